@@ -1,6 +1,9 @@
 package api
 
-import pb "github.com/Grivn/libfalanx/zcommon/protos"
+import (
+	"github.com/Grivn/libfalanx/txcontainer/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
 
 type ModuleControl interface {
 	Start()
@@ -10,12 +13,44 @@ type ModuleControl interface {
 // TxsContainer is only used to receive the transactions and their status
 // we don't need to maintain the order here and it is only a container for transactions
 // no duplicated transactions
+// Add:     store the transaction from particular client, it could be rejected because of the capacity or quota
+// Pin:     protect the transaction referenced by a paved batch from eviction until it has been removed
+// Unpin:   drop the protection of a transaction which won't be executed with the paved batch, such as the one
+//          which has been executed by state transfer
+// Metrics: the statistics of the container
 type TxsContainer interface {
-	Add(tx *pb.Transaction)
+	Add(client uint64, tx *pb.Transaction) error
 	Get(txHash string) *pb.Transaction
 	Remove(txHash string) error
+	Pin(txHash string)
+	Unpin(txHash string)
+	Metrics() types.Metrics
+}
+
+// Checkpoint is used to agree on the executed order with other replicas
+// StableCheckpoint: the latest checkpoint generated by 2f+1 replicas with the same digest, nil if there
+//                   isn't any stable checkpoint yet
+type Checkpoint interface {
+	ModuleControl
+	StableCheckpoint() *pb.StableCheckpoint
+}
+
+// ClientOrder is used to order the requests from particular client
+// Evict: stop the instance if it is idle and return the progress of its client, so that the instance created
+//        for its later requests could be resumed from it. ok is false if the instance is still busy
+type ClientOrder interface {
+	ModuleControl
+	Evict() (sequence uint64, timestamp int64, ok bool)
 }
 
 type ForwardClient interface {
+	ModuleControl
 	ProposeTxs(txs []*pb.Transaction)
 }
+
+// Executor is the state machine supplied by the application
+// the payloads of every finished batch will be delivered to it according to the order generated by falanx,
+// and the results will be replied to the clients, results[i] is the result of value[i]
+type Executor interface {
+	Execute(value [][]byte) [][]byte
+}
@@ -0,0 +1,29 @@
+package checkpoint
+
+import (
+	"github.com/Grivn/libfalanx/checkpoint/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+func NewCheckpointProcessor(c types.Config) *checkpointImpl {
+	return newCheckpointImpl(c)
+}
+
+func (cp *checkpointImpl) Start() {
+	cp.start()
+}
+
+func (cp *checkpointImpl) Stop() {
+	cp.stop()
+}
+
+// StableCheckpoint is used to read the latest stable checkpoint with the proofs from 2f+1 replicas,
+// it returns nil if there isn't any stable checkpoint yet
+func (cp *checkpointImpl) StableCheckpoint() *pb.StableCheckpoint {
+	return cp.stableCheckpoint()
+}
+
+// Metrics is used to inspect the amount of states kept by checkpoint processor
+func (cp *checkpointImpl) Metrics() types.Metrics {
+	return cp.query()
+}
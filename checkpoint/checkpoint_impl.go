@@ -0,0 +1,522 @@
+package checkpoint
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/Grivn/libfalanx/checkpoint/types"
+	"github.com/Grivn/libfalanx/checkpoint/utils"
+	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+type checkpointImpl struct {
+	id uint64
+
+	// quorum is the amount of matching checkpoints to make a checkpoint stable, which is 2f+1
+	n        int
+	f        int
+	quorum   int
+	interval uint64
+
+	// replicas is the replica set of current epoch, and epoch is the new one which will be switched to once
+	// the batch completing the reconfiguration has been processed
+	replicas map[uint64]bool
+	epoch    *tp.EpochEvent
+
+	// seqNo is the latest executed batch, and digest is the chained digest of the executed order up to it:
+	// digest(k) = hash(digest(k-1), tx hashes of batch k, k)
+	seqNo  uint64
+	digest string
+
+	// recorder ====================================================================
+	// local:    the digests of the checkpoints generated by current replica above the stable one
+	// votes:    the checkpoints from every replica above the stable one, replica id ==> seq ==> checkpoint
+	// executed: the executed batches which haven't been covered by the stable checkpoint
+	local    map[uint64]string
+	votes    map[uint64]map[uint64]*pb.Checkpoint
+	executed []tp.ExecuteEvent
+
+	// stable is the latest stable checkpoint, it is read by the application so that it is protected by lock
+	stable *pb.StableCheckpoint
+	lock   sync.RWMutex
+
+	// state transfer ==============================================================
+	// history:      the executed batches covered by the stable checkpoint, which are kept to serve the lagging
+	//               replicas
+	// ahead:        the highest checkpoint above the window from every replica, current replica is lagging
+	//               once f+1 replicas are ahead of it
+	// transferring: whether current replica is waiting for the state response
+	// pending:      the stable checkpoint fetched from other replicas, it will be installed once the batches
+	//               covered by it have been executed
+	history      []tp.ExecuteEvent
+	ahead        map[uint64]uint64
+	transferring bool
+	pending      *pb.StableCheckpoint
+
+	// persistence =================================================================
+	// store: persist the states to resume from after restart, nil if the persistence is disabled
+	// dirty: whether the states have been changed since the latest snapshot
+	store utils.SnapshotStore
+	dirty bool
+
+	// channel =====================================================================
+	// executedC: receive the batches executed by current replica
+	// recvC:     receive the checkpoints from other replicas
+	// stableC:   post the executed batches covered by the stable checkpoint
+	// executorC: post the sequence number of the stable checkpoint to executor
+	// fetchC:    receive the state fetch requests from the lagging replicas
+	// responseC: receive the state responses from other replicas
+	// transferC: post the batches fetched by state transfer to executor
+	// filterC:   post the batches fetched by state transfer to filter
+	// dagC:      post the batches fetched by state transfer to DAG manager
+	// epochC:    receive the replicas of new epoch
+	executedC chan tp.ExecuteEvent
+	recvC     chan *pb.Checkpoint
+	stableC   chan tp.ExecuteEvent
+	executorC chan uint64
+	fetchC    chan *pb.StateFetch
+	responseC chan *pb.StateResponse
+	transferC chan tp.TransferEvent
+	filterC   chan tp.TransferEvent
+	dagC      chan tp.TransferEvent
+	epochC    chan tp.EpochEvent
+	timeoutC  chan uint64
+	close     chan bool
+
+	// metricsC is used to receive the queries for the amount of states kept by checkpoint processor
+	metricsC chan chan types.Metrics
+
+	network network.Network
+	tools   zcommon.Tools
+	logger  logger.Logger
+}
+
+func newCheckpointImpl(c types.Config) *checkpointImpl {
+	f := int(math.Floor((float64(c.N) - 1) / 4))
+	if f == 0 {
+		f = 1
+	}
+	interval := c.Interval
+	if interval == 0 {
+		interval = types.DefaultInterval
+	}
+	replicas := make(map[uint64]bool)
+	for i := 1; i <= c.N; i++ {
+		replicas[uint64(i)] = true
+	}
+
+	cp := &checkpointImpl{
+		id:        c.ID,
+		n:         c.N,
+		f:         f,
+		quorum:    2*f + 1,
+		interval:  interval,
+		replicas:  replicas,
+		local:     make(map[uint64]string),
+		votes:     make(map[uint64]map[uint64]*pb.Checkpoint),
+		ahead:     make(map[uint64]uint64),
+		executedC: c.ExecutedC,
+		recvC:     c.RecvC,
+		stableC:   c.StableC,
+		executorC: c.ExecutorC,
+		fetchC:    c.FetchC,
+		responseC: c.ResponseC,
+		transferC: c.TransferC,
+		filterC:   c.FilterC,
+		dagC:      c.DAGC,
+		epochC:    c.EpochC,
+		timeoutC:  make(chan uint64),
+		close:     make(chan bool),
+		metricsC:  make(chan chan types.Metrics),
+		store:     c.Store,
+		network:   c.Network,
+		tools:     c.Tools,
+		logger:    c.Logger,
+	}
+	cp.restore(c)
+	return cp
+}
+
+// restore is used to resume the chain of digest from the latest executed batch before restart, the executed
+// batches covered by the stable checkpoint are kept as history, and the local checkpoints above it are
+// generated once again so that they could become stable with the ones from other replicas
+func (cp *checkpointImpl) restore(c types.Config) {
+	if c.Seq == 0 {
+		return
+	}
+	cp.seqNo = c.Seq
+	cp.digest = c.Digest
+	cp.stable = c.Stable
+
+	low, digest := uint64(0), ""
+	if c.Stable != nil {
+		low, digest = c.Stable.Seq, c.Stable.Digest
+	}
+	for _, event := range c.Batches {
+		if event.Seq > c.Seq {
+			break
+		}
+		if event.Seq <= low {
+			cp.history = append(cp.history, event)
+			continue
+		}
+		digest = cp.chain(digest, event)
+		cp.executed = append(cp.executed, event)
+		if event.Seq%cp.interval == 0 {
+			cp.local[event.Seq] = digest
+		}
+	}
+	if low+uint64(len(cp.executed)) != c.Seq || digest != c.Digest {
+		cp.logger.Errorf("[CHECKPOINT] cannot restore the checkpoints from stable checkpoint %d to batch %d, digest %s", low, c.Seq, c.Digest)
+		cp.executed = nil
+		cp.local = make(map[uint64]string)
+	}
+	cp.logger.Infof("[CHECKPOINT] resume from batch %d, digest %s, stable checkpoint %d", cp.seqNo, cp.digest, low)
+}
+
+// persist is used to save the chain of digest with the executed batches kept by checkpoint processor, once
+// they have been changed
+func (cp *checkpointImpl) persist() {
+	if cp.store == nil || !cp.dirty {
+		return
+	}
+	cp.dirty = false
+
+	snapshot := &pb.CheckpointSnapshot{
+		Seq:    cp.seqNo,
+		Digest: cp.digest,
+		Stable: cp.stableCheckpoint(),
+	}
+	for _, events := range [][]tp.ExecuteEvent{cp.history, cp.executed} {
+		for _, event := range events {
+			snapshot.Batches = append(snapshot.Batches, &pb.ExecutedBatch{Seq: event.Seq, TxHashes: event.TxHashes})
+		}
+	}
+	if err := cp.store.Save(snapshot); err != nil {
+		cp.logger.Errorf("[CHECKPOINT] persist snapshot of batch %d failed: %s", cp.seqNo, err)
+	}
+}
+
+func (cp *checkpointImpl) start() {
+	go cp.listener()
+}
+
+func (cp *checkpointImpl) stop() {
+	close(cp.close)
+}
+
+func (cp *checkpointImpl) listener() {
+	for {
+		select {
+		case <-cp.close:
+			return
+
+		case event := <-cp.executedC:
+			cp.processExecuted(event)
+
+		case checkpoint := <-cp.recvC:
+			if cp.record(checkpoint) {
+				cp.checkStable(checkpoint.Seq)
+			}
+			cp.checkLagging()
+
+		case fetch := <-cp.fetchC:
+			cp.serveFetch(fetch)
+
+		case response := <-cp.responseC:
+			cp.receiveState(response)
+
+		case seq := <-cp.timeoutC:
+			cp.transferTimeout(seq)
+
+		case event := <-cp.epochC:
+			cp.epoch = &event
+			cp.switchEpoch()
+
+		case replyC := <-cp.metricsC:
+			replyC <- cp.metrics()
+		}
+		cp.persist()
+	}
+}
+
+// query is used to inspect the amount of states kept by checkpoint processor, the query is answered by the
+// listener so that it won't race with the processing of checkpoints. it returns the empty metrics once the
+// processor has been stopped.
+func (cp *checkpointImpl) query() types.Metrics {
+	replyC := make(chan types.Metrics, 1)
+	select {
+	case cp.metricsC <- replyC:
+	case <-cp.close:
+		return types.Metrics{}
+	}
+	select {
+	case metrics := <-replyC:
+		return metrics
+	case <-cp.close:
+		return types.Metrics{}
+	}
+}
+
+func (cp *checkpointImpl) metrics() types.Metrics {
+	metrics := types.Metrics{
+		Local:    len(cp.local),
+		Ahead:    len(cp.ahead),
+		Executed: len(cp.executed),
+		History:  len(cp.history),
+	}
+	for _, votes := range cp.votes {
+		metrics.Votes += len(votes)
+	}
+	return metrics
+}
+
+func (cp *checkpointImpl) stableCheckpoint() *pb.StableCheckpoint {
+	cp.lock.RLock()
+	defer cp.lock.RUnlock()
+	return cp.stable
+}
+
+func (cp *checkpointImpl) stableSeq() uint64 {
+	cp.lock.RLock()
+	defer cp.lock.RUnlock()
+	if cp.stable == nil {
+		return 0
+	}
+	return cp.stable.Seq
+}
+
+// processExecuted is used to extend the digest with the executed batch, and a checkpoint will be generated
+// and broadcast every interval batches
+func (cp *checkpointImpl) processExecuted(event tp.ExecuteEvent) {
+	if event.Seq != cp.seqNo+1 {
+		cp.logger.Warningf("[CHECKPOINT] unexpected executed batch %d, expect %d", event.Seq, cp.seqNo+1)
+		return
+	}
+	cp.seqNo = event.Seq
+	cp.digest = cp.chain(cp.digest, event)
+	cp.executed = append(cp.executed, event)
+	cp.dirty = true
+	cp.installPending()
+
+	if event.Seq%cp.interval != 0 {
+		cp.switchEpoch()
+		return
+	}
+	cp.local[event.Seq] = cp.digest
+
+	checkpoint := &pb.Checkpoint{
+		ReplicaId: cp.id,
+		Seq:       event.Seq,
+		Digest:    cp.digest,
+	}
+	if err := zcommon.SignCheckpoint(cp.tools, checkpoint); err != nil {
+		cp.logger.Errorf("[CHECKPOINT] sign checkpoint %d failed: %s", event.Seq, err)
+		return
+	}
+	payload, err := proto.Marshal(checkpoint)
+	if err != nil {
+		cp.logger.Errorf("[CHECKPOINT] marshal checkpoint %d failed: %s", event.Seq, err)
+		return
+	}
+	cp.network.Broadcast(&pb.ConsensusMessage{Type: pb.Type_CHECKPOINT, Payload: payload})
+	cp.logger.Infof("[CHECKPOINT] broadcast checkpoint %d, digest %s", event.Seq, cp.digest)
+
+	cp.record(checkpoint)
+	cp.checkStable(event.Seq)
+	cp.switchEpoch()
+}
+
+// record is used to store the checkpoint from particular replica, only the first one for every sequence
+// number will be accepted, and the ones out of window will be ignored to bound the memory
+func (cp *checkpointImpl) record(checkpoint *pb.Checkpoint) bool {
+	if !cp.replicas[checkpoint.ReplicaId] && !cp.joining(checkpoint.ReplicaId) {
+		cp.logger.Debugf("[CHECKPOINT] ignore checkpoint %d from replica %d, which is not in current epoch", checkpoint.Seq, checkpoint.ReplicaId)
+		return false
+	}
+
+	low := cp.stableSeq()
+	high := cp.high()
+	if checkpoint.Seq > high && checkpoint.Seq%cp.interval == 0 && checkpoint.Seq > cp.ahead[checkpoint.ReplicaId] {
+		cp.ahead[checkpoint.ReplicaId] = checkpoint.Seq
+	}
+
+	if checkpoint.Seq <= low || checkpoint.Seq > high || checkpoint.Seq%cp.interval != 0 {
+		cp.logger.Debugf("[CHECKPOINT] ignore checkpoint %d from replica %d, window (%d, %d]", checkpoint.Seq, checkpoint.ReplicaId, low, high)
+		return false
+	}
+
+	votes, ok := cp.votes[checkpoint.ReplicaId]
+	if !ok {
+		votes = make(map[uint64]*pb.Checkpoint)
+		cp.votes[checkpoint.ReplicaId] = votes
+	}
+	if _, ok := votes[checkpoint.Seq]; ok {
+		cp.logger.Debugf("[CHECKPOINT] duplicated checkpoint %d from replica %d", checkpoint.Seq, checkpoint.ReplicaId)
+		return false
+	}
+	votes[checkpoint.Seq] = checkpoint
+	return true
+}
+
+// switchEpoch is used to switch to the replicas of new epoch once the batch completing the reconfiguration
+// has been processed, the checkpoints from the removed replicas will be dropped
+func (cp *checkpointImpl) switchEpoch() {
+	if cp.epoch == nil || cp.seqNo < cp.epoch.Seq {
+		return
+	}
+	event := cp.epoch
+	cp.epoch = nil
+
+	cp.n = len(event.Replicas)
+	cp.f = int(math.Floor((float64(cp.n) - 1) / 4))
+	if cp.f == 0 {
+		cp.f = 1
+	}
+	cp.quorum = 2*cp.f + 1
+	cp.replicas = make(map[uint64]bool)
+	for _, id := range event.Replicas {
+		cp.replicas[uint64(id)] = true
+	}
+	for id := range cp.votes {
+		if !cp.replicas[id] {
+			delete(cp.votes, id)
+		}
+	}
+	for id := range cp.ahead {
+		if !cp.replicas[id] {
+			delete(cp.ahead, id)
+		}
+	}
+	cp.logger.Infof("[CHECKPOINT] switch to epoch %d after batch %d, replicas %v", event.Epoch, event.Seq, event.Replicas)
+}
+
+// joining is used to check whether the replica will join in the epoch which has not been switched to
+func (cp *checkpointImpl) joining(id uint64) bool {
+	if cp.epoch == nil {
+		return false
+	}
+	for _, replica := range cp.epoch.Replicas {
+		if uint64(replica) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// high is used to calculate the upper bound of the window for the checkpoints to accept
+func (cp *checkpointImpl) high() uint64 {
+	high := cp.seqNo
+	if stable := cp.stableSeq(); stable > high {
+		high = stable
+	}
+	return high + cp.interval*types.DefaultWindow
+}
+
+// chain is used to extend the digest of executed order with the next batch
+func (cp *checkpointImpl) chain(digest string, event tp.ExecuteEvent) string {
+	return cp.tools.CalculateHash(append([]string{digest}, event.TxHashes...), int64(event.Seq))
+}
+
+// checkStable is used to check whether 2f+1 replicas have generated the same checkpoint as current replica
+// for particular sequence number
+func (cp *checkpointImpl) checkStable(seq uint64) {
+	digest, ok := cp.local[seq]
+	if !ok {
+		return
+	}
+
+	var proofs []*pb.Checkpoint
+	counter := make(map[string]int)
+	for _, votes := range cp.votes {
+		checkpoint, ok := votes[seq]
+		if !ok {
+			continue
+		}
+		counter[checkpoint.Digest]++
+		if checkpoint.Digest == digest {
+			proofs = append(proofs, checkpoint)
+		}
+	}
+
+	if len(proofs) < cp.quorum {
+		total := 0
+		for other, count := range counter {
+			total += count
+			if other != digest && count >= cp.quorum {
+				cp.logger.Errorf("[CHECKPOINT] local digest %s of checkpoint %d diverges from %s agreed by %d replicas", digest, seq, other, count)
+			}
+		}
+		if total == cp.n && len(counter) > 1 {
+			cp.logger.Warningf("[CHECKPOINT] checkpoint %d cannot become stable, the replicas have generated %d different digests", seq, len(counter))
+		}
+		return
+	}
+
+	sort.Slice(proofs, func(i, j int) bool { return proofs[i].ReplicaId < proofs[j].ReplicaId })
+	cp.moveStable(&pb.StableCheckpoint{Seq: seq, Digest: digest, Proofs: proofs})
+}
+
+// moveStable is used to update the stable checkpoint, the checkpoints below it will be dropped and the
+// executed batches covered by it will be posted one by one
+func (cp *checkpointImpl) moveStable(stable *pb.StableCheckpoint) {
+	cp.lock.Lock()
+	cp.stable = stable
+	cp.lock.Unlock()
+	cp.logger.Infof("[CHECKPOINT] checkpoint %d has become stable, digest %s", stable.Seq, stable.Digest)
+
+	for seq := range cp.local {
+		if seq <= stable.Seq {
+			delete(cp.local, seq)
+		}
+	}
+	for _, votes := range cp.votes {
+		for seq := range votes {
+			if seq <= stable.Seq {
+				delete(votes, seq)
+			}
+		}
+	}
+
+	index := 0
+	for ; index < len(cp.executed) && cp.executed[index].Seq <= stable.Seq; index++ {
+		if cp.stableC == nil {
+			continue
+		}
+		select {
+		case cp.stableC <- cp.executed[index]:
+		case <-cp.close:
+			return
+		}
+	}
+	cp.history = append(cp.history, cp.executed[:index]...)
+	cp.executed = cp.executed[index:]
+	cp.dirty = true
+
+	// only the batches of latest DefaultHistory intervals are kept, which are copied so that the pruned ones
+	// could be released
+	if stable.Seq > cp.interval*types.DefaultHistory {
+		low := stable.Seq - cp.interval*types.DefaultHistory
+		index = sort.Search(len(cp.history), func(i int) bool { return cp.history[i].Seq > low })
+		if index > 0 {
+			cp.history = append([]tp.ExecuteEvent(nil), cp.history[index:]...)
+		}
+	}
+
+	// the sequence number is cumulative, so that the executor will catch up with the next one if it has
+	// missed this notification
+	if cp.executorC != nil {
+		select {
+		case cp.executorC <- stable.Seq:
+		default:
+			cp.logger.Debugf("[CHECKPOINT] executor is busy, skip notifying it of stable checkpoint %d", stable.Seq)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package checkpoint
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Grivn/libfalanx/checkpoint/types"
+	"github.com/Grivn/libfalanx/checkpoint/utils"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// testLogger drops the logs, the failures are reported by the tests themselves
+type testLogger struct{}
+
+func (testLogger) Debug(v ...interface{})                   {}
+func (testLogger) Debugf(format string, v ...interface{})   {}
+func (testLogger) Info(v ...interface{})                    {}
+func (testLogger) Infof(format string, v ...interface{})    {}
+func (testLogger) Warning(v ...interface{})                 {}
+func (testLogger) Warningf(format string, v ...interface{}) {}
+func (testLogger) Error(v ...interface{})                   {}
+func (testLogger) Errorf(format string, v ...interface{})   {}
+
+// recordNetwork records the state responses unicast by the processor
+type recordNetwork struct {
+	responses []*pb.StateResponse
+}
+
+func (n *recordNetwork) Broadcast(msg *pb.ConsensusMessage) {}
+func (n *recordNetwork) Unicast(to uint64, msg *pb.ConsensusMessage) {
+	response := &pb.StateResponse{}
+	if err := response.Unmarshal(msg.Payload); err == nil {
+		n.responses = append(n.responses, response)
+	}
+}
+
+func newTestConfig(t *testing.T, interval uint64) (types.Config, *recordNetwork) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := zcommon.NewEd25519Signer(private, map[uint64]ed25519.PublicKey{1: public})
+	network := &recordNetwork{}
+	return types.Config{
+		ID:       1,
+		N:        4,
+		Interval: interval,
+		Network:  network,
+		Tools:    zcommon.NewTools(pb.HashAlgorithm_HASH_UNSPECIFIED, signer),
+		Logger:   testLogger{},
+	}, network
+}
+
+// execute is used to process the executed batches up to seq, and the checkpoints of replica 2 and 3 make the
+// local ones up to stable become stable
+func execute(cp *checkpointImpl, seq uint64, stable uint64) {
+	for next := cp.seqNo + 1; next <= seq; next++ {
+		cp.processExecuted(tp.ExecuteEvent{Seq: next, TxHashes: []string{fmt.Sprintf("tx-%d", next)}})
+		if next > stable || next%cp.interval != 0 {
+			continue
+		}
+		for _, id := range []uint64{2, 3} {
+			cp.record(&pb.Checkpoint{ReplicaId: id, Seq: next, Digest: cp.local[next]})
+		}
+		cp.checkStable(next)
+	}
+}
+
+// TestRestoreChain persists the processor with stable checkpoint 4 and executed batch 7, the restored one
+// should extend the same chain of digest and generate the local checkpoint above the stable one once again
+func TestRestoreChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "falanx-checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := utils.NewSnapshotStore(filepath.Join(dir, tp.CheckpointSnapshot))
+
+	c, _ := newTestConfig(t, 2)
+	c.Store = store
+	origin := newCheckpointImpl(c)
+	execute(origin, 7, 4)
+	origin.persist()
+
+	snapshot, err := store.Load()
+	if err != nil || snapshot == nil {
+		t.Fatalf("load snapshot failed: %v", err)
+	}
+	c.Seq, c.Digest, c.Stable = snapshot.Seq, snapshot.Digest, snapshot.Stable
+	for _, batch := range snapshot.Batches {
+		c.Batches = append(c.Batches, tp.ExecuteEvent{Seq: batch.Seq, TxHashes: batch.TxHashes})
+	}
+	restored := newCheckpointImpl(c)
+
+	if restored.seqNo != 7 || restored.digest != origin.digest || restored.stableSeq() != 4 {
+		t.Fatalf("restore batch %d, stable checkpoint %d, expect batch 7, stable checkpoint 4", restored.seqNo, restored.stableSeq())
+	}
+	if len(restored.history) != 4 || len(restored.executed) != 3 || restored.local[6] != origin.local[6] {
+		t.Fatalf("restore history %d, executed %d, local %v", len(restored.history), len(restored.executed), restored.local)
+	}
+
+	execute(origin, 8, 8)
+	execute(restored, 8, 8)
+	if restored.digest != origin.digest || restored.stableSeq() != 8 {
+		t.Fatalf("restored processor diverges in batch 8, stable checkpoint %d", restored.stableSeq())
+	}
+}
+
+// TestPruneHistory makes the checkpoints stable up to batch 40, only the batches of the latest DefaultHistory
+// intervals should be kept, and the lagging replicas could only be served from them
+func TestPruneHistory(t *testing.T) {
+	c, network := newTestConfig(t, 1)
+	cp := newCheckpointImpl(c)
+	execute(cp, 40, 40)
+
+	if len(cp.history) != types.DefaultHistory || cp.history[0].Seq != 40-types.DefaultHistory+1 {
+		t.Fatalf("keep %d batches in history from batch %d", len(cp.history), cp.history[0].Seq)
+	}
+
+	cp.serveFetch(&pb.StateFetch{ReplicaId: 2, Seq: 10})
+	if len(network.responses) != 0 {
+		t.Fatalf("serve state fetch from pruned batch 11")
+	}
+	cp.serveFetch(&pb.StateFetch{ReplicaId: 2, Seq: 30})
+	if len(network.responses) != 1 {
+		t.Fatalf("cannot serve state fetch from batch 31")
+	}
+	batches := network.responses[0].Batches
+	if len(batches) != 10 || batches[0].Seq != 31 || batches[9].Seq != 40 {
+		t.Fatalf("reply %d batches from batch 31 to 40", len(batches))
+	}
+}
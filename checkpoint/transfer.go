@@ -0,0 +1,197 @@
+package checkpoint
+
+import (
+	"time"
+
+	"github.com/Grivn/libfalanx/checkpoint/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// checkLagging is used to check whether f+1 replicas have generated the checkpoints above the window, which
+// means that current replica has fallen behind, and the state will be fetched from other replicas
+func (cp *checkpointImpl) checkLagging() {
+	if cp.transferring || cp.pending != nil {
+		return
+	}
+
+	high := cp.high()
+	for id, seq := range cp.ahead {
+		if seq <= high {
+			delete(cp.ahead, id)
+		}
+	}
+	if len(cp.ahead) < cp.f+1 {
+		return
+	}
+	cp.fetchState()
+}
+
+// fetchState is used to broadcast the state fetch request with the latest executed batch of current replica
+func (cp *checkpointImpl) fetchState() {
+	fetch := &pb.StateFetch{
+		ReplicaId: cp.id,
+		Seq:       cp.seqNo,
+	}
+	payload, err := proto.Marshal(fetch)
+	if err != nil {
+		cp.logger.Errorf("[CHECKPOINT] marshal state fetch failed: %s", err)
+		return
+	}
+	cp.network.Broadcast(&pb.ConsensusMessage{Type: pb.Type_STATE_FETCH, Payload: payload})
+	cp.logger.Infof("[CHECKPOINT] %d replicas are ahead, fetch state from batch %d", len(cp.ahead), cp.seqNo+1)
+
+	cp.transferring = true
+	cp.startTransferTimer(cp.seqNo)
+}
+
+func (cp *checkpointImpl) startTransferTimer(seq uint64) {
+	go func() {
+		select {
+		case <-cp.close:
+		case <-time.After(types.DefaultTransferTimeout):
+			select {
+			case <-cp.close:
+			case cp.timeoutC <- seq:
+			}
+		}
+	}()
+}
+
+// transferTimeout is used to fetch the state once again if there isn't any valid response
+func (cp *checkpointImpl) transferTimeout(seq uint64) {
+	if !cp.transferring {
+		return
+	}
+	cp.logger.Warningf("[CHECKPOINT] state fetch from batch %d timeout", seq+1)
+	cp.transferring = false
+	cp.checkLagging()
+}
+
+// serveFetch is used to reply the latest stable checkpoint and the executed batches covered by it, which
+// follow the latest executed batch of the lagging replica
+func (cp *checkpointImpl) serveFetch(fetch *pb.StateFetch) {
+	if fetch.ReplicaId == cp.id {
+		return
+	}
+	stable := cp.stableCheckpoint()
+	if stable == nil || stable.Seq <= fetch.Seq {
+		cp.logger.Debugf("[CHECKPOINT] cannot serve state fetch from replica %d, batch %d", fetch.ReplicaId, fetch.Seq)
+		return
+	}
+	// the history has been pruned to the latest DefaultHistory intervals, which starts from the first one kept
+	if len(cp.history) == 0 || cp.history[len(cp.history)-1].Seq != stable.Seq {
+		cp.logger.Warningf("[CHECKPOINT] missing history for stable checkpoint %d", stable.Seq)
+		return
+	}
+	first := cp.history[0].Seq
+	if fetch.Seq+1 < first {
+		cp.logger.Warningf("[CHECKPOINT] cannot serve state fetch from replica %d, batch %d, history has been pruned to batch %d", fetch.ReplicaId, fetch.Seq, first)
+		return
+	}
+
+	response := &pb.StateResponse{
+		ReplicaId:  cp.id,
+		Checkpoint: stable,
+	}
+	for _, event := range cp.history[fetch.Seq+1-first:] {
+		response.Batches = append(response.Batches, &pb.ExecutedBatch{Seq: event.Seq, TxHashes: event.TxHashes})
+	}
+	payload, err := proto.Marshal(response)
+	if err != nil {
+		cp.logger.Errorf("[CHECKPOINT] marshal state response failed: %s", err)
+		return
+	}
+	cp.network.Unicast(fetch.ReplicaId, &pb.ConsensusMessage{Type: pb.Type_STATE_RESPONSE, Payload: payload})
+	cp.logger.Infof("[CHECKPOINT] reply state from batch %d to %d to replica %d", fetch.Seq+1, stable.Seq, fetch.ReplicaId)
+}
+
+// receiveState is used to verify the state response, the stable checkpoint should be proved by 2f+1 replicas,
+// and the batches should extend the digest of current replica to the one of stable checkpoint. the batches
+// will be posted to executor, filter and DAG manager, and the checkpoint will be installed once they have been executed
+func (cp *checkpointImpl) receiveState(response *pb.StateResponse) {
+	if !cp.transferring || cp.pending != nil {
+		return
+	}
+	stable := response.Checkpoint
+	if stable == nil || stable.Seq <= cp.seqNo {
+		return
+	}
+	if !cp.verifyStable(stable) {
+		cp.logger.Warningf("[CHECKPOINT] invalid stable checkpoint %d from replica %d", stable.Seq, response.ReplicaId)
+		return
+	}
+
+	digest := cp.digest
+	expect := cp.seqNo + 1
+	var batches []tp.ExecuteEvent
+	for _, batch := range response.Batches {
+		if batch == nil || batch.Seq < expect {
+			continue
+		}
+		if batch.Seq != expect {
+			cp.logger.Warningf("[CHECKPOINT] invalid state from replica %d, batch %d, expect %d", response.ReplicaId, batch.Seq, expect)
+			return
+		}
+		event := tp.ExecuteEvent{Seq: batch.Seq, TxHashes: batch.TxHashes}
+		digest = cp.chain(digest, event)
+		batches = append(batches, event)
+		expect++
+	}
+	if expect != stable.Seq+1 || digest != stable.Digest {
+		cp.logger.Warningf("[CHECKPOINT] state from replica %d cannot extend batch %d to stable checkpoint %d", response.ReplicaId, cp.seqNo, stable.Seq)
+		return
+	}
+
+	cp.logger.Infof("[CHECKPOINT] transfer state from batch %d to %d, replica %d", cp.seqNo+1, stable.Seq, response.ReplicaId)
+	cp.transferring = false
+	cp.pending = stable
+
+	event := tp.TransferEvent{Seq: stable.Seq, Batches: batches}
+	for _, transferC := range []chan tp.TransferEvent{cp.transferC, cp.filterC, cp.dagC} {
+		if transferC == nil {
+			continue
+		}
+		select {
+		case transferC <- event:
+		case <-cp.close:
+			return
+		}
+	}
+}
+
+// verifyStable is used to check the proofs of stable checkpoint, whose signatures have been verified
+func (cp *checkpointImpl) verifyStable(stable *pb.StableCheckpoint) bool {
+	if stable.Seq%cp.interval != 0 {
+		return false
+	}
+	replicas := make(map[uint64]bool)
+	for _, proof := range stable.Proofs {
+		if proof == nil || proof.Seq != stable.Seq || proof.Digest != stable.Digest {
+			return false
+		}
+		if !cp.replicas[proof.ReplicaId] {
+			return false
+		}
+		replicas[proof.ReplicaId] = true
+	}
+	return len(replicas) >= cp.quorum
+}
+
+// installPending is used to install the stable checkpoint fetched by state transfer, once the batches covered
+// by it have been executed
+func (cp *checkpointImpl) installPending() {
+	if cp.pending == nil || cp.seqNo < cp.pending.Seq {
+		return
+	}
+	pending := cp.pending
+	cp.pending = nil
+
+	if cp.seqNo != pending.Seq || cp.digest != pending.Digest {
+		cp.logger.Errorf("[CHECKPOINT] executed order diverges from the transferred checkpoint %d, digest %s", pending.Seq, pending.Digest)
+		return
+	}
+	cp.moveStable(pending)
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"time"
+
+	"github.com/Grivn/libfalanx/checkpoint/utils"
+	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// Config is used to initiate the checkpoint processor
+// Interval:  a checkpoint will be generated every Interval executed batches, DefaultInterval will be used
+//            if it is not positive
+// ExecutedC: receive the batches executed by current replica
+// RecvC:     receive the checkpoints from other replicas, whose signature has been verified
+// StableC:   post the executed batches once they have been covered by a stable checkpoint, it could be nil
+//            if they are not concerned
+// ExecutorC: post the sequence number of the stable checkpoint to executor, the notification will be skipped
+//            instead of blocking when executor is busy, it could be nil if they are not concerned
+// FetchC:    receive the state fetch requests from the lagging replicas
+// ResponseC: receive the stable checkpoint and the executed batches covered by it from other replicas
+// TransferC: post the batches fetched by state transfer to executor, it could be nil if the state
+//            transfer is disabled
+// FilterC:   post the batches fetched by state transfer to filter, so that it could skip them
+// DAGC:      post the batches fetched by state transfer to DAG manager, so that the pending pairs of the
+//            transactions in them won't block the later batches
+// EpochC:    receive the replicas of new epoch, it could be nil if the replica set is fixed
+// Seq:       the latest batch executed before restart, the chain of digest will be extended from it
+// Digest:    the digest of executed order up to Seq
+// Stable:    the latest stable checkpoint before restart, it could be nil
+// Batches:   the executed batches persisted before restart, the ones covered by Stable are kept to serve the
+//            lagging replicas
+// Store:     persist the states to resume from after restart, it could be nil if the persistence is disabled
+// Network:   broadcast the checkpoints and state fetch requests of current replica
+type Config struct {
+	ID        uint64
+	N         int
+	Interval  uint64
+	Seq       uint64
+	Digest    string
+	Stable    *pb.StableCheckpoint
+	Batches   []tp.ExecuteEvent
+	Store     utils.SnapshotStore
+	ExecutedC chan tp.ExecuteEvent
+	RecvC     chan *pb.Checkpoint
+	StableC   chan tp.ExecuteEvent
+	ExecutorC chan uint64
+	FetchC    chan *pb.StateFetch
+	ResponseC chan *pb.StateResponse
+	TransferC chan tp.TransferEvent
+	FilterC   chan tp.TransferEvent
+	DAGC      chan tp.TransferEvent
+	EpochC    chan tp.EpochEvent
+	Network   network.Network
+	Tools     zcommon.Tools
+	Logger    logger.Logger
+}
+
+const (
+	// DefaultInterval is the default amount of executed batches between two checkpoints
+	DefaultInterval = 10
+
+	// DefaultWindow is the amount of checkpoints above the stable one we would like to keep for every
+	// replica, the ones out of the window will be ignored
+	DefaultWindow = 4
+
+	// DefaultHistory is the amount of intervals below the stable checkpoint whose executed batches are kept to
+	// serve the lagging replicas, the replicas lagging behind them cannot be served any more
+	DefaultHistory = 16
+
+	// DefaultTransferTimeout is the duration to wait for the state response before fetching it once again
+	DefaultTransferTimeout = 2 * time.Second
+)
+
+// Metrics is the amount of states kept by checkpoint processor
+// Local:    the checkpoints generated by current replica above the stable one
+// Votes:    the checkpoints from all the replicas above the stable one
+// Ahead:    the replicas which have generated the checkpoints above the window
+// Executed: the executed batches which haven't been covered by the stable checkpoint
+// History:  the executed batches covered by the stable checkpoint, which are kept to serve the lagging replicas
+type Metrics struct {
+	Local    int
+	Votes    int
+	Ahead    int
+	Executed int
+	History  int
+}
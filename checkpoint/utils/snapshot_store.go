@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// SnapshotStore is used to persist the latest snapshot of checkpoint processor, which is taken once a batch
+// has been executed, so that the replica could resume the chain of digest after restart.
+type SnapshotStore interface {
+	Save(snapshot *pb.CheckpointSnapshot) error
+	Load() (*pb.CheckpointSnapshot, error)
+}
+
+func NewSnapshotStore(path string) *snapshotStoreImpl {
+	return newSnapshotStoreImpl(path)
+}
+
+func (s *snapshotStoreImpl) Save(snapshot *pb.CheckpointSnapshot) error {
+	return s.save(snapshot)
+}
+
+func (s *snapshotStoreImpl) Load() (*pb.CheckpointSnapshot, error) {
+	return s.load()
+}
+
+type snapshotStoreImpl struct {
+	path string
+}
+
+func newSnapshotStoreImpl(path string) *snapshotStoreImpl {
+	return &snapshotStoreImpl{path: path}
+}
+
+// save writes the snapshot into a temporary file and renames it, so that the previous snapshot could
+// still be loaded if the replica crashes during writing.
+func (s *snapshotStoreImpl) save(snapshot *pb.CheckpointSnapshot) error {
+	payload, err := snapshot.Marshal()
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(payload); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+
+	// sync the directory to make the rename durable
+	dir, err := os.Open(filepath.Dir(s.path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+// load reads the latest snapshot, and it returns nil if there isn't any snapshot.
+func (s *snapshotStoreImpl) load() (*pb.CheckpointSnapshot, error) {
+	payload, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &pb.CheckpointSnapshot{}
+	if err := snapshot.Unmarshal(payload); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
@@ -12,4 +12,7 @@ func (c *clientOrderImpl) Start() {
 }
 func (c *clientOrderImpl) Stop() {
 	c.stop()
-}
\ No newline at end of file
+}
+func (c *clientOrderImpl) Evict() (uint64, int64, bool) {
+	return c.evict()
+}
@@ -1,10 +1,16 @@
 package clientsorder
 
 import (
+	"math"
+	"time"
+
 	"github.com/Grivn/libfalanx/clientsorder/types"
 	"github.com/Grivn/libfalanx/clientsorder/utils"
 	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/zcommon"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
 )
 
 type clientOrderImpl struct {
@@ -17,30 +23,92 @@ type clientOrderImpl struct {
 	// ===========================================================================
 	cache    utils.CacheReq       // cache is used to store the requests which temporarily cannot be processed
 	recorder utils.ClientRecorder // recorder si used to record the counter status of particular client
+	history  utils.ReqHistory     // history is used to store the received requests to serve the fetch requests
+
+	// fetch =====================================================================
+	// self:     the identifier of current replica
+	// quorum:   the amount of replicas which should have never received a sequence number to skip it
+	// fetching: whether the fetch timer has been armed for the missing requests
+	// attempts: the amount of fetch requests sent for current counter, the first one will be sent to the
+	//           client, and the others will be broadcast to the replicas which have relayed them
+	// absent:   the replicas which have never received particular sequence number, seq ==> replicas
+	// replicas: the replica set of current epoch, the absent reports from other replicas will be ignored
+	// rejoin:   the progress of the client has been forgotten, the counter will be reset with the requests
+	//           received during the first fetch timeout
+	self      uint64
+	quorum    int
+	replicas  map[uint64]bool
+	fetching  bool
+	attempts  int
+	absent    map[uint64]map[uint64]bool
+	rejoin    bool
+	timeoutC  chan uint64
+	fetchC    chan *pb.ReqFetch
+	responseC chan *pb.ReqResponse
+	epochC    chan tp.EpochEvent
+	network   network.Network
+
+	// eviction ==================================================================
+	// active:       the latest time a request, fetch request or response of the client has been received
+	// idleTimeout:  the instance will be reported as idle once it hasn't been active for idleTimeout
+	// idleC:        report the idle instance, it is nil if the instance is never evicted
+	// idleTimeoutC: the idle timer will be fired into it
+	// evictC:       receive the eviction requests, the instance will stop if it's still idle
+	active       time.Time
+	idleTimeout  time.Duration
+	idleC        chan uint64
+	idleTimeoutC chan bool
+	evictC       chan chan bool
 
 	// message channel ===========================================================
-	orderC chan string // orderC is used to trigger local log sort
-	recvC  chan *pb.OrderedReq
-	close  chan bool
+	orderC  chan string // orderC is used to trigger local log sort
+	recvC   chan *pb.OrderedReq
+	clientC chan *pb.OrderedReq // clientC is used to tell executor the client of transactions
+	close   chan bool
 
 	// essential tools ===========================================================
+	tools  zcommon.Tools
 	logger logger.Logger
 }
 
 func newClientOrderImpl(c types.Config) *clientOrderImpl {
-	c.Logger.Infof("Initialize client order instance: [id]%d", c.ID)
-	return &clientOrderImpl{
-		id:       c.ID,
-		cache:    utils.NewReqCache(),
-		recorder: utils.NewClientRecorder(),
-		recvC:    c.RecvC,
-		orderC:   c.OrderC,
-		logger:   c.Logger,
+	c.Logger.Infof("Initialize client order instance: [id]%d, [sequence]%d", c.ID, c.Sequence)
+	idleTimeout := c.IdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = types.DefaultIdleTimeout
 	}
+	client := &clientOrderImpl{
+		id:           c.ID,
+		cache:        utils.NewReqCache(),
+		recorder:     utils.NewClientRecorder(c.Sequence, c.Timestamp),
+		history:      utils.NewReqHistory(types.MaxHistoryReqs),
+		self:         c.Self,
+		absent:       make(map[uint64]map[uint64]bool),
+		rejoin:       c.Rejoin,
+		timeoutC:     make(chan uint64),
+		fetchC:       c.FetchC,
+		responseC:    c.ResponseC,
+		epochC:       c.EpochC,
+		network:      c.Network,
+		active:       time.Now(),
+		idleTimeout:  idleTimeout,
+		idleC:        c.IdleC,
+		idleTimeoutC: make(chan bool),
+		evictC:       make(chan chan bool),
+		recvC:        c.RecvC,
+		orderC:       c.OrderC,
+		clientC:      c.ClientC,
+		close:        make(chan bool),
+		tools:        c.Tools,
+		logger:       c.Logger,
+	}
+	client.updateReplicas(c.Replicas)
+	return client
 }
 
 func (c *clientOrderImpl) start() {
 	go c.listenOrderedRequest()
+	c.startIdleTimer()
 }
 
 func (c *clientOrderImpl) stop() {
@@ -54,7 +122,34 @@ func (c *clientOrderImpl) listenOrderedRequest() {
 			return
 
 		case req := <-c.recvC:
+			c.active = time.Now()
 			c.receiveOrderedRequest(req)
+
+		case fetch := <-c.fetchC:
+			c.active = time.Now()
+			c.serveFetch(fetch)
+
+		case response := <-c.responseC:
+			c.active = time.Now()
+			c.receiveResponse(response)
+
+		case counter := <-c.timeoutC:
+			c.fetchTimeout(counter)
+
+		case event := <-c.epochC:
+			c.reconfigure(event)
+
+		case <-c.idleTimeoutC:
+			c.checkIdle()
+
+		case reply := <-c.evictC:
+			if c.idle() {
+				c.logger.Infof("Evict client order instance: [id]%d, [sequence]%d", c.id, c.recorder.Counter())
+				close(c.close)
+				reply <- true
+				return
+			}
+			reply <- false
 		}
 	}
 }
@@ -68,16 +163,53 @@ func (c *clientOrderImpl) receiveOrderedRequest(r *pb.OrderedReq) {
 		c.logger.Warningf("Client %d received request from another client %d", c.id, r.ClientId)
 		return
 	}
+	if c.rejoin {
+		c.hold(r)
+		return
+	}
 
 	// store the request into the cache
 	c.cacheRequest(r)
 
 	// order the requests in the cache
 	c.orderCachedRequests()
+
+	// try to fetch the missing requests if there is a gap between the counter and the cached requests
+	c.checkGap()
+}
+
+// hold is used to cache the requests of a client whose progress has been forgotten until the fetch timer
+// expires, as they might arrive out of order. the requests not later than the forgotten ones are rejected,
+// as they might have been ordered before
+func (c *clientOrderImpl) hold(r *pb.OrderedReq) {
+	if r.Sequence == 0 || r.Timestamp <= c.recorder.Timestamp() {
+		c.logger.Warningf("Stale req-sequence %d from client %d, whose progress has been forgotten", r.Sequence, c.id)
+		return
+	}
+	c.cacheRequest(r)
+	c.startFetchTimer()
+}
+
+// resume is used to reset the counter with the earliest request held for a client whose progress has been
+// forgotten, and the requests will be ordered from it
+func (c *clientOrderImpl) resume() {
+	top := c.cache.Top()
+	if top == nil {
+		return
+	}
+	c.logger.Infof("Resume client %d from req-sequence %d", c.id, top.Sequence)
+	c.recorder = utils.NewClientRecorder(top.Sequence-1, c.recorder.Timestamp())
+	c.rejoin = false
 }
 
 // cache is used to save the requests temporarily unable to process because of its sequence number
 func (c *clientOrderImpl) cacheRequest(r *pb.OrderedReq) {
+	c.history.Add(r)
+	if r.Sequence <= c.recorder.Counter() {
+		// the stale request would block the cache, as it could never be posted
+		c.logger.Debugf("Stale req-sequence %d from client %d, current %d", r.Sequence, r.ClientId, c.recorder.Counter())
+		return
+	}
 	if c.cache.Has(r.Sequence) {
 		c.logger.Warningf("Duplicated req-sequence %d from client", r.Sequence)
 		return
@@ -87,18 +219,42 @@ func (c *clientOrderImpl) cacheRequest(r *pb.OrderedReq) {
 }
 
 func (c *clientOrderImpl) orderCachedRequests() uint64 {
-	if c.cache.Len() == 0 {
+	if c.cache.Len() == 0 || c.rejoin {
 		return c.recorder.Counter()
 	}
 
-	for c.recorder.Check(c.cache.Top()) {
-		r := c.cache.Pop()
-		c.recorder.Update(r)
-		c.postOrderedTxs(r.TxHashList)
+	for {
+		if c.recorder.Check(c.cache.Top()) {
+			r := c.cache.Pop()
+			c.recorder.Update(r)
+			c.attempts = 0
+			delete(c.absent, r.Sequence)
+			c.postClient(r)
+			c.postOrderedTxs(r.TxHashList)
+			continue
+		}
+		if c.skippable() {
+			c.logger.Warningf("[C-Skip] skip req-sequence %d from client %d, which has never been received by %d replicas", c.recorder.Counter()+1, c.id, len(c.absent[c.recorder.Counter()+1]))
+			delete(c.absent, c.recorder.Counter()+1)
+			c.recorder.Skip()
+			c.attempts = 0
+			continue
+		}
+		break
 	}
+	c.forgetAbsent()
 	return c.recorder.Counter()
 }
 
+// forgetAbsent is used to drop the absent reports of the sequence numbers which have been ordered or skipped
+func (c *clientOrderImpl) forgetAbsent() {
+	for seq := range c.absent {
+		if seq <= c.recorder.Counter() {
+			delete(c.absent, seq)
+		}
+	}
+}
+
 func (c *clientOrderImpl) postOrderedTxs(list []string) {
 	for _, txHash := range list {
 		c.logger.Infof("Post request %s from client %d", txHash, c.id)
@@ -109,3 +265,37 @@ func (c *clientOrderImpl) postOrderedTxs(list []string) {
 func (c *clientOrderImpl) postTx(txHash string) {
 	c.orderC <- txHash
 }
+
+func (c *clientOrderImpl) postClient(r *pb.OrderedReq) {
+	if c.clientC == nil {
+		return
+	}
+	c.clientC <- r
+}
+
+// reconfigure is used to switch to the replicas of new epoch, the absent sequence numbers reported by the
+// removed replicas will be dropped, and the ones which could be skipped with the new quorum will be skipped
+func (c *clientOrderImpl) reconfigure(event tp.EpochEvent) {
+	c.updateReplicas(event.Replicas)
+	for _, replicas := range c.absent {
+		for id := range replicas {
+			if !c.replicas[id] {
+				delete(replicas, id)
+			}
+		}
+	}
+	c.orderCachedRequests()
+	c.checkGap()
+}
+
+func (c *clientOrderImpl) updateReplicas(replicas []int) {
+	f := int(math.Floor((float64(len(replicas)) - 1) / 4))
+	if f == 0 {
+		f = 1
+	}
+	c.quorum = len(replicas) - f
+	c.replicas = make(map[uint64]bool)
+	for _, id := range replicas {
+		c.replicas[uint64(id)] = true
+	}
+}
@@ -0,0 +1,122 @@
+package clientsorder
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Grivn/libfalanx/clientsorder/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// testLogger drops the logs, the failures are reported by the tests themselves
+type testLogger struct{}
+
+func (testLogger) Debug(v ...interface{})                   {}
+func (testLogger) Debugf(format string, v ...interface{})   {}
+func (testLogger) Info(v ...interface{})                    {}
+func (testLogger) Infof(format string, v ...interface{})    {}
+func (testLogger) Warning(v ...interface{})                 {}
+func (testLogger) Warningf(format string, v ...interface{}) {}
+func (testLogger) Error(v ...interface{})                   {}
+func (testLogger) Errorf(format string, v ...interface{})   {}
+
+// testNetwork drops the messages, the fetch responses are delivered by the tests themselves
+type testNetwork struct{}
+
+func (testNetwork) Broadcast(msg *pb.ConsensusMessage)          {}
+func (testNetwork) Unicast(to uint64, msg *pb.ConsensusMessage) {}
+
+func newTestClientOrder() *clientOrderImpl {
+	return newClientOrderImpl(types.Config{
+		ID:       5,
+		Self:     1,
+		Replicas: []int{1, 2, 3, 4},
+		OrderC:   make(chan string, 10000),
+		Network:  testNetwork{},
+		Logger:   testLogger{},
+	})
+}
+
+func newTestReq(seq uint64) *pb.OrderedReq {
+	return &pb.OrderedReq{
+		ClientId:   5,
+		Sequence:   seq,
+		TxHashList: []string{fmt.Sprintf("tx-%d", seq)},
+		Timestamp:  int64(seq),
+	}
+}
+
+// TestForgetAbsent keeps losing the requests of the client, the absent reports for them should be dropped
+// once they have been ordered or skipped, and the history should only keep the latest requests
+func TestForgetAbsent(t *testing.T) {
+	c := newTestClientOrder()
+	defer c.stop()
+
+	for seq := uint64(1); seq <= 3*types.MaxHistoryReqs; seq += 3 {
+		// seq+1 is lost, and it will be fetched from others
+		c.receiveOrderedRequest(newTestReq(seq))
+		c.receiveOrderedRequest(newTestReq(seq + 2))
+		c.fetchTimeout(c.recorder.Counter())
+		c.receiveResponse(&pb.ReqResponse{ReplicaId: 2, ClientId: 5, Reqs: []*pb.OrderedReq{newTestReq(seq + 1)}})
+		if c.recorder.Counter() != seq+2 {
+			t.Fatalf("counter %d, expect %d", c.recorder.Counter(), seq+2)
+		}
+	}
+	if len(c.absent) != 0 {
+		t.Fatalf("keep absent reports for %d ordered sequence numbers", len(c.absent))
+	}
+	if c.history.Len() != types.MaxHistoryReqs {
+		t.Fatalf("keep %d requests in history, expect %d", c.history.Len(), types.MaxHistoryReqs)
+	}
+
+	// seq+1 has never been issued by the client, and it will be skipped with the reports from others
+	seq := c.recorder.Counter() + 1
+	c.receiveOrderedRequest(newTestReq(seq))
+	c.receiveOrderedRequest(newTestReq(seq + 2))
+	c.fetchTimeout(c.recorder.Counter())
+	for _, replica := range []uint64{2, 3} {
+		c.receiveResponse(&pb.ReqResponse{ReplicaId: replica, ClientId: 5, Absent: []uint64{seq + 1, seq + 100}})
+	}
+	if c.recorder.Counter() != seq+2 {
+		t.Fatalf("counter %d, expect %d", c.recorder.Counter(), seq+2)
+	}
+	if len(c.absent) != 0 {
+		t.Fatalf("keep absent reports for %d sequence numbers after skipping", len(c.absent))
+	}
+}
+
+// TestRejoin checks that the instance of a client whose progress has been forgotten is resumed from the
+// earliest request received before the fetch timer expires, and the requests not later than the forgotten
+// ones are rejected
+func TestRejoin(t *testing.T) {
+	c := newClientOrderImpl(types.Config{
+		ID:        5,
+		Self:      1,
+		Replicas:  []int{1, 2, 3, 4},
+		Timestamp: 100,
+		Rejoin:    true,
+		OrderC:    make(chan string, 10),
+		Network:   testNetwork{},
+		Logger:    testLogger{},
+	})
+	defer c.stop()
+
+	// the replayed request issued before the progress has been forgotten
+	c.receiveOrderedRequest(newTestReq(90))
+	if c.recorder.Counter() != 0 || c.cache.Len() != 0 {
+		t.Fatalf("accept the replayed request, counter %d", c.recorder.Counter())
+	}
+
+	// the requests might arrive out of order, and they are held until the fetch timer expires
+	c.receiveOrderedRequest(newTestReq(102))
+	c.receiveOrderedRequest(newTestReq(101))
+	if c.recorder.Counter() != 0 || len(c.orderC) != 0 {
+		t.Fatalf("order the requests before the fetch timer expires, counter %d", c.recorder.Counter())
+	}
+
+	c.fetchTimeout(c.recorder.Counter())
+	c.receiveOrderedRequest(newTestReq(103))
+	if c.recorder.Counter() != 103 || len(c.orderC) != 3 {
+		t.Fatalf("counter %d, ordered %d txs, expect 103 and 3", c.recorder.Counter(), len(c.orderC))
+	}
+}
@@ -0,0 +1,61 @@
+package clientsorder
+
+import (
+	"time"
+)
+
+// startIdleTimer is used to check whether the instance has become idle every idleTimeout, the timer is only
+// armed if the instance could be evicted
+func (c *clientOrderImpl) startIdleTimer() {
+	if c.idleC == nil {
+		return
+	}
+	go func() {
+		select {
+		case <-c.close:
+		case <-time.After(c.idleTimeout):
+			select {
+			case <-c.close:
+			case c.idleTimeoutC <- true:
+			}
+		}
+	}()
+}
+
+// checkIdle is used to report the instance which hasn't received anything from its client for idleTimeout
+// and has nothing to order, the report is dropped if the receiver is busy, and the timer will be re-armed
+// until the instance has been evicted
+func (c *clientOrderImpl) checkIdle() {
+	if time.Since(c.active) >= c.idleTimeout && c.idle() {
+		select {
+		case c.idleC <- c.id:
+		default:
+		}
+	}
+	c.startIdleTimer()
+}
+
+// idle checks whether the instance could be stopped without losing any request of its client, the requests
+// waiting for the missing ones in cache and the messages which haven't been processed should be kept
+func (c *clientOrderImpl) idle() bool {
+	return c.cache.Len() == 0 && !c.fetching && len(c.recvC) == 0 && len(c.fetchC) == 0 && len(c.responseC) == 0
+}
+
+// evict is used to stop the instance if it is still idle, and return the progress of its client, so that
+// the instance created for its later requests could be resumed from it. the caller should have stopped
+// dispatching messages to the instance, and the instance shouldn't be stopped once it has been evicted.
+// the instance which is busy processing something is kept without waiting for it
+func (c *clientOrderImpl) evict() (uint64, int64, bool) {
+	reply := make(chan bool)
+	select {
+	case c.evictC <- reply:
+	case <-c.close:
+		return 0, 0, false
+	default:
+		return 0, 0, false
+	}
+	if !<-reply {
+		return 0, 0, false
+	}
+	return c.recorder.Counter(), c.recorder.Timestamp(), true
+}
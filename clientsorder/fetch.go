@@ -0,0 +1,178 @@
+package clientsorder
+
+import (
+	"time"
+
+	"github.com/Grivn/libfalanx/clientsorder/types"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	"github.com/gogo/protobuf/proto"
+)
+
+// checkGap is used to arm the fetch timer when the cached requests are ahead of the counter, which means
+// some requests from the client might have been lost, or the client has skipped some sequence numbers
+func (c *clientOrderImpl) checkGap() {
+	if c.id == c.self || c.network == nil {
+		// the requests of current replica are delivered locally
+		return
+	}
+	top := c.cache.Top()
+	if top == nil || top.Sequence <= c.recorder.Counter()+1 {
+		return
+	}
+	c.startFetchTimer()
+}
+
+func (c *clientOrderImpl) startFetchTimer() {
+	if c.fetching {
+		return
+	}
+	c.fetching = true
+
+	counter := c.recorder.Counter()
+	go func() {
+		select {
+		case <-c.close:
+		case <-time.After(types.DefaultFetchTimeout):
+			select {
+			case <-c.close:
+			case c.timeoutC <- counter:
+			}
+		}
+	}()
+}
+
+// fetchTimeout is used to request the missing requests between the counter and the cached requests, the
+// timer will be re-armed until the gap has been filled or skipped
+func (c *clientOrderImpl) fetchTimeout(counter uint64) {
+	c.fetching = false
+
+	if c.rejoin {
+		c.resume()
+		c.orderCachedRequests()
+		c.checkGap()
+		return
+	}
+
+	top := c.cache.Top()
+	if top == nil || top.Sequence <= c.recorder.Counter()+1 {
+		return
+	}
+	if counter != c.recorder.Counter() {
+		// some requests have been ordered since the timer was armed, wait for the rest of them once again
+		c.startFetchTimer()
+		return
+	}
+
+	fetch := &pb.ReqFetch{
+		ReplicaId: c.self,
+		ClientId:  c.id,
+		FromSeq:   counter + 1,
+		ToSeq:     top.Sequence - 1,
+	}
+	payload, err := proto.Marshal(fetch)
+	if err != nil {
+		c.logger.Errorf("Marshal req fetch error: %s", err)
+		return
+	}
+	msg := &pb.ConsensusMessage{
+		Type:    pb.Type_REQ_FETCH,
+		Payload: payload,
+	}
+
+	// current replica has never received the missing requests either
+	for seq := fetch.FromSeq; seq <= fetch.ToSeq; seq++ {
+		c.recordAbsent(seq, c.self)
+	}
+
+	if c.attempts == 0 {
+		c.logger.Warningf("[C-Fetch] fetch reqs from client %d, seq [%d, %d]", c.id, fetch.FromSeq, fetch.ToSeq)
+		c.network.Unicast(c.id, msg)
+	} else {
+		c.logger.Warningf("[C-Fetch] fetch reqs of client %d from others, seq [%d, %d], attempt %d", c.id, fetch.FromSeq, fetch.ToSeq, c.attempts+1)
+		c.network.Broadcast(msg)
+	}
+	c.attempts++
+	c.startFetchTimer()
+}
+
+// serveFetch is used to reply the stored requests to the replica which has missed them, and the sequence
+// numbers which have been skipped from our point of view. the requests have been signed by the client so
+// that they could be relayed by anyone
+func (c *clientOrderImpl) serveFetch(fetch *pb.ReqFetch) {
+	if fetch.ClientId != c.id || fetch.ReplicaId == c.self || c.network == nil {
+		return
+	}
+
+	reqs, absent := c.history.GetRange(fetch.FromSeq, fetch.ToSeq, types.MaxFetchReqs)
+	if len(reqs) == 0 && len(absent) == 0 {
+		return
+	}
+	response := &pb.ReqResponse{
+		ReplicaId: c.self,
+		ClientId:  c.id,
+		Reqs:      reqs,
+		Absent:    absent,
+	}
+	if err := zcommon.SignReqResponse(c.tools, response); err != nil {
+		c.logger.Errorf("Sign req response error: %s", err)
+		return
+	}
+	payload, err := proto.Marshal(response)
+	if err != nil {
+		c.logger.Errorf("Marshal req response error: %s", err)
+		return
+	}
+	msg := &pb.ConsensusMessage{
+		Type:    pb.Type_REQ_RESPONSE,
+		Payload: payload,
+	}
+	c.logger.Infof("[C-Fetch] reply %d reqs and %d absent seqs of client %d to replica %d", len(reqs), len(absent), c.id, fetch.ReplicaId)
+	c.network.Unicast(fetch.ReplicaId, msg)
+}
+
+// receiveResponse is used to process the fetched requests and the absent sequence numbers reported by
+// other replicas, the response has been verified so that the absent reports are counted for its signer
+func (c *clientOrderImpl) receiveResponse(response *pb.ReqResponse) {
+	if response.ClientId != c.id {
+		return
+	}
+	for _, r := range response.Reqs {
+		c.cacheRequest(r)
+	}
+	// only the sequence numbers before the cached requests could be skipped, so that the other reports
+	// are ignored instead of being kept for the sequence numbers which might never be issued
+	top := c.cache.Top()
+	for _, seq := range response.Absent {
+		if seq > c.recorder.Counter() && top != nil && seq < top.Sequence {
+			c.recordAbsent(seq, response.ReplicaId)
+		}
+	}
+	c.orderCachedRequests()
+	c.checkGap()
+}
+
+func (c *clientOrderImpl) recordAbsent(seq uint64, replica uint64) {
+	if !c.replicas[replica] {
+		return
+	}
+	replicas, ok := c.absent[seq]
+	if !ok {
+		replicas = make(map[uint64]bool)
+		c.absent[seq] = replicas
+	}
+	replicas[replica] = true
+}
+
+// skippable checks whether the next sequence number could be skipped, a sequence number is regarded as
+// never issued by the client once n-f replicas have never received it while they have received the later
+// ones. skipping a request only means current replica won't order its txs, which could still be ordered
+// with the logs from other replicas
+func (c *clientOrderImpl) skippable() bool {
+	next := c.recorder.Counter() + 1
+	top := c.cache.Top()
+	if top == nil || top.Sequence <= next || c.cache.Has(next) {
+		return false
+	}
+	return len(c.absent[next]) >= c.quorum
+}
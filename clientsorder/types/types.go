@@ -1,13 +1,73 @@
 package types
 
 import (
+	"time"
+
 	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/zcommon"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
 )
 
+// Config is used to initiate the client order instance
+// Self:        the identifier of current replica, which is used to fetch the missing requests from others
+// Replicas:    the replicas of current epoch, a sequence number will be skipped once n-f of them have never
+//              received it
+// Sequence:    the sequence number of the latest request accepted from the client, the instance created
+//              for a client which has been evicted will be resumed from it
+// Timestamp:   the timestamp of the latest request accepted from the client
+// Rejoin:      the progress of the client might have been forgotten, the instance will be resumed from the
+//              first request later than Timestamp, so that the earlier ones cannot be replayed
+// ClientC:     post the accepted requests to executor, so that it could reply to the client after execution
+// FetchC:      receive the requests to fetch the requests of current client order's client
+// ResponseC:   receive the responses of the fetch requests, the responses and the requests in them should
+//              have been verified
+// EpochC:      receive the replicas of new epoch, the quorum to skip a sequence number will be updated
+// IdleC:       report the client once the instance has been idle for IdleTimeout, so that it could be evicted,
+//              it could be nil if the instance is never evicted
+// IdleTimeout: the duration without any request before the instance is reported as idle, DefaultIdleTimeout
+//              will be used if it is not positive
+// Network:     used to send the fetch requests and responses
+// Tools:       sign the responses of current replica, as the absent sequence numbers in them are counted to
+//              skip a sequence number
 type Config struct {
-	ID     uint64
-	RecvC  chan *pb.OrderedReq
-	OrderC chan string
-	Logger logger.Logger
+	ID          uint64
+	Self        uint64
+	Replicas    []int
+	Sequence    uint64
+	Timestamp   int64
+	Rejoin      bool
+	RecvC       chan *pb.OrderedReq
+	OrderC      chan string
+	ClientC     chan *pb.OrderedReq
+	FetchC      chan *pb.ReqFetch
+	ResponseC   chan *pb.ReqResponse
+	EpochC      chan tp.EpochEvent
+	IdleC       chan uint64
+	IdleTimeout time.Duration
+	Network     network.Network
+	Tools       zcommon.Tools
+	Logger      logger.Logger
 }
+
+const (
+	// DefaultFetchTimeout is the duration to wait for the missing requests before fetching them
+	DefaultFetchTimeout = time.Second
+
+	// MaxFetchReqs is the maximum amount of requests and absent sequence numbers in one fetch response
+	MaxFetchReqs = 100
+
+	// MaxHistoryReqs is the amount of latest requests kept to serve the fetch requests, the older requests
+	// cannot be fetched from current replica any more
+	MaxHistoryReqs = 1000
+
+	// DefaultMaxClients is the default maximum amount of clients whose instances are kept at the same time
+	DefaultMaxClients = 10000
+
+	// DefaultIdleTimeout is the default duration without any request before an instance is evicted
+	DefaultIdleTimeout = time.Minute
+
+	// DefaultMaxEvicted is the default maximum amount of evicted clients whose progress is kept
+	DefaultMaxEvicted = 100000
+)
@@ -60,7 +60,7 @@ func (c *cacheReqImpl) Push(r *pb.OrderedReq) {
 		return
 	}
 
-	c.heap.Push(r)
+	heap.Push(c.heap, r)
 	c.presence[seq] = r
 }
 
@@ -68,7 +68,7 @@ func (c *cacheReqImpl) Pop() *pb.OrderedReq {
 	if c.heap.Len() == 0 {
 		return nil
 	}
-	r, ok := c.heap.Pop().(*pb.OrderedReq)
+	r, ok := heap.Pop(c.heap).(*pb.OrderedReq)
 	if !ok {
 		return nil
 	}
@@ -113,8 +113,5 @@ func (h *ReqHeap) top() interface{} {
 	if h.Len() == 0 {
 		return nil
 	}
-	old := *h
-	n := h.Len()
-	x := old[n-1]
-	return x
+	return (*h)[0]
 }
@@ -4,18 +4,24 @@ import pb "github.com/Grivn/libfalanx/zcommon/protos"
 
 type ClientRecorder interface {
 	Counter() uint64
+	Timestamp() int64
 	Check(r *pb.OrderedReq) bool
 	Update(r *pb.OrderedReq)
+	Skip()
 }
 
-func NewClientRecorder() *clientRecorderImpl {
-	return newClientRecorderImpl()
+func NewClientRecorder(counter uint64, timestamp int64) *clientRecorderImpl {
+	return newClientRecorderImpl(counter, timestamp)
 }
 
 func (cr *clientRecorderImpl) Counter() uint64 {
 	return cr.counter
 }
 
+func (cr *clientRecorderImpl) Timestamp() int64 {
+	return cr.timestamp
+}
+
 func (cr *clientRecorderImpl) Check(r *pb.OrderedReq) bool {
 	return cr.check(r)
 }
@@ -24,6 +30,10 @@ func (cr *clientRecorderImpl) Update(r *pb.OrderedReq) {
 	cr.update(r)
 }
 
+func (cr *clientRecorderImpl) Skip() {
+	cr.skip()
+}
+
 type clientRecorderImpl struct {
 	// counter indicates the order of requests from client
 	counter uint64
@@ -32,8 +42,8 @@ type clientRecorderImpl struct {
 	timestamp int64
 }
 
-func newClientRecorderImpl() *clientRecorderImpl {
-	return &clientRecorderImpl{}
+func newClientRecorderImpl(counter uint64, timestamp int64) *clientRecorderImpl {
+	return &clientRecorderImpl{counter: counter, timestamp: timestamp}
 }
 
 func (cr *clientRecorderImpl) check(r *pb.OrderedReq) bool {
@@ -47,3 +57,9 @@ func (cr *clientRecorderImpl) update(r *pb.OrderedReq) {
 	cr.counter++
 	cr.timestamp = r.Timestamp
 }
+
+// skip is used to pass the sequence number which has been confirmed to be never issued by the client,
+// the timestamp is kept so that the following request should still be later than the last accepted one
+func (cr *clientRecorderImpl) skip() {
+	cr.counter++
+}
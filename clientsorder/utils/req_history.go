@@ -0,0 +1,111 @@
+package utils
+
+import (
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// ================ ReqHistory Interfaces ==================
+// ReqHistory is used to store the latest requests received from a client, so that current replica could
+// serve the fetch requests from the ones which have missed them
+type ReqHistory interface {
+	Len() int
+	Max() uint64
+	Add(r *pb.OrderedReq)
+	Get(seq uint64) *pb.OrderedReq
+	GetRange(from, to uint64, max int) ([]*pb.OrderedReq, []uint64)
+}
+
+func NewReqHistory(limit uint64) *reqHistoryImpl {
+	return newReqHistoryImpl(limit)
+}
+
+func (h *reqHistoryImpl) Len() int {
+	return len(h.reqs)
+}
+
+func (h *reqHistoryImpl) Max() uint64 {
+	return h.max
+}
+
+func (h *reqHistoryImpl) Add(r *pb.OrderedReq) {
+	h.add(r)
+}
+
+func (h *reqHistoryImpl) Get(seq uint64) *pb.OrderedReq {
+	return h.reqs[seq]
+}
+
+func (h *reqHistoryImpl) GetRange(from, to uint64, max int) ([]*pb.OrderedReq, []uint64) {
+	return h.getRange(from, to, max)
+}
+
+type reqHistoryImpl struct {
+	// reqs is used to store the requests, sequence number ==> request
+	reqs map[uint64]*pb.OrderedReq
+
+	// max is the largest sequence number of the received requests
+	max uint64
+
+	// floor is the smallest sequence number which could be stored, only the latest limit sequence numbers
+	// are kept so that the history won't grow with the throughput
+	floor uint64
+	limit uint64
+}
+
+func newReqHistoryImpl(limit uint64) *reqHistoryImpl {
+	return &reqHistoryImpl{reqs: make(map[uint64]*pb.OrderedReq), limit: limit}
+}
+
+func (h *reqHistoryImpl) add(r *pb.OrderedReq) {
+	if r.Sequence < h.floor {
+		return
+	}
+	if _, ok := h.reqs[r.Sequence]; ok {
+		return
+	}
+	h.reqs[r.Sequence] = r
+	if r.Sequence > h.max {
+		h.max = r.Sequence
+	}
+	if h.max-h.floor >= h.limit {
+		h.forget(h.max - h.limit + 1)
+	}
+}
+
+// forget is used to drop the requests before floor
+func (h *reqHistoryImpl) forget(floor uint64) {
+	if floor-h.floor > uint64(len(h.reqs)) {
+		for seq := range h.reqs {
+			if seq < floor {
+				delete(h.reqs, seq)
+			}
+		}
+	} else {
+		for seq := h.floor; seq < floor; seq++ {
+			delete(h.reqs, seq)
+		}
+	}
+	h.floor = floor
+}
+
+// getRange returns the stored requests whose sequence number is in [from, to], at most max requests will
+// be returned. it also returns the sequence numbers in the range which have never been received, while
+// some later requests have been received, so that the client has skipped them from our point of view.
+// nothing is reported for the sequence numbers which have been dropped from the history
+func (h *reqHistoryImpl) getRange(from, to uint64, max int) ([]*pb.OrderedReq, []uint64) {
+	if from < h.floor {
+		from = h.floor
+	}
+	var (
+		reqs   []*pb.OrderedReq
+		absent []uint64
+	)
+	for seq := from; seq <= to && seq < h.max && len(reqs)+len(absent) < max; seq++ {
+		if r, ok := h.reqs[seq]; ok {
+			reqs = append(reqs, r)
+		} else {
+			absent = append(absent, seq)
+		}
+	}
+	return reqs, absent
+}
@@ -0,0 +1,344 @@
+package dagmanager
+
+import (
+	"github.com/Grivn/libfalanx/dagmanager/types"
+	"github.com/Grivn/libfalanx/logger"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+type dagManagerImpl struct {
+	// seqNo is the sequence number of the next finalized batch to execute
+	seqNo uint64
+
+	// batches is used to store the finalized batches which haven't been executed, seq ==> linear order
+	// the batches are executed strictly in the order of sequence number, and batch k could be executed iff:
+	//   A1. there isn't any pending pair which contains the transactions in it
+	//   A2. all the transactions which should be executed before them have been finalized, and there isn't
+	//       any pending pair which contains these transactions either
+	// the transactions in later batches which should be executed before batch k will be executed with it
+	batches map[uint64][]string
+
+	// graph is the DAG of finalized transactions
+	graph *types.DAG
+
+	// reversed is used to find the transactions which should be executed before particular one, to ==> from
+	reversed map[string]map[string]bool
+
+	// blocked is the amount of pending pairs for particular transaction
+	blocked map[string]int
+
+	// resolved is used to record the determined relations whose pending pair hasn't been received
+	resolved map[tp.Pair]tp.Edge
+
+	// executed is used to record the transactions which have been executed, so that the duplicated ones
+	// finalized once again will be skipped. the marks are kept for DefaultExecutedBatches batches after the
+	// batches executing them, which are recorded in expiring, seq ==> executed transactions
+	executed map[string]bool
+	expiring map[uint64][]string
+
+	// executeC is used to post the executable batches
+	// close is closed once the DAG manager has been stopped, the batch being posted will be dropped
+	executeC chan tp.ExecuteEvent
+	close    chan bool
+
+	logger logger.Logger
+}
+
+func newDAGManagerImpl(seqNo uint64, executeC chan tp.ExecuteEvent, close chan bool, logger logger.Logger) *dagManagerImpl {
+	return &dagManagerImpl{
+		seqNo:   seqNo + 1,
+		batches: make(map[uint64][]string),
+		graph: &types.DAG{
+			Vertices: make(map[string]*types.Vertex),
+			Edges:    make(map[string]map[string]bool),
+			Pending:  make(map[tp.Pair]bool),
+		},
+		reversed: make(map[string]map[string]bool),
+		blocked:  make(map[string]int),
+		resolved: make(map[tp.Pair]tp.Edge),
+		executed: make(map[string]bool),
+		expiring: make(map[uint64][]string),
+		executeC: executeC,
+		close:    close,
+		logger:   logger,
+	}
+}
+
+func (dm *dagManagerImpl) Extend(value *types.DAGValue) {
+	dm.extend(value)
+}
+
+func (dm *dagManagerImpl) Resolve(edge tp.Edge) {
+	dm.resolve(edge)
+}
+
+func (dm *dagManagerImpl) Transfer(event tp.TransferEvent) {
+	dm.transfer(event)
+}
+
+func (dm *dagManagerImpl) Execute() {
+	dm.execute()
+}
+
+func (dm *dagManagerImpl) Metrics() types.Metrics {
+	return dm.metrics()
+}
+
+func (dm *dagManagerImpl) GetGraph() *types.DAG {
+	return dm.graph
+}
+
+func (dm *dagManagerImpl) extend(value *types.DAGValue) {
+	dm.logger.Infof("[DAG] extend batch %d, txs %d, external %d, pending %d", value.Seq, len(value.Order), len(value.External), len(value.Pending))
+
+	if value.Seq < dm.seqNo {
+		dm.logger.Warningf("[DAG] batch %d has been executed, current %d", value.Seq, dm.seqNo)
+		return
+	}
+	if _, ok := dm.batches[value.Seq]; ok {
+		dm.logger.Warningf("[DAG] duplicated batch %d", value.Seq)
+		return
+	}
+
+	order := make([]string, 0, len(value.Order))
+	for index, txHash := range value.Order {
+		if dm.executed[txHash] || dm.graph.Vertices[txHash] != nil {
+			dm.logger.Warningf("[DAG] duplicated transaction %s in batch %d", txHash, value.Seq)
+			continue
+		}
+		dm.graph.Vertices[txHash] = &types.Vertex{Hash: txHash, Seq: value.Seq, Index: index}
+		if len(order) > 0 {
+			dm.addEdge(order[len(order)-1], txHash)
+		}
+		order = append(order, txHash)
+	}
+	dm.batches[value.Seq] = order
+
+	for _, edge := range value.External {
+		dm.addEdge(edge.From, edge.To)
+	}
+
+	for _, pair := range value.Pending {
+		key := pairKey(pair.Former, pair.Latter)
+		if edge, ok := dm.resolved[key]; ok {
+			// the relation has been determined before we received the pending pair
+			delete(dm.resolved, key)
+			if edge.From == pair.Former {
+				dm.addEdge(edge.From, edge.To)
+			}
+			continue
+		}
+		if dm.graph.Pending[pair] || dm.executed[pair.Former] || dm.executed[pair.Latter] {
+			continue
+		}
+		dm.graph.Pending[pair] = true
+		dm.blocked[pair.Former]++
+		dm.blocked[pair.Latter]++
+	}
+}
+
+// resolve is used to release the pending pair, the edge is recorded only if the outsider has been preferred,
+// as the relation preferring the finalized transaction is implied by the order of finalization
+func (dm *dagManagerImpl) resolve(edge tp.Edge) {
+	pair := tp.Pair{Former: edge.From, Latter: edge.To}
+	preferred := dm.graph.Pending[pair]
+	if !preferred {
+		pair = tp.Pair{Former: edge.To, Latter: edge.From}
+		if !dm.graph.Pending[pair] {
+			dm.resolved[pairKey(edge.From, edge.To)] = edge
+			return
+		}
+	}
+	dm.logger.Infof("[DAG] resolve pending pair, %s ===> %s", edge.From, edge.To)
+
+	delete(dm.graph.Pending, pair)
+	dm.unblock(pair.Former)
+	dm.unblock(pair.Latter)
+	if preferred {
+		dm.addEdge(edge.From, edge.To)
+	}
+}
+
+// transfer is used to drop the transactions executed by state transfer, the pending pairs which contain them
+// will never be resolved, so that they are released to unblock the other transactions
+func (dm *dagManagerImpl) transfer(event tp.TransferEvent) {
+	transferred := make(map[string]bool)
+	for _, batch := range event.Batches {
+		for _, txHash := range batch.TxHashes {
+			if dm.executed[txHash] {
+				continue
+			}
+			transferred[txHash] = true
+			dm.remove(txHash)
+			dm.expiring[batch.Seq] = append(dm.expiring[batch.Seq], txHash)
+		}
+	}
+	if len(transferred) == 0 {
+		return
+	}
+	for key := range dm.graph.Pending {
+		if !transferred[key.Former] && !transferred[key.Latter] {
+			continue
+		}
+		delete(dm.graph.Pending, key)
+		dm.unblock(key.Former)
+		dm.unblock(key.Latter)
+	}
+	dm.logger.Infof("[DAG] drop %d transactions executed by state transfer", len(transferred))
+}
+
+func (dm *dagManagerImpl) unblock(txHash string) {
+	dm.blocked[txHash]--
+	if dm.blocked[txHash] <= 0 {
+		delete(dm.blocked, txHash)
+	}
+}
+
+// addEdge is used to record that 'from' should be executed before 'to', the edges which construct a cycle are
+// kept as well, and the cycle will be condensed once the transactions in it are executed
+func (dm *dagManagerImpl) addEdge(from, to string) {
+	if dm.executed[from] {
+		return
+	}
+	if dm.executed[to] {
+		dm.logger.Warningf("[DAG] %s has been executed before %s", to, from)
+		return
+	}
+
+	if dm.graph.Edges[from] == nil {
+		dm.graph.Edges[from] = make(map[string]bool)
+	}
+	dm.graph.Edges[from][to] = true
+
+	if dm.reversed[to] == nil {
+		dm.reversed[to] = make(map[string]bool)
+	}
+	dm.reversed[to][from] = true
+}
+
+// execute is used to post the finalized batches in the order of sequence number, every batch is posted with
+// the transactions which should be executed before it, so that the executed order only depends on the
+// finalized batches and the relations of their pending pairs, rather than the time they have been received
+func (dm *dagManagerImpl) execute() {
+	for {
+		order, ok := dm.batches[dm.seqNo]
+		if !ok {
+			return
+		}
+		closure, ok := dm.closure(order)
+		if !ok {
+			return
+		}
+
+		txHashes := dm.linearize(closure)
+		for _, txHash := range txHashes {
+			dm.remove(txHash)
+		}
+		delete(dm.batches, dm.seqNo)
+
+		dm.logger.Infof("[DAG] post execute event %d, txs %d", dm.seqNo, len(txHashes))
+		select {
+		case dm.executeC <- tp.ExecuteEvent{Seq: dm.seqNo, TxHashes: txHashes}:
+		case <-dm.close:
+			return
+		}
+		dm.expiring[dm.seqNo] = txHashes
+		dm.expire()
+		dm.seqNo++
+	}
+}
+
+// expire is used to drop the executed marks for the batches executed DefaultExecutedBatches batches ago, and
+// the determined relations concerning them, whose pending pairs have been received or skipped with them.
+func (dm *dagManagerImpl) expire() {
+	expired := make(map[string]bool)
+	for seq, txHashes := range dm.expiring {
+		if seq+types.DefaultExecutedBatches > dm.seqNo {
+			continue
+		}
+		for _, txHash := range txHashes {
+			delete(dm.executed, txHash)
+			expired[txHash] = true
+		}
+		delete(dm.expiring, seq)
+	}
+	if len(expired) == 0 {
+		return
+	}
+	for key := range dm.resolved {
+		if expired[key.Former] || expired[key.Latter] {
+			delete(dm.resolved, key)
+		}
+	}
+}
+
+func (dm *dagManagerImpl) metrics() types.Metrics {
+	return types.Metrics{
+		Batches:  len(dm.batches),
+		Vertices: len(dm.graph.Vertices),
+		Edges:    len(dm.graph.Edges),
+		Pending:  len(dm.graph.Pending),
+		Resolved: len(dm.resolved),
+		Executed: len(dm.executed),
+	}
+}
+
+// closure is used to find the transactions in the batch and the ones which should be executed before them,
+// it returns false if some of them haven't been finalized or have pending pairs
+func (dm *dagManagerImpl) closure(order []string) (map[string]bool, bool) {
+	closure := make(map[string]bool)
+	var stack []string
+	for _, txHash := range order {
+		if !dm.executed[txHash] {
+			stack = append(stack, txHash)
+		}
+	}
+	for len(stack) > 0 {
+		txHash := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if closure[txHash] {
+			continue
+		}
+		if dm.graph.Vertices[txHash] == nil {
+			dm.logger.Debugf("[DAG] batch %d is waiting for %s to be finalized", dm.seqNo, txHash)
+			return nil, false
+		}
+		if dm.blocked[txHash] > 0 {
+			dm.logger.Debugf("[DAG] batch %d is waiting for the pending pairs of %s", dm.seqNo, txHash)
+			return nil, false
+		}
+		closure[txHash] = true
+		for from := range dm.reversed[txHash] {
+			if !dm.executed[from] && !closure[from] {
+				stack = append(stack, from)
+			}
+		}
+	}
+	return closure, true
+}
+
+func (dm *dagManagerImpl) remove(txHash string) {
+	dm.executed[txHash] = true
+	delete(dm.graph.Vertices, txHash)
+	for to := range dm.graph.Edges[txHash] {
+		delete(dm.reversed[to], txHash)
+		if len(dm.reversed[to]) == 0 {
+			delete(dm.reversed, to)
+		}
+	}
+	for from := range dm.reversed[txHash] {
+		delete(dm.graph.Edges[from], txHash)
+		if len(dm.graph.Edges[from]) == 0 {
+			delete(dm.graph.Edges, from)
+		}
+	}
+	delete(dm.graph.Edges, txHash)
+	delete(dm.reversed, txHash)
+}
+
+func pairKey(a, b string) tp.Pair {
+	if a < b {
+		return tp.Pair{Former: a, Latter: b}
+	}
+	return tp.Pair{Former: b, Latter: a}
+}
@@ -0,0 +1,166 @@
+package dagmanager
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Grivn/libfalanx/dagmanager/types"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// testLogger drops the logs, the failures are reported by the tests themselves
+type testLogger struct{}
+
+func (testLogger) Debug(v ...interface{})                   {}
+func (testLogger) Debugf(format string, v ...interface{})   {}
+func (testLogger) Info(v ...interface{})                    {}
+func (testLogger) Infof(format string, v ...interface{})    {}
+func (testLogger) Warning(v ...interface{})                 {}
+func (testLogger) Warningf(format string, v ...interface{}) {}
+func (testLogger) Error(v ...interface{})                   {}
+func (testLogger) Errorf(format string, v ...interface{})   {}
+
+func newTestManager() (*dagManagerImpl, chan tp.ExecuteEvent) {
+	executeC := make(chan tp.ExecuteEvent, tp.DefaultChannelLen)
+	return newDAGManagerImpl(0, executeC, make(chan bool), testLogger{}), executeC
+}
+
+// extend is used to extend DAG with a finalized batch and try to execute the batches
+func extend(dm *dagManagerImpl, seq uint64, order []string, external []tp.Edge, pending []tp.Pair) {
+	dm.Extend(&types.DAGValue{Seq: seq, Order: order, External: external, Pending: pending})
+	dm.Execute()
+}
+
+// expectExecuted checks the batches posted by DAG manager, and that there isn't any other one
+func expectExecuted(t *testing.T, executeC chan tp.ExecuteEvent, expect ...[]string) {
+	for _, txHashes := range expect {
+		select {
+		case event := <-executeC:
+			if fmt.Sprint(event.TxHashes) != fmt.Sprint(txHashes) {
+				t.Fatalf("executed %v in batch %d, expect %v", event.TxHashes, event.Seq, txHashes)
+			}
+		default:
+			t.Fatalf("expect to execute %v", txHashes)
+		}
+	}
+	select {
+	case event := <-executeC:
+		t.Fatalf("unexpected batch %d: %v", event.Seq, event.TxHashes)
+	default:
+	}
+}
+
+func TestExecuteInOrder(t *testing.T) {
+	dm, executeC := newTestManager()
+
+	extend(dm, 2, []string{"c", "d"}, nil, nil)
+	expectExecuted(t, executeC)
+
+	extend(dm, 1, []string{"b", "a"}, nil, nil)
+	expectExecuted(t, executeC, []string{"b", "a"}, []string{"c", "d"})
+}
+
+// TestClosure finalizes a batch after the outsiders preferred to it, they should be executed with the
+// earlier batch once they have been finalized, and the later batch only contains the rest of them
+func TestClosure(t *testing.T) {
+	dm, executeC := newTestManager()
+
+	extend(dm, 1, []string{"a", "b"}, []tp.Edge{{From: "x", To: "b"}}, nil)
+	expectExecuted(t, executeC)
+
+	extend(dm, 2, []string{"y", "x"}, []tp.Edge{{From: "z", To: "x"}}, nil)
+	expectExecuted(t, executeC)
+
+	extend(dm, 3, []string{"z"}, nil, nil)
+	expectExecuted(t, executeC, []string{"a", "y", "z", "x", "b"}, nil, nil)
+}
+
+// TestPending blocks the batches with pending pairs, the edge is recorded only if the outsider is preferred
+func TestPending(t *testing.T) {
+	dm, executeC := newTestManager()
+
+	extend(dm, 1, []string{"a"}, nil, []tp.Pair{{Former: "o", Latter: "a"}})
+	extend(dm, 2, []string{"b"}, nil, []tp.Pair{{Former: "p", Latter: "b"}})
+	extend(dm, 3, []string{"p", "o"}, nil, nil)
+	expectExecuted(t, executeC)
+
+	// the finalized one is preferred, and the pair is released without any edge
+	dm.Resolve(tp.Edge{From: "a", To: "o"})
+	dm.Execute()
+	expectExecuted(t, executeC, []string{"a"})
+
+	// the outsider is preferred, so that it should be executed before the finalized one
+	dm.Resolve(tp.Edge{From: "p", To: "b"})
+	dm.Execute()
+	expectExecuted(t, executeC, []string{"p", "b"}, []string{"o"})
+}
+
+// TestResolveBeforePending resolves the pending pair before the batch containing it has been received
+func TestResolveBeforePending(t *testing.T) {
+	dm, executeC := newTestManager()
+
+	dm.Resolve(tp.Edge{From: "o", To: "a"})
+	extend(dm, 1, []string{"a"}, nil, []tp.Pair{{Former: "o", Latter: "a"}})
+	expectExecuted(t, executeC)
+
+	extend(dm, 2, []string{"o"}, nil, nil)
+	expectExecuted(t, executeC, []string{"o", "a"}, nil)
+}
+
+// TestCondenseCycle executes the transactions in a cycle across batches in the order of finalization
+func TestCondenseCycle(t *testing.T) {
+	dm, executeC := newTestManager()
+
+	extend(dm, 1, []string{"b", "a"}, []tp.Edge{{From: "y", To: "b"}}, nil)
+	extend(dm, 2, []string{"x", "y"}, []tp.Edge{{From: "a", To: "x"}}, nil)
+	expectExecuted(t, executeC, []string{"b", "a", "x", "y"}, nil)
+
+	closure, ok := dm.closure([]string{"c"})
+	if ok || closure != nil {
+		t.Fatalf("closure of unknown tx %v", closure)
+	}
+}
+
+// TestStopExecuting stops the DAG manager while nobody is receiving the executable batches
+func TestStopExecuting(t *testing.T) {
+	closeC := make(chan bool)
+	dm := newDAGManagerImpl(0, make(chan tp.ExecuteEvent), closeC, testLogger{})
+	dm.Extend(&types.DAGValue{Seq: 1, Order: []string{"a"}})
+
+	executed := make(chan bool)
+	go func() {
+		dm.Execute()
+		close(executed)
+	}()
+	close(closeC)
+	select {
+	case <-executed:
+	case <-time.After(time.Second):
+		t.Fatal("execution is blocked after stop")
+	}
+}
+
+// TestExpireExecuted executes tx a in batch 1, the duplicated one finalized in later batches should be skipped
+// until DefaultExecutedBatches batches later
+func TestExpireExecuted(t *testing.T) {
+	dm, executeC := newTestManager()
+
+	dm.Resolve(tp.Edge{From: "o", To: "a"})
+	extend(dm, 1, []string{"a"}, nil, nil)
+	expectExecuted(t, executeC, []string{"a"})
+
+	seq := uint64(2)
+	for ; seq <= types.DefaultExecutedBatches; seq++ {
+		extend(dm, seq, []string{fmt.Sprintf("tx-%d", seq)}, nil, nil)
+		<-executeC
+	}
+	extend(dm, seq, []string{"a"}, nil, nil)
+	expectExecuted(t, executeC, nil)
+
+	if dm.executed["a"] || len(dm.executed) != types.DefaultExecutedBatches-1 || len(dm.resolved) != 0 {
+		t.Fatalf("keep %d executed marks and %d resolved relations", len(dm.executed), len(dm.resolved))
+	}
+	extend(dm, seq+1, []string{"a"}, nil, nil)
+	expectExecuted(t, executeC, []string{"a"})
+}
@@ -1,5 +1,87 @@
 package dagmanager
 
-type DAGProcessor struct {
+import (
+	"github.com/Grivn/libfalanx/dagmanager/types"
+	"github.com/Grivn/libfalanx/logger"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
 
-}
\ No newline at end of file
+type dagProcessor struct {
+	manager DAGManager
+
+	// recvC:     receive the finalized batches from graph engine
+	// resolveC:  receive the determined relations of pending pairs from filter
+	// transferC: receive the batches executed by state transfer
+	recvC     chan *types.DAGValue
+	resolveC  chan tp.Edge
+	transferC chan tp.TransferEvent
+	close     chan bool
+
+	// metricsC is used to receive the queries for the amount of states kept by DAG manager
+	metricsC chan chan types.Metrics
+
+	logger logger.Logger
+}
+
+func newDAGProcessor(c types.Config) *dagProcessor {
+	closeC := make(chan bool)
+	return &dagProcessor{
+		manager:   newDAGManagerImpl(c.Sequence, c.ExecuteC, closeC, c.Logger),
+		recvC:     c.RecvC,
+		resolveC:  c.ResolveC,
+		transferC: c.TransferC,
+		close:     closeC,
+		metricsC:  make(chan chan types.Metrics),
+		logger:    c.Logger,
+	}
+}
+
+func (dp *dagProcessor) start() {
+	go dp.listener()
+}
+
+func (dp *dagProcessor) stop() {
+	close(dp.close)
+}
+
+// metrics is used to query the amount of states kept by DAG manager, the query is answered by the listener
+// so that it won't race with the processing of batches. it returns the empty metrics once the DAG manager
+// has been stopped.
+func (dp *dagProcessor) metrics() types.Metrics {
+	replyC := make(chan types.Metrics, 1)
+	select {
+	case dp.metricsC <- replyC:
+	case <-dp.close:
+		return types.Metrics{}
+	}
+	select {
+	case metrics := <-replyC:
+		return metrics
+	case <-dp.close:
+		return types.Metrics{}
+	}
+}
+
+func (dp *dagProcessor) listener() {
+	for {
+		select {
+		case <-dp.close:
+			return
+
+		case value := <-dp.recvC:
+			dp.manager.Extend(value)
+			dp.manager.Execute()
+
+		case edge := <-dp.resolveC:
+			dp.manager.Resolve(edge)
+			dp.manager.Execute()
+
+		case event := <-dp.transferC:
+			dp.manager.Transfer(event)
+			dp.manager.Execute()
+
+		case replyC := <-dp.metricsC:
+			replyC <- dp.manager.Metrics()
+		}
+	}
+}
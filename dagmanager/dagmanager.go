@@ -0,0 +1,20 @@
+package dagmanager
+
+import "github.com/Grivn/libfalanx/dagmanager/types"
+
+func NewDAGProcessor(c types.Config) *dagProcessor {
+	return newDAGProcessor(c)
+}
+
+func (dp *dagProcessor) Start() {
+	dp.start()
+}
+
+func (dp *dagProcessor) Stop() {
+	dp.stop()
+}
+
+// Metrics is used to inspect the amount of states kept by DAG manager
+func (dp *dagProcessor) Metrics() types.Metrics {
+	return dp.metrics()
+}
@@ -1,14 +1,26 @@
 package dagmanager
 
-import "github.com/Grivn/libfalanx/dagmanager/types"
+import (
+	"github.com/Grivn/libfalanx/dagmanager/types"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
 
 type DAGManager interface {
 	// Extend is used to extend DAG graph
 	Extend(value *types.DAGValue)
 
+	// Resolve is used to record the relation of a pending pair which has been determined
+	Resolve(edge tp.Edge)
+
+	// Transfer is used to drop the transactions which have been executed by state transfer
+	Transfer(event tp.TransferEvent)
+
 	// Execute is used to fetch values from DAG to call execute
 	Execute()
 
+	// Metrics is used to inspect the amount of states kept by DAG manager
+	Metrics() types.Metrics
+
 	// GetGraph is used to get the graph of DAG
 	GetGraph() *types.DAG
 }
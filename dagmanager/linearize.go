@@ -0,0 +1,153 @@
+package dagmanager
+
+import (
+	"sort"
+
+	"github.com/Grivn/libfalanx/dagmanager/types"
+)
+
+// linearize is used to generate the executed order for the transactions in closure, in the same way as the
+// graph engine does for a batch.
+//
+//  1. tarjan algorithm is used to find the strongly connected components, the transactions in one component
+//     have constructed a cycle across batches and they cannot be ordered by the relations.
+//  2. every component is collapsed into a vertex, and the DAG of components is ordered by topological
+//     sorting. if there are several components could be selected, we will choose the one containing the
+//     transaction finalized earlier.
+//  3. the transactions in one component are ordered by the batch which finalized them, and their index in
+//     the linear order of batch.
+//
+// the result only depends on the vertices and edges in closure, so that every replica will generate the
+// same order for the same finalized batches.
+func (dm *dagManagerImpl) linearize(closure map[string]bool) []string {
+	vertices := make([]*types.Vertex, 0, len(closure))
+	for txHash := range closure {
+		vertices = append(vertices, dm.graph.Vertices[txHash])
+	}
+	sort.Slice(vertices, func(i, j int) bool { return earlier(vertices[i], vertices[j]) })
+
+	ids := make(map[string]int)
+	for id, vertex := range vertices {
+		ids[vertex.Hash] = id
+	}
+	successors := make([][]int, len(vertices))
+	for id, vertex := range vertices {
+		for to := range dm.graph.Edges[vertex.Hash] {
+			if next, ok := ids[to]; ok {
+				successors[id] = append(successors[id], next)
+			}
+		}
+		sort.Ints(successors[id])
+	}
+
+	components, cMap := tarjan(successors)
+
+	// the vertices are sorted, so that the minimum id in component is the earliest transaction in it
+	inDegree := make([]int, len(components))
+	edges := make([]map[int]bool, len(components))
+	for index := range components {
+		edges[index] = make(map[int]bool)
+	}
+	for from, list := range successors {
+		for _, to := range list {
+			cFrom, cTo := cMap[from], cMap[to]
+			if cFrom == cTo || edges[cFrom][cTo] {
+				continue
+			}
+			edges[cFrom][cTo] = true
+			inDegree[cTo]++
+		}
+	}
+	minimum := make([]int, len(components))
+	for index, component := range components {
+		sort.Ints(component)
+		minimum[index] = component[0]
+	}
+
+	var order []string
+	var ready []int
+	for index := range components {
+		if inDegree[index] == 0 {
+			ready = append(ready, index)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return minimum[ready[i]] < minimum[ready[j]] })
+		current := ready[0]
+		ready = ready[1:]
+		for _, id := range components[current] {
+			order = append(order, vertices[id].Hash)
+		}
+		for next := range edges[current] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+	return order
+}
+
+// tarjan is used to find the strongly connected components of the graph, and the index of component which
+// contains every vertex
+func tarjan(successors [][]int) ([][]int, []int) {
+	var (
+		dfn        = make([]int, len(successors))
+		low        = make([]int, len(successors))
+		pushed     = make([]bool, len(successors))
+		cMap       = make([]int, len(successors))
+		stack      []int
+		components [][]int
+		counter    int
+		visit      func(id int)
+	)
+	visit = func(id int) {
+		counter++
+		dfn[id], low[id] = counter, counter
+		pushed[id] = true
+		stack = append(stack, id)
+
+		for _, next := range successors[id] {
+			if dfn[next] == 0 {
+				visit(next)
+				if low[next] < low[id] {
+					low[id] = low[next]
+				}
+			} else if pushed[next] && dfn[next] < low[id] {
+				low[id] = dfn[next]
+			}
+		}
+
+		if dfn[id] != low[id] {
+			return
+		}
+
+		// current vertex is the root of a strongly connected component
+		var component []int
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			pushed[top] = false
+			cMap[top] = len(components)
+			component = append(component, top)
+			if top == id {
+				break
+			}
+		}
+		components = append(components, component)
+	}
+	for id := range successors {
+		if dfn[id] == 0 {
+			visit(id)
+		}
+	}
+	return components, cMap
+}
+
+// earlier checks whether the vertex a has been finalized before b
+func earlier(a, b *types.Vertex) bool {
+	if a.Seq != b.Seq {
+		return a.Seq < b.Seq
+	}
+	return a.Index < b.Index
+}
@@ -1,9 +1,74 @@
 package types
 
+import (
+	"github.com/Grivn/libfalanx/logger"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// Config is used to initiate the DAG manager
+// Sequence:  the latest batch finalized before restart, the batches will be executed from the next one
+// TransferC: receive the batches executed by state transfer, it could be nil if the state transfer is disabled
+// ExecuteC:  post the finalized batches in the order of sequence number, with the transactions which should
+//            be executed before them
+type Config struct {
+	Sequence  uint64
+	RecvC     chan *DAGValue
+	ResolveC  chan tp.Edge
+	TransferC chan tp.TransferEvent
+	ExecuteC  chan tp.ExecuteEvent
+	Logger    logger.Logger
+}
+
+// DefaultExecutedBatches is the amount of batches to keep the executed marks of transactions for, which
+// should cover the tombstones kept by filter, so that the transactions finalized once again with the late
+// logs arriving after their tombstones have expired will still be skipped
+const DefaultExecutedBatches = 128
+
+// Metrics is the amount of states kept by DAG manager
+// Batches:  the finalized batches which haven't been executed
+// Vertices: the finalized transactions which haven't been executed
+// Edges:    the transactions which should be executed before some others
+// Pending:  the pending pairs which haven't been resolved
+// Resolved: the determined relations whose pending pair hasn't been received
+// Executed: the executed transactions whose marks are kept to skip the duplicated ones
+type Metrics struct {
+	Batches  int
+	Vertices int
+	Edges    int
+	Pending  int
+	Resolved int
+	Executed int
+}
+
+// DAG contains the finalized transactions which haven't been executed
+// Vertices: the finalized transactions
+// Edges:    from ==> to, 'from' should be executed before 'to', the vertices might not be finalized yet, and
+//           the edges might construct a cycle across batches
+// Pending:  the pairs whose relation hasn't been determined, the transactions in them cannot be executed. the
+//           'Former' is the outsider which might be preferred to the finalized 'Latter'
 type DAG struct {
+	Vertices map[string]*Vertex
+	Edges    map[string]map[string]bool
+	Pending  map[tp.Pair]bool
+}
 
+// Vertex is a finalized transaction
+// Seq:   the sequence number of batch which finalized it
+// Index: the index of it in the linear order of batch
+type Vertex struct {
+	Hash  string
+	Seq   uint64
+	Index int
 }
 
+// DAGValue is a finalized batch used to extend DAG
+// Seq:      the sequence number of the batch
+// Order:    the linear order of transactions in batch
+// External: the relations preferring the transactions which haven't been finalized to the ones in batch
+// Pending:  the pairs of transactions whose relation hasn't been determined
 type DAGValue struct {
-
+	Seq      uint64
+	Order    []string
+	External []tp.Edge
+	Pending  []tp.Pair
 }
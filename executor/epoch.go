@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Grivn/libfalanx/executor/types"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// reconfigure is used to record the config transaction proposed by some replica of current epoch, and the
+// new epoch will be agreed once f+1 replicas have proposed the same replica set for it, as at least one of
+// them must be correct. the executed order is the same on all the replicas, so that they will switch to the
+// new epoch after the same batch.
+func (ep *executeProcessor) reconfigure(reconfig *pb.Reconfig, seq uint64) (tp.EpochEvent, bool) {
+	if reconfig.Epoch != ep.epoch+1 {
+		ep.logger.Warningf("[EXEC] ignore reconfiguration for epoch %d from replica %d, current epoch %d", reconfig.Epoch, reconfig.ReplicaId, ep.epoch)
+		return tp.EpochEvent{}, false
+	}
+	if !ep.replicas[reconfig.ReplicaId] {
+		ep.logger.Warningf("[EXEC] ignore reconfiguration from replica %d which is not in epoch %d", reconfig.ReplicaId, ep.epoch)
+		return tp.EpochEvent{}, false
+	}
+	if err := zcommon.VerifyReconfig(ep.tools, reconfig); err != nil {
+		ep.logger.Warningf("[EXEC] ignore reconfiguration from replica %d: %s", reconfig.ReplicaId, err)
+		return tp.EpochEvent{}, false
+	}
+	replicas, err := checkReplicas(reconfig.Replicas)
+	if err != nil {
+		ep.logger.Warningf("[EXEC] ignore reconfiguration from replica %d: %s", reconfig.ReplicaId, err)
+		return tp.EpochEvent{}, false
+	}
+
+	key := fmt.Sprint(reconfig.Replicas)
+	proposers, ok := ep.reconfigs[key]
+	if !ok {
+		proposers = make(map[uint64]bool)
+		ep.reconfigs[key] = proposers
+	}
+	proposers[reconfig.ReplicaId] = true
+
+	f := int(math.Floor((float64(len(ep.replicas)) - 1) / 4))
+	if f == 0 {
+		f = 1
+	}
+	if len(proposers) < f+1 {
+		ep.logger.Infof("[EXEC] reconfiguration %v for epoch %d has been proposed by %d replicas", replicas, reconfig.Epoch, len(proposers))
+		return tp.EpochEvent{}, false
+	}
+
+	// the new epoch is recorded at once, so that the config transactions for it in the same batch would be
+	// ignored, while it will be posted after the batch has been committed
+	ep.epoch = reconfig.Epoch
+	ep.replicas = make(map[uint64]bool)
+	for _, id := range replicas {
+		ep.replicas[uint64(id)] = true
+	}
+	ep.reconfigs = make(map[string]map[uint64]bool)
+	ep.logger.Infof("[EXEC] epoch %d has been agreed in batch %d, replicas %v", reconfig.Epoch, seq, replicas)
+	return tp.EpochEvent{Epoch: reconfig.Epoch, Seq: seq, Replicas: replicas}, true
+}
+
+// switchEpoch is used to post the new epoch once the batch which completes the reconfiguration has been
+// committed, so that the other modules could switch to it at the batch boundary
+func (ep *executeProcessor) switchEpoch(event tp.EpochEvent) {
+	if ep.epochC == nil {
+		return
+	}
+	select {
+	case ep.epochC <- event:
+	case <-ep.close:
+	}
+}
+
+// checkReplicas is used to check whether the replica set is valid, the replicas should be listed in
+// ascending order without duplication
+func checkReplicas(ids []uint64) ([]int, error) {
+	if len(ids) < types.MinReplicas {
+		return nil, fmt.Errorf("%d replicas cannot tolerate any fault", len(ids))
+	}
+	var replicas []int
+	for index, id := range ids {
+		if id == 0 || (index > 0 && id <= ids[index-1]) {
+			return nil, fmt.Errorf("invalid replica list %v", ids)
+		}
+		replicas = append(replicas, int(id))
+	}
+	return replicas, nil
+}
@@ -0,0 +1,15 @@
+package executor
+
+import "github.com/Grivn/libfalanx/executor/types"
+
+func NewExecuteProcessor(c types.Config) *executeProcessor {
+	return newExecuteProcessor(c)
+}
+
+func (ep *executeProcessor) Start() {
+	ep.start()
+}
+
+func (ep *executeProcessor) Stop() {
+	ep.stop()
+}
@@ -1,5 +1,461 @@
 package executor
 
-type ExecuteProcessor struct {
+import (
+	"github.com/Grivn/libfalanx/api"
+	"github.com/Grivn/libfalanx/executor/types"
+	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
 
+	"github.com/gogo/protobuf/proto"
+)
+
+type executeProcessor struct {
+	id uint64
+
+	// seqNo indicates the latest executed batch
+	seqNo uint64
+
+	// dagSeq indicates the latest finalized batch consumed from DAG manager, which differs from seqNo once some
+	// batches have been executed by state transfer or skipped, as the batches from DAG manager will be
+	// renumbered after them
+	dagSeq uint64
+
+	// cache is used to store the batches from DAG manager which cannot be executed because of its sequence number
+	cache map[uint64]tp.ExecuteEvent
+
+	// state transfer
+	// transfers:   the batches fetched by state transfer, they will be executed before the ones from DAG manager
+	// transferred: the transactions executed by state transfer, they will be skipped once they have been
+	//              ordered by DAG manager
+	// transferC:   channel used to receive the batches fetched by state transfer
+	transfers   []tp.ExecuteEvent
+	transferred map[string]bool
+	transferC   chan tp.TransferEvent
+
+	// executor is the state machine of application
+	// txContainer is used to find the payload of transactions
+	executor    api.Executor
+	txContainer api.TxsContainer
+
+	// commitC is used to notify the application of the committed batches
+	// it is a bounded channel, and the processor will be blocked until the application has received
+	// the commit event, so that a slow application will stall the ordering pipeline instead of losing
+	// any committed batch
+	recvC   chan tp.ExecuteEvent
+	commitC chan *tp.CommitEvent
+	close   chan bool
+
+	// executedC is used to report the executed batches, so that the states for them could be collected
+	executedC chan tp.ExecuteEvent
+
+	// epoch
+	// the replica set will be switched once f+1 replicas of current epoch have proposed the same config
+	// transaction, and the new epoch will take effect after the batch which completes the reconfiguration
+	// epoch:     the current epoch
+	// replicas:  the replicas of current epoch
+	// reconfigs: the replicas which have proposed particular replica set for next epoch
+	// epochC:    post the new epoch once it has been agreed
+	epoch     uint64
+	replicas  map[uint64]bool
+	reconfigs map[string]map[uint64]bool
+	epochC    chan tp.EpochEvent
+
+	// clients is used to record the client of every transaction, so that we could reply to it once the
+	// transaction has been executed, the entries are dropped once the transactions have been executed or their
+	// payloads have been missing for a checkpoint interval
+	// absent records the transactions whose payload was missing at the latest stable checkpoint
+	// reqC is used to receive the ordered requests from clients order
+	// selfC is used to deliver the replies for the client of current replica
+	clients map[string]uint64
+	absent  map[string]bool
+	reqC    chan *pb.OrderedReq
+	selfC   chan *pb.Reply
+
+	// payload fetch
+	// the next batch will not be executed until the payloads of all its transactions have been found, and
+	// the missing ones will be fetched from other replicas
+	// missing:   the transactions in next batch whose payload hasn't been found
+	// fetchSeq:  the sequence number of the batch whose payloads are being fetched
+	// fetching:  whether the fetch timer has been armed for fetchSeq
+	// attempts:  the amount of fetch requests sent for next batch, the first one will be sent to the clients
+	//            of the transactions, and the others will be sent to the replicas in turn
+	// executed:  the payloads of executed transactions, which are kept to serve the fetch requests until their
+	//            batch has been covered by the stable checkpoint
+	// unstable:  the executed batches which haven't been covered by the stable checkpoint
+	// stableC:   channel used to receive the sequence number of the stable checkpoint
+	// fetchC:    channel used to receive the fetch requests from other replicas
+	// responseC: channel used to receive the fetched payloads
+	missing   map[string]bool
+	fetchSeq  uint64
+	fetching  bool
+	attempts  int
+	executed  map[string]*pb.Transaction
+	unstable  []tp.ExecuteEvent
+	stableC   chan uint64
+	timeoutC  chan uint64
+	fetchC    chan *pb.TxFetch
+	responseC chan *pb.TxSet
+
+	sender network.Network
+	tools  zcommon.Tools
+	logger logger.Logger
+}
+
+func newExecuteProcessor(c types.Config) *executeProcessor {
+	replicas := make(map[uint64]bool)
+	for _, id := range c.Replicas {
+		replicas[uint64(id)] = true
+	}
+
+	return &executeProcessor{
+		id:          c.ID,
+		seqNo:       c.Seq,
+		dagSeq:      c.DAGSeq,
+		cache:       make(map[uint64]tp.ExecuteEvent),
+		transferred: make(map[string]bool),
+		transferC:   c.TransferC,
+		executor:    c.Executor,
+		txContainer: c.TxContainer,
+		recvC:       c.RecvC,
+		commitC:     c.CommitC,
+		close:       make(chan bool),
+		executedC:   c.ExecutedC,
+		replicas:    replicas,
+		reconfigs:   make(map[string]map[uint64]bool),
+		epochC:      c.EpochC,
+		clients:     make(map[string]uint64),
+		absent:      make(map[string]bool),
+		reqC:        c.ReqC,
+		selfC:       c.SelfC,
+		missing:     make(map[string]bool),
+		executed:    make(map[string]*pb.Transaction),
+		stableC:     c.StableC,
+		timeoutC:    make(chan uint64),
+		fetchC:      c.FetchC,
+		responseC:   c.ResponseC,
+		sender:      c.Sender,
+		tools:       c.Tools,
+		logger:      c.Logger,
+	}
+}
+
+func (ep *executeProcessor) start() {
+	go ep.listener()
+}
+
+func (ep *executeProcessor) stop() {
+	close(ep.close)
+}
+
+func (ep *executeProcessor) listener() {
+	for {
+		select {
+		case <-ep.close:
+			return
+
+		case event := <-ep.recvC:
+			ep.cacheBatch(event)
+			ep.executeCachedBatches()
+
+		case event := <-ep.transferC:
+			ep.transfer(event)
+			ep.executeCachedBatches()
+
+		case req := <-ep.reqC:
+			ep.recordClient(req)
+
+		case fetch := <-ep.fetchC:
+			ep.serveFetch(fetch)
+
+		case response := <-ep.responseC:
+			ep.receivePayloads(response)
+			ep.executeCachedBatches()
+
+		case seq := <-ep.timeoutC:
+			ep.fetchTimeout(seq)
+
+		case seq := <-ep.stableC:
+			ep.stable(seq)
+		}
+	}
+}
+
+// stable is used to drop the payloads of the transactions in the batches covered by the stable checkpoint, the
+// lagging replicas will fetch the stable batches by state transfer, and the payloads from their clients. the
+// pins of these transactions are dropped as well, as they might have been paved once again after execution
+func (ep *executeProcessor) stable(seq uint64) {
+	index := 0
+	for ; index < len(ep.unstable) && ep.unstable[index].Seq <= seq; index++ {
+		for _, txHash := range ep.unstable[index].TxHashes {
+			delete(ep.executed, txHash)
+			ep.txContainer.Unpin(txHash)
+		}
+	}
+	ep.unstable = ep.unstable[index:]
+	ep.collectClients()
+}
+
+// recordClient is used to record the client of the transactions in the ordered request, the ones which have
+// been executed are skipped, as the request has arrived too late to be replied
+func (ep *executeProcessor) recordClient(req *pb.OrderedReq) {
+	for _, txHash := range req.TxHashList {
+		if ep.executed[txHash] != nil {
+			continue
+		}
+		ep.clients[txHash] = req.ClientId
+	}
+}
+
+// collectClients is used to drop the clients of the transactions whose payloads have been missing from the
+// container since the previous stable checkpoint, such as the ones evicted by the container or never sent by
+// their clients, so that they won't be kept forever. the payload might arrive after the ordered request, so
+// that the entry is dropped only if the payload is still missing at the next stable checkpoint.
+func (ep *executeProcessor) collectClients() {
+	absent := make(map[string]bool)
+	for txHash := range ep.clients {
+		if ep.txContainer.Get(txHash) != nil {
+			continue
+		}
+		if ep.absent[txHash] {
+			delete(ep.clients, txHash)
+			continue
+		}
+		absent[txHash] = true
+	}
+	ep.absent = absent
+}
+
+func (ep *executeProcessor) cacheBatch(event tp.ExecuteEvent) {
+	if event.Seq <= ep.dagSeq {
+		ep.logger.Warningf("[EXEC] batch %d has already been executed", event.Seq)
+		return
+	}
+	if _, ok := ep.cache[event.Seq]; ok {
+		ep.logger.Warningf("[EXEC] duplicated batch %d", event.Seq)
+		return
+	}
+	ep.cache[event.Seq] = event
+}
+
+func (ep *executeProcessor) executeCachedBatches() {
+	for {
+		event, ok := ep.next()
+		if !ok {
+			return
+		}
+		if !ep.ready(event) {
+			ep.fetchPayloads(event)
+			return
+		}
+		ep.consume()
+		ep.execute(event)
+		ep.seqNo = event.Seq
+	}
+}
+
+// next is used to find the next batch to execute, the batches fetched by state transfer have priority, and
+// the transactions which have been executed with them will be removed from the batches of DAG manager. the
+// batches without any transaction to execute are skipped, as their transactions might have been executed
+// with an earlier batch
+func (ep *executeProcessor) next() (tp.ExecuteEvent, bool) {
+	if len(ep.transfers) > 0 {
+		return ep.transfers[0], true
+	}
+
+	for {
+		event, ok := ep.cache[ep.dagSeq+1]
+		if !ok {
+			return tp.ExecuteEvent{}, false
+		}
+		var txHashes []string
+		for _, txHash := range event.TxHashes {
+			if !ep.transferred[txHash] {
+				txHashes = append(txHashes, txHash)
+			}
+		}
+		if len(txHashes) > 0 {
+			return tp.ExecuteEvent{Seq: ep.seqNo + 1, TxHashes: txHashes}, true
+		}
+		ep.logger.Infof("[EXEC] skip batch %d from DAG manager, all its txs have been executed", event.Seq)
+		ep.consume()
+	}
+}
+
+// consume is used to drop the batch returned by next once it has been executed
+func (ep *executeProcessor) consume() {
+	if len(ep.transfers) > 0 {
+		ep.transfers = ep.transfers[1:]
+		return
+	}
+
+	// the transactions executed by state transfer might have been pinned by paving once again
+	event := ep.cache[ep.dagSeq+1]
+	for _, txHash := range event.TxHashes {
+		if ep.transferred[txHash] {
+			ep.txContainer.Unpin(txHash)
+		}
+		delete(ep.transferred, txHash)
+	}
+	delete(ep.cache, event.Seq)
+	ep.dagSeq = event.Seq
+}
+
+// transfer is used to queue the batches fetched by state transfer, they must follow the latest executed batch
+// immediately, or the executed order would diverge from the stable checkpoint
+func (ep *executeProcessor) transfer(event tp.TransferEvent) {
+	if len(ep.transfers) > 0 {
+		ep.logger.Warningf("[EXEC] ignore state transfer up to %d, the previous one hasn't been finished", event.Seq)
+		return
+	}
+
+	var batches []tp.ExecuteEvent
+	for _, batch := range event.Batches {
+		if batch.Seq > ep.seqNo {
+			batches = append(batches, batch)
+		}
+	}
+	if len(batches) == 0 {
+		return
+	}
+	if batches[0].Seq != ep.seqNo+1 {
+		ep.logger.Warningf("[EXEC] ignore state transfer from batch %d, expect %d", batches[0].Seq, ep.seqNo+1)
+		return
+	}
+
+	ep.logger.Infof("[EXEC] state transfer from batch %d to %d", batches[0].Seq, event.Seq)
+	ep.transfers = batches
+	for _, batch := range batches {
+		for _, txHash := range batch.TxHashes {
+			ep.transferred[txHash] = true
+			// the payloads of transferred txs will be fetched, and they shouldn't be rejected by container
+			ep.txContainer.Pin(txHash)
+		}
+	}
+}
+
+func (ep *executeProcessor) execute(event tp.ExecuteEvent) {
+	ep.logger.Infof("============================ Execute batch %d ============================", event.Seq)
+
+	var executed []string
+	var kept []string
+	var configs []string
+	var payloads [][]byte
+	var epoch *tp.EpochEvent
+	txs := make([]*pb.Transaction, len(event.TxHashes))
+	for index, txHash := range event.TxHashes {
+		tx := ep.txContainer.Get(txHash)
+		if tx == nil {
+			ep.logger.Warningf("[EXEC] cannot find payload of %s, batch %d", txHash, event.Seq)
+			continue
+		}
+		ep.logger.Infof("[EXEC] %s", txHash)
+		txs[index] = tx
+		ep.executed[txHash] = tx
+		kept = append(kept, txHash)
+
+		// the config transactions are executed by falanx itself instead of the application
+		if tx.Reconfig != nil {
+			if next, ok := ep.reconfigure(tx.Reconfig, event.Seq); ok {
+				epoch = &next
+			}
+			configs = append(configs, txHash)
+			continue
+		}
+		executed = append(executed, txHash)
+		payloads = append(payloads, tx.Payload)
+	}
+
+	var results [][]byte
+	if ep.executor != nil {
+		results = ep.executor.Execute(payloads)
+	}
+
+	for index, txHash := range executed {
+		var result []byte
+		if index < len(results) {
+			result = results[index]
+		}
+		ep.reply(txHash, result)
+	}
+	for _, txHash := range configs {
+		ep.reply(txHash, nil)
+	}
+
+	for _, txHash := range append(executed, configs...) {
+		if err := ep.txContainer.Remove(txHash); err != nil {
+			ep.logger.Warningf("[EXEC] remove executed tx %s failed: %s", txHash, err)
+		}
+	}
+
+	ep.unstable = append(ep.unstable, tp.ExecuteEvent{Seq: event.Seq, TxHashes: kept})
+	ep.commit(event, txs)
+	ep.report(event)
+	if epoch != nil {
+		ep.switchEpoch(*epoch)
+	}
+}
+
+func (ep *executeProcessor) commit(event tp.ExecuteEvent, txs []*pb.Transaction) {
+	commit := &tp.CommitEvent{
+		Seq:      event.Seq,
+		TxHashes: event.TxHashes,
+		Txs:      txs,
+	}
+	select {
+	case ep.commitC <- commit:
+	case <-ep.close:
+	}
+}
+
+func (ep *executeProcessor) report(event tp.ExecuteEvent) {
+	if ep.executedC == nil {
+		return
+	}
+	select {
+	case ep.executedC <- event:
+	case <-ep.close:
+	}
+}
+
+// reply is used to send the signed execution result to the client of the transaction
+func (ep *executeProcessor) reply(txHash string, result []byte) {
+	client, ok := ep.clients[txHash]
+	if !ok {
+		ep.logger.Debugf("[EXEC] unknown client for tx %s, skip reply", txHash)
+		return
+	}
+	delete(ep.clients, txHash)
+
+	reply := &pb.Reply{
+		ReplicaId: ep.id,
+		ClientId:  client,
+		TxHash:    txHash,
+		Result:    result,
+	}
+	if err := zcommon.SignReply(ep.tools, reply); err != nil {
+		ep.logger.Errorf("[EXEC] sign reply failed: %s", err)
+		return
+	}
+
+	if client == ep.id {
+		select {
+		case ep.selfC <- reply:
+		case <-ep.close:
+		}
+		return
+	}
+
+	payload, err := proto.Marshal(reply)
+	if err != nil {
+		ep.logger.Errorf("[EXEC] marshal reply failed: %s", err)
+		return
+	}
+	msg := &pb.ConsensusMessage{
+		Type:    pb.Type_REPLY,
+		Payload: payload,
+	}
+	ep.sender.Unicast(client, msg)
 }
@@ -0,0 +1,188 @@
+package executor
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"testing"
+
+	"github.com/Grivn/libfalanx/executor/types"
+	"github.com/Grivn/libfalanx/txcontainer"
+	containerType "github.com/Grivn/libfalanx/txcontainer/types"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// testLogger drops the logs, the failures are reported by the tests themselves
+type testLogger struct{}
+
+func (testLogger) Debug(v ...interface{})                   {}
+func (testLogger) Debugf(format string, v ...interface{})   {}
+func (testLogger) Info(v ...interface{})                    {}
+func (testLogger) Infof(format string, v ...interface{})    {}
+func (testLogger) Warning(v ...interface{})                 {}
+func (testLogger) Warningf(format string, v ...interface{}) {}
+func (testLogger) Error(v ...interface{})                   {}
+func (testLogger) Errorf(format string, v ...interface{})   {}
+
+// testSender drops the messages sent by the processor
+type testSender struct{}
+
+func (testSender) Broadcast(msg *pb.ConsensusMessage)          {}
+func (testSender) Unicast(to uint64, msg *pb.ConsensusMessage) {}
+
+func newTestProcessor(t *testing.T) *executeProcessor {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := zcommon.NewEd25519Signer(private, map[uint64]ed25519.PublicKey{1: public})
+	tools := zcommon.NewTools(pb.HashAlgorithm_HASH_UNSPECIFIED, signer)
+	return newExecuteProcessor(types.Config{
+		ID:          1,
+		Replicas:    []int{1, 2, 3, 4},
+		TxContainer: txcontainer.NewTxContainer(containerType.Config{Tools: tools, Logger: testLogger{}}),
+		CommitC:     make(chan *tp.CommitEvent, 1000),
+		Sender:      testSender{},
+		Tools:       tools,
+		Logger:      testLogger{},
+	})
+}
+
+// propose is used to put the transactions into container, and it returns their hashes
+func propose(t *testing.T, ep *executeProcessor, tag string, amount int) []string {
+	var txHashes []string
+	for index := 0; index < amount; index++ {
+		tx := &pb.Transaction{Payload: []byte(fmt.Sprintf("%s-%d", tag, index))}
+		if err := ep.txContainer.Add(1, tx); err != nil {
+			t.Fatal(err)
+		}
+		txHashes = append(txHashes, ep.tools.TransactionHash(tx))
+	}
+	return txHashes
+}
+
+// TestStablePayloads keeps executing batches, the payloads kept to serve the fetch requests should be
+// dropped once their batches have been covered by the stable checkpoint
+func TestStablePayloads(t *testing.T) {
+	ep := newTestProcessor(t)
+	defer ep.stop()
+
+	const (
+		batches  = 100
+		batchLen = 10
+		interval = 5
+	)
+	for seq := uint64(1); seq <= batches; seq++ {
+		ep.cacheBatch(tp.ExecuteEvent{Seq: seq, TxHashes: propose(t, ep, fmt.Sprint(seq), batchLen)})
+		ep.executeCachedBatches()
+		<-ep.commitC
+		if seq%interval == 0 {
+			ep.stable(seq)
+		}
+		if len(ep.executed) > interval*batchLen {
+			t.Fatalf("batch %d: keep %d payloads, expect at most %d", seq, len(ep.executed), interval*batchLen)
+		}
+	}
+	if len(ep.executed) != 0 || len(ep.unstable) != 0 {
+		t.Fatalf("keep %d payloads of %d batches after all of them have become stable", len(ep.executed), len(ep.unstable))
+	}
+}
+
+// TestCollectClients checks that the clients are dropped once their transactions have been executed, and the
+// ones whose payloads have been missing for a checkpoint interval are dropped by the stable checkpoints
+func TestCollectClients(t *testing.T) {
+	ep := newTestProcessor(t)
+	defer ep.stop()
+
+	late := &pb.Transaction{Payload: []byte("late")}
+	lateHash := ep.tools.TransactionHash(late)
+	txHashes := propose(t, ep, "executed", 2)
+	ep.recordClient(&pb.OrderedReq{ClientId: 2, TxHashList: append(txHashes, lateHash, "never")})
+	ep.cacheBatch(tp.ExecuteEvent{Seq: 1, TxHashes: txHashes})
+	ep.executeCachedBatches()
+	<-ep.commitC
+	if len(ep.clients) != 2 {
+		t.Fatalf("keep %d clients after execution, expect 2", len(ep.clients))
+	}
+
+	// the ordered request arrives after its transactions have been executed
+	ep.recordClient(&pb.OrderedReq{ClientId: 3, TxHashList: txHashes})
+	if len(ep.clients) != 2 {
+		t.Fatalf("record clients for executed txs")
+	}
+
+	ep.stable(1)
+	if len(ep.clients) != 2 {
+		t.Fatalf("drop clients once their payloads are missing at the first checkpoint")
+	}
+
+	// the payload arrives after the ordered request
+	if err := ep.txContainer.Add(2, late); err != nil {
+		t.Fatal(err)
+	}
+	ep.stable(2)
+	if len(ep.clients) != 1 || ep.clients[lateHash] != 2 {
+		t.Fatalf("keep clients %v, expect the one of %s", ep.clients, lateHash)
+	}
+}
+
+// TestFetchPayloads checks that the batch whose payloads are missing is never executed without them, the
+// fetch requests are sent to the client at first and then to the other replicas in turn with backoff, and the
+// batch is executed once the payloads have been fetched
+func TestFetchPayloads(t *testing.T) {
+	ep := newTestProcessor(t)
+	defer ep.stop()
+	sender := &recordSender{}
+	ep.sender = sender
+
+	missing := &pb.Transaction{Payload: []byte("missing")}
+	missingHash := ep.tools.TransactionHash(missing)
+	ep.recordClient(&pb.OrderedReq{ClientId: 3, TxHashList: []string{missingHash}})
+	ep.cacheBatch(tp.ExecuteEvent{Seq: 1, TxHashes: append(propose(t, ep, "found", 2), missingHash)})
+	ep.cacheBatch(tp.ExecuteEvent{Seq: 2, TxHashes: propose(t, ep, "next", 2)})
+	ep.executeCachedBatches()
+	for attempt := 1; attempt < 10; attempt++ {
+		ep.fetchTimeout(1)
+	}
+	select {
+	case commit := <-ep.commitC:
+		t.Fatalf("batch %d is committed without its payloads", commit.Seq)
+	default:
+	}
+	if expect := "[3 2 3 4 2 3 4 2 3 4]"; fmt.Sprint(sender.targets) != expect {
+		t.Fatalf("fetch from %v, expect %v", sender.targets, expect)
+	}
+	if timeout := fetchTimeout(ep.attempts); timeout != types.MaxFetchTimeout {
+		t.Fatalf("wait %s after %d attempts, expect %s", timeout, ep.attempts, types.MaxFetchTimeout)
+	}
+
+	ep.receivePayloads(&pb.TxSet{ReplicaId: 4, Txs: []*pb.Transaction{missing}})
+	ep.executeCachedBatches()
+	commit := <-ep.commitC
+	if commit.Seq != 1 || len(commit.Txs) != 3 || commit.Txs[2] == nil {
+		t.Fatalf("unexpected commit of batch %d, txs %v", commit.Seq, commit.Txs)
+	}
+	if commit = <-ep.commitC; commit.Seq != 2 {
+		t.Fatalf("expect batch 2, got %d", commit.Seq)
+	}
+}
+
+// recordSender records the targets of the fetch requests, 0 for the broadcast ones
+type recordSender struct {
+	targets []uint64
+}
+
+func (s *recordSender) Broadcast(msg *pb.ConsensusMessage) {
+	s.record(0, msg)
+}
+
+func (s *recordSender) Unicast(to uint64, msg *pb.ConsensusMessage) {
+	s.record(to, msg)
+}
+
+func (s *recordSender) record(to uint64, msg *pb.ConsensusMessage) {
+	if msg.Type == pb.Type_TX_FETCH {
+		s.targets = append(s.targets, to)
+	}
+}
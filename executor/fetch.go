@@ -0,0 +1,210 @@
+package executor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Grivn/libfalanx/executor/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// ready checks whether the payloads of all the transactions in the batch have been found, and the missing
+// ones will be recorded
+func (ep *executeProcessor) ready(event tp.ExecuteEvent) bool {
+	ep.missing = make(map[string]bool)
+	for _, txHash := range event.TxHashes {
+		if ep.txContainer.Get(txHash) == nil {
+			ep.missing[txHash] = true
+		}
+	}
+	if len(ep.missing) == 0 {
+		return true
+	}
+	return false
+}
+
+// fetchPayloads is used to fetch the missing payloads of the batch, which will never be executed without them.
+// the first fetch request will be sent to the clients of the transactions, as they must have the payloads, and
+// the following ones will be sent to the replicas of current epoch in turn, the interval between the attempts
+// is doubled until it reaches MaxFetchTimeout, so that a payload which cannot be found for a while won't flood
+// the network
+func (ep *executeProcessor) fetchPayloads(event tp.ExecuteEvent) {
+	if ep.fetchSeq != event.Seq {
+		ep.fetchSeq = event.Seq
+		ep.fetching = false
+		ep.attempts = 0
+	}
+	if ep.fetching {
+		return
+	}
+	ep.logger.Warningf("[EXEC] batch %d is waiting for %d payloads, attempt %d", event.Seq, len(ep.missing), ep.attempts+1)
+
+	if ep.attempts == 0 {
+		targets := make(map[uint64][]string)
+		for txHash := range ep.missing {
+			targets[ep.clients[txHash]] = append(targets[ep.clients[txHash]], txHash)
+		}
+		for client, hashes := range targets {
+			if client == 0 || client == ep.id {
+				ep.sendFetch(0, hashes)
+				continue
+			}
+			ep.sendFetch(client, hashes)
+		}
+	} else {
+		var hashes []string
+		for txHash := range ep.missing {
+			hashes = append(hashes, txHash)
+		}
+		ep.sendFetch(ep.fetchTarget(), hashes)
+	}
+	ep.attempts++
+	ep.startFetchTimer(event.Seq, fetchTimeout(ep.attempts))
+}
+
+// fetchTarget is used to choose the replica of current epoch to fetch from in turn, the request will be
+// broadcast if there isn't any other replica
+func (ep *executeProcessor) fetchTarget() uint64 {
+	var replicas []uint64
+	for id := range ep.replicas {
+		if id != ep.id {
+			replicas = append(replicas, id)
+		}
+	}
+	if len(replicas) == 0 {
+		return 0
+	}
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i] < replicas[j] })
+	return replicas[(ep.attempts-1)%len(replicas)]
+}
+
+// fetchTimeout returns the duration to wait for the payloads fetched by particular attempt
+func fetchTimeout(attempts int) time.Duration {
+	timeout := types.DefaultFetchTimeout
+	for attempt := 1; attempt < attempts && timeout < types.MaxFetchTimeout; attempt++ {
+		timeout *= 2
+	}
+	if timeout > types.MaxFetchTimeout {
+		timeout = types.MaxFetchTimeout
+	}
+	return timeout
+}
+
+// sendFetch is used to send the fetch request to particular replica, or broadcast it if the target is 0
+func (ep *executeProcessor) sendFetch(target uint64, hashes []string) {
+	if ep.sender == nil {
+		return
+	}
+	sort.Strings(hashes)
+
+	fetch := &pb.TxFetch{
+		ReplicaId: ep.id,
+		TxHashes:  hashes,
+	}
+	payload, err := proto.Marshal(fetch)
+	if err != nil {
+		ep.logger.Errorf("[EXEC] marshal tx fetch failed: %s", err)
+		return
+	}
+	msg := &pb.ConsensusMessage{
+		Type:    pb.Type_TX_FETCH,
+		Payload: payload,
+	}
+	if target == 0 {
+		ep.logger.Infof("[EXEC] broadcast fetch for %d payloads", len(hashes))
+		ep.sender.Broadcast(msg)
+		return
+	}
+	ep.logger.Infof("[EXEC] fetch %d payloads from replica %d", len(hashes), target)
+	ep.sender.Unicast(target, msg)
+}
+
+func (ep *executeProcessor) startFetchTimer(seq uint64, timeout time.Duration) {
+	ep.fetching = true
+	go func() {
+		select {
+		case <-ep.close:
+		case <-time.After(timeout):
+			select {
+			case <-ep.close:
+			case ep.timeoutC <- seq:
+			}
+		}
+	}()
+}
+
+// fetchTimeout is used to fetch the payloads which are still missing once again
+func (ep *executeProcessor) fetchTimeout(seq uint64) {
+	if seq != ep.fetchSeq {
+		// the batch has been executed, and the timer for next batch might have been armed
+		return
+	}
+	ep.fetching = false
+	ep.executeCachedBatches()
+}
+
+// receivePayloads is used to verify the fetched payloads against the missing hashes, the payloads which
+// haven't been requested or don't match the hash will be ignored
+func (ep *executeProcessor) receivePayloads(response *pb.TxSet) {
+	for _, tx := range response.Txs {
+		if tx == nil {
+			continue
+		}
+		txHash := ep.tools.TransactionHash(tx)
+		if !ep.missing[txHash] {
+			continue
+		}
+		ep.logger.Debugf("[EXEC] receive payload of %s from replica %d", txHash, response.ReplicaId)
+		// the payloads referenced by paved batches have been pinned, so that they won't be rejected by container,
+		// and they are charged to the client which has signed the ordered request instead of the responder
+		if err := ep.txContainer.Add(ep.clients[txHash], tx); err != nil {
+			ep.logger.Warningf("[EXEC] store payload of %s failed: %s", txHash, err)
+			continue
+		}
+		delete(ep.missing, txHash)
+	}
+}
+
+// serveFetch is used to reply the payloads to the replica which has missed them, including the ones which
+// have been executed
+func (ep *executeProcessor) serveFetch(fetch *pb.TxFetch) {
+	if fetch.ReplicaId == ep.id || ep.sender == nil {
+		return
+	}
+
+	var txs []*pb.Transaction
+	for _, txHash := range fetch.TxHashes {
+		if len(txs) >= types.MaxFetchTxs {
+			break
+		}
+		tx := ep.txContainer.Get(txHash)
+		if tx == nil {
+			tx = ep.executed[txHash]
+		}
+		if tx != nil {
+			txs = append(txs, tx)
+		}
+	}
+	if len(txs) == 0 {
+		return
+	}
+
+	response := &pb.TxSet{
+		ReplicaId: ep.id,
+		Txs:       txs,
+	}
+	payload, err := proto.Marshal(response)
+	if err != nil {
+		ep.logger.Errorf("[EXEC] marshal tx response failed: %s", err)
+		return
+	}
+	msg := &pb.ConsensusMessage{
+		Type:    pb.Type_TX_RESPONSE,
+		Payload: payload,
+	}
+	ep.logger.Infof("[EXEC] reply %d payloads to replica %d", len(txs), fetch.ReplicaId)
+	ep.sender.Unicast(fetch.ReplicaId, msg)
+}
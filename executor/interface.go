@@ -1,6 +0,0 @@
-package executor
-
-type Executor interface {
-	// Execute
-	Execute(value [][]byte)
-}
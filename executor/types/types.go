@@ -0,0 +1,67 @@
+package types
+
+import (
+	"time"
+
+	"github.com/Grivn/libfalanx/api"
+	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// Config is used to initiate the execute processor
+// Replicas: the replicas of the initial epoch
+// Seq:      the latest batch executed before restart, the executed batches will follow it
+// DAGSeq:   the latest batch finalized before restart, the batches from DAG manager will follow it
+// ReqC:   receive the ordered requests from clients order, which tell us the client of every transaction
+// SelfC:  deliver the replies for the client of current replica directly
+// Sender: send the replies to clients, and the payload fetch requests and responses
+// FetchC:    receive the requests to fetch the payloads of transactions from other replicas
+// ResponseC: receive the payloads fetched from other replicas
+// ExecutedC: report the batches which have been executed, it could be nil if they are not concerned
+// StableC:   receive the sequence number of the stable checkpoint, the payloads kept for the batches covered
+//            by it will be dropped, and so will the clients of the transactions whose payloads have been
+//            missing since the previous one, it could be nil if they are never dropped
+// TransferC: receive the batches fetched by state transfer, which will be executed before the ones from
+//            DAG manager, it could be nil if the state transfer is disabled
+// EpochC:    post the new epoch once a reconfiguration has been agreed, it could be nil if the replica set
+//            is not concerned
+type Config struct {
+	ID          uint64
+	Replicas    []int
+	Seq         uint64
+	DAGSeq      uint64
+	Executor    api.Executor
+	TxContainer api.TxsContainer
+	RecvC       chan tp.ExecuteEvent
+	CommitC     chan *tp.CommitEvent
+	ReqC        chan *pb.OrderedReq
+	SelfC       chan *pb.Reply
+	FetchC      chan *pb.TxFetch
+	ResponseC   chan *pb.TxSet
+	ExecutedC   chan tp.ExecuteEvent
+	StableC     chan uint64
+	TransferC   chan tp.TransferEvent
+	EpochC      chan tp.EpochEvent
+	Sender      network.Network
+	Tools       zcommon.Tools
+	Logger      logger.Logger
+}
+
+const (
+	// DefaultFetchTimeout is the duration to wait for the payloads fetched by the first attempt before fetching
+	// them once again
+	DefaultFetchTimeout = time.Second
+
+	// MaxFetchTxs is the maximum amount of transactions in one fetch response
+	MaxFetchTxs = 100
+
+	// MaxFetchTimeout is the maximum duration to wait for the fetched payloads, the timeout is doubled for every
+	// attempt until it reaches this one
+	MaxFetchTimeout = 16 * time.Second
+
+	// MinReplicas is the minimum amount of replicas in an epoch, so that one faulty replica could be tolerated
+	MinReplicas = 4
+)
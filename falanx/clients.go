@@ -0,0 +1,155 @@
+package falanx
+
+import (
+	"container/list"
+	"sync/atomic"
+
+	"github.com/Grivn/libfalanx/api"
+	"github.com/Grivn/libfalanx/clientsorder"
+	clientOrderType "github.com/Grivn/libfalanx/clientsorder/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	"github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// clientProgress is the latest request accepted from an evicted client, the instance created for its later
+// requests will be resumed from it, so that the requests which have been ordered won't be accepted again.
+// element is the position of the client in the eviction order
+type clientProgress struct {
+	sequence  uint64
+	timestamp int64
+	element   *list.Element
+}
+
+// addClientOrder is used to create the client order instance for particular client with the replicas of
+// current epoch, which will be resumed from the progress of the client if it has been evicted. the instance
+// for current replica is never evicted, as the forward client delivers its own requests to it directly.
+// the caller should hold the mutex if falanx has been started
+func (falanx *falanxImpl) addClientOrder(id uint64) api.ClientOrder {
+	recvC := make(chan *pb.OrderedReq, types.DefaultChannelLen)
+	fetchC := make(chan *pb.ReqFetch, types.DefaultChannelLen)
+	responseC := make(chan *pb.ReqResponse, types.DefaultChannelLen)
+	epochC := make(chan types.EpochEvent, types.DefaultChannelLen)
+	progress, ok := falanx.clientProgress[id]
+	clientConfig := clientOrderType.Config{
+		ID:          id,
+		Self:        falanx.id,
+		Replicas:    falanx.replicaList(),
+		Sequence:    progress.sequence,
+		Timestamp:   progress.timestamp,
+		RecvC:       recvC,
+		OrderC:      falanx.reqOrderC,
+		ClientC:     falanx.clientC,
+		FetchC:      fetchC,
+		ResponseC:   responseC,
+		EpochC:      epochC,
+		IdleTimeout: falanx.idleTimeout,
+		Network:     falanx.sender,
+		Tools:       falanx.tools,
+		Logger:      falanx.logger,
+	}
+	if id != falanx.id {
+		clientConfig.IdleC = falanx.idleC
+	}
+	if ok {
+		falanx.evicted.Remove(progress.element)
+		delete(falanx.clientProgress, id)
+	} else if falanx.forgotten > 0 {
+		// the progress of the client might have been forgotten
+		clientConfig.Timestamp = falanx.forgotten
+		clientConfig.Rejoin = true
+	}
+	client := clientsorder.NewClientOrder(clientConfig)
+	falanx.reqRecvC[id] = recvC
+	falanx.reqFetchC[id] = fetchC
+	falanx.reqResponseC[id] = responseC
+	falanx.reqEpochC[id] = epochC
+	falanx.reqPending[id] = new(int32)
+	falanx.clientsOrder[id] = client
+	return client
+}
+
+// admitClient is used to create the client order instance for the client whose first request has arrived,
+// the request will be rejected if the amount of clients has reached the limit or falanx has been stopped.
+// the caller should hold the mutex
+func (falanx *falanxImpl) admitClient(id uint64) bool {
+	if _, ok := falanx.clientsOrder[id]; ok {
+		return true
+	}
+	select {
+	case <-falanx.close:
+		return false
+	default:
+	}
+	if len(falanx.clientsOrder) >= falanx.maxClients {
+		return false
+	}
+	falanx.addClientOrder(id).Start()
+	return true
+}
+
+// listenClients is used to evict the idle client order instances one by one
+func (falanx *falanxImpl) listenClients() {
+	for {
+		select {
+		case <-falanx.close:
+			return
+
+		case id := <-falanx.idleC:
+			falanx.evictClient(id)
+		}
+	}
+}
+
+// pendingClient is used to mark a message being dispatched to the instance of particular client without the
+// mutex, the caller should hold the mutex and decrease the returned counter once the message has arrived
+func (falanx *falanxImpl) pendingClient(id uint64) *int32 {
+	pending := falanx.reqPending[id]
+	atomic.AddInt32(pending, 1)
+	return pending
+}
+
+// evictClient is used to stop the instance of an idle client and record its progress, the mutex is held so
+// that no message could be dispatched to the instance during the eviction, and the instance will be kept
+// if some messages are being dispatched to it or have arrived before it. the eviction never waits for a
+// busy instance, so that the mutex won't be held for long
+func (falanx *falanxImpl) evictClient(id uint64) {
+	falanx.mutex.Lock()
+	defer falanx.mutex.Unlock()
+
+	client, ok := falanx.clientsOrder[id]
+	if !ok || id == falanx.id {
+		return
+	}
+	if atomic.LoadInt32(falanx.reqPending[id]) > 0 {
+		falanx.logger.Debugf("Client %d is busy, keep its client order instance", id)
+		return
+	}
+	sequence, timestamp, ok := client.Evict()
+	if !ok {
+		falanx.logger.Debugf("Client %d is busy, keep its client order instance", id)
+		return
+	}
+	falanx.keepProgress(id, sequence, timestamp)
+	delete(falanx.clientsOrder, id)
+	delete(falanx.reqRecvC, id)
+	delete(falanx.reqFetchC, id)
+	delete(falanx.reqResponseC, id)
+	delete(falanx.reqEpochC, id)
+	delete(falanx.reqPending, id)
+	falanx.logger.Infof("Replica %d evict idle client %d, sequence %d, %d clients remain", falanx.id, id, sequence, len(falanx.clientsOrder))
+}
+
+// keepProgress is used to record the progress of an evicted client, and the progress of the earliest evicted
+// clients will be forgotten once there are more than maxEvicted of them. the caller should hold the mutex
+func (falanx *falanxImpl) keepProgress(id uint64, sequence uint64, timestamp int64) {
+	falanx.clientProgress[id] = clientProgress{sequence: sequence, timestamp: timestamp, element: falanx.evicted.PushBack(id)}
+	for len(falanx.clientProgress) > falanx.maxEvicted {
+		earliest := falanx.evicted.Remove(falanx.evicted.Front()).(uint64)
+		progress := falanx.clientProgress[earliest]
+		delete(falanx.clientProgress, earliest)
+		if progress.timestamp > falanx.forgotten {
+			falanx.forgotten = progress.timestamp
+		}
+		falanx.logger.Debugf("Replica %d forget the progress of client %d, sequence %d", falanx.id, earliest, progress.sequence)
+	}
+}
@@ -0,0 +1,85 @@
+package falanx
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	"github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// TestForgetProgress evicts more clients than MaxEvicted, the progress of the earliest evicted ones should be
+// forgotten, and the latest timestamp in them should be remembered to reject the replayed requests
+func TestForgetProgress(t *testing.T) {
+	cluster := newTestCluster(t, 4, func(c *types.Config) {
+		c.Clients.MaxEvicted = 2
+	})
+	node := cluster.nodes[0]
+
+	for id := uint64(10); id < 15; id++ {
+		node.keepProgress(id, id, int64(id*100))
+	}
+	if len(node.clientProgress) != 2 || node.evicted.Len() != 2 {
+		t.Fatalf("keep the progress of %d clients, expect 2", len(node.clientProgress))
+	}
+	if _, ok := node.clientProgress[14]; !ok {
+		t.Fatalf("the progress of the latest evicted client has been forgotten")
+	}
+	if node.forgotten != 1200 {
+		t.Fatalf("forgotten timestamp %d, expect 1200", node.forgotten)
+	}
+
+	// the progress is dropped once the client has come back
+	node.addClientOrder(13)
+	if len(node.clientProgress) != 1 || node.evicted.Len() != 1 {
+		t.Fatalf("keep the progress of %d clients, expect 1", len(node.clientProgress))
+	}
+}
+
+// TestEvictBusyClient dispatches a request to a client order instance which never processes anything, the
+// eviction shouldn't be blocked by the dispatching or the busy instance, and the instance should be kept
+func TestEvictBusyClient(t *testing.T) {
+	cluster := newTestCluster(t, 4, nil)
+	node := cluster.nodes[0]
+
+	// the instance isn't started, and its channel is full
+	node.addClientOrder(7)
+	for index := 0; index < types.DefaultChannelLen; index++ {
+		node.reqRecvC[7] <- &pb.OrderedReq{ClientId: 7}
+	}
+	dispatched := make(chan bool)
+	go func() {
+		node.processOrderedReq(&pb.OrderedReq{ClientId: 7})
+		close(dispatched)
+	}()
+	for atomic.LoadInt32(node.reqPending[7]) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	evict := func() {
+		evicted := make(chan bool)
+		go func() {
+			node.evictClient(7)
+			close(evicted)
+		}()
+		select {
+		case <-evicted:
+		case <-time.After(time.Second):
+			t.Fatalf("the eviction is blocked")
+		}
+		if _, ok := node.clientsOrder[7]; !ok {
+			t.Fatalf("the busy client order instance has been evicted")
+		}
+	}
+	evict()
+
+	// the dispatching is dropped once falanx has been stopped
+	close(node.close)
+	select {
+	case <-dispatched:
+	case <-time.After(time.Second):
+		t.Fatalf("the dispatching is blocked after falanx has been stopped")
+	}
+	evict()
+}
@@ -0,0 +1,215 @@
+package falanx
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Grivn/libfalanx/network/memory"
+	memType "github.com/Grivn/libfalanx/network/memory/types"
+	netType "github.com/Grivn/libfalanx/network/types"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	"github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// testLogger drops the logs, the failures are reported by the tests themselves
+type testLogger struct{}
+
+func (testLogger) Debug(v ...interface{})                   {}
+func (testLogger) Debugf(format string, v ...interface{})   {}
+func (testLogger) Info(v ...interface{})                    {}
+func (testLogger) Infof(format string, v ...interface{})    {}
+func (testLogger) Warning(v ...interface{})                 {}
+func (testLogger) Warningf(format string, v ...interface{}) {}
+func (testLogger) Error(v ...interface{})                   {}
+func (testLogger) Errorf(format string, v ...interface{})   {}
+
+// testExecutor replies the payloads as the results
+type testExecutor struct{}
+
+func (testExecutor) Execute(payloads [][]byte) [][]byte {
+	return payloads
+}
+
+// testCluster is a group of replicas connected by the in-memory network, the commits of every replica are
+// drained in background and recorded in the order they are delivered
+// orders:  the committed transactions of every replica
+// batches: the committed batches of every replica
+type testCluster struct {
+	hub   testHub
+	nodes []*falanxImpl
+
+	mutex   sync.Mutex
+	orders  [][]string
+	batches [][]types.CommitEvent
+	close   chan bool
+	wg      sync.WaitGroup
+}
+
+// testHub is the part of in-memory network used by the tests
+type testHub interface {
+	Partition(groups ...[]uint64)
+	Heal()
+	Stop()
+}
+
+func newTestCluster(t *testing.T, n int, modify func(c *types.Config)) *testCluster {
+	publics := make(map[uint64]ed25519.PublicKey)
+	privates := make(map[uint64]ed25519.PrivateKey)
+	for id := 1; id <= n; id++ {
+		public, private, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publics[uint64(id)] = public
+		privates[uint64(id)] = private
+	}
+
+	hub := memory.NewHub(memType.Config{
+		Seed:   1,
+		Link:   memType.LinkConfig{Latency: time.Millisecond, Jitter: 2 * time.Millisecond},
+		Logger: testLogger{},
+	})
+	cluster := &testCluster{
+		hub:     hub,
+		orders:  make([][]string, n),
+		batches: make([][]types.CommitEvent, n),
+		close:   make(chan bool),
+	}
+	for id := 1; id <= n; id++ {
+		receiver := netType.NetworkReceiver{RecvC: make(chan *netType.Message, types.DefaultChannelLen)}
+		c := types.Config{
+			ID:       uint64(id),
+			N:        n,
+			Sender:   hub.Register(uint64(id), receiver),
+			Receiver: receiver,
+			Executor: testExecutor{},
+			Signer:   zcommon.NewEd25519Signer(privates[uint64(id)], publics),
+			Logger:   testLogger{},
+		}
+		if modify != nil {
+			modify(&c)
+		}
+		node, err := newFalanxImpl(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cluster.nodes = append(cluster.nodes, node)
+	}
+	return cluster
+}
+
+func (cluster *testCluster) start() {
+	for index, node := range cluster.nodes {
+		node.start()
+		cluster.wg.Add(1)
+		go cluster.drain(index, node)
+	}
+}
+
+func (cluster *testCluster) stop() {
+	close(cluster.close)
+	cluster.wg.Wait()
+	for _, node := range cluster.nodes {
+		node.stop()
+	}
+	cluster.hub.Stop()
+}
+
+func (cluster *testCluster) drain(index int, node *falanxImpl) {
+	defer cluster.wg.Done()
+	for {
+		select {
+		case <-cluster.close:
+			return
+		case commit := <-node.Commits():
+			cluster.mutex.Lock()
+			cluster.orders[index] = append(cluster.orders[index], commit.TxHashes...)
+			cluster.batches[index] = append(cluster.batches[index], types.CommitEvent{Seq: commit.Seq, TxHashes: commit.TxHashes})
+			cluster.mutex.Unlock()
+		case <-node.Completions():
+		}
+	}
+}
+
+// propose is used to let every replica propose one transaction per round
+func (cluster *testCluster) propose(tag string, rounds int) {
+	for round := 0; round < rounds; round++ {
+		for index, node := range cluster.nodes {
+			node.Propose([]*pb.Transaction{{Payload: []byte(fmt.Sprintf("%s-%d-%d", tag, index, round))}})
+		}
+	}
+}
+
+func (cluster *testCluster) committed(index int) int {
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+	return len(cluster.orders[index])
+}
+
+// waitCommitted is used to wait until every replica has committed the expected amount of transactions
+func (cluster *testCluster) waitCommitted(t *testing.T, expected int, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for {
+		finished := true
+		for index := range cluster.nodes {
+			if cluster.committed(index) < expected {
+				finished = false
+			}
+		}
+		if finished {
+			return
+		}
+		if time.Now().After(deadline) {
+			counts := make([]int, len(cluster.nodes))
+			for index := range cluster.nodes {
+				counts[index] = cluster.committed(index)
+			}
+			t.Fatalf("committed %v, expect %d", counts, expected)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// checkCommitted is used to check that every replica has committed the same transactions exactly once, in
+// the same batches and the same order inside each batch
+func (cluster *testCluster) checkCommitted(t *testing.T) {
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+
+	for index := range cluster.orders {
+		if len(cluster.orders[index]) != len(cluster.orders[0]) {
+			t.Fatalf("replica %d committed %d txs, replica 1 committed %d", index+1, len(cluster.orders[index]), len(cluster.orders[0]))
+		}
+		seen := make(map[string]bool)
+		for _, txHash := range cluster.orders[index] {
+			if seen[txHash] {
+				t.Fatalf("replica %d committed tx %s twice", index+1, txHash)
+			}
+			seen[txHash] = true
+		}
+		for _, txHash := range cluster.orders[0] {
+			if !seen[txHash] {
+				t.Fatalf("replica %d didn't commit tx %s", index+1, txHash)
+			}
+		}
+	}
+
+	for index := range cluster.batches {
+		if len(cluster.batches[index]) != len(cluster.batches[0]) {
+			t.Fatalf("replica %d committed %d batches, replica 1 committed %d", index+1, len(cluster.batches[index]), len(cluster.batches[0]))
+		}
+		for seq, batch := range cluster.batches[index] {
+			expect := cluster.batches[0][seq]
+			if batch.Seq != expect.Seq {
+				t.Fatalf("replica %d committed batch %d at position %d, replica 1 committed batch %d", index+1, batch.Seq, seq, expect.Seq)
+			}
+			if fmt.Sprint(batch.TxHashes) != fmt.Sprint(expect.TxHashes) {
+				t.Fatalf("replica %d committed %v in batch %d, replica 1 committed %v", index+1, batch.TxHashes, batch.Seq, expect.TxHashes)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package falanx
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/Grivn/libfalanx/api"
+	"github.com/Grivn/libfalanx/replicasorder"
+	replicaOrderType "github.com/Grivn/libfalanx/replicasorder/types"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	"github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// addReplicaOrder is used to create the replica order instance for particular replica, which will be resumed
+// from the latest log if it's not nil. the caller should hold the mutex if falanx has been started
+func (falanx *falanxImpl) addReplicaOrder(id uint64, latest *pb.OrderedLog) api.ModuleControl {
+	recvC := make(chan *pb.OrderedLog, types.DefaultChannelLen)
+	fetchC := make(chan *pb.LogFetch, types.DefaultChannelLen)
+	replicaConfig := replicaOrderType.Config{
+		ID:      id,
+		Self:    falanx.id,
+		RecvC:   recvC,
+		OrderC:  falanx.logOrderC,
+		FetchC:  fetchC,
+		Network: falanx.sender,
+		Logger:  falanx.logger,
+	}
+	if latest != nil {
+		replicaConfig.Sequence = latest.Sequence
+		replicaConfig.Timestamp = latest.Timestamp
+	}
+	replica := replicasorder.NewReplicaOrder(replicaConfig)
+	falanx.logRecvC[id] = recvC
+	falanx.fetchRecvC[id] = fetchC
+	falanx.logStopC[id] = make(chan bool)
+	falanx.replicasOrder[id] = replica
+	return replica
+}
+
+// removeReplicaOrder is used to stop the replica order instance for particular replica, the caller should
+// hold the mutex
+func (falanx *falanxImpl) removeReplicaOrder(id uint64) {
+	if replica, ok := falanx.replicasOrder[id]; ok {
+		replica.Stop()
+		close(falanx.logStopC[id])
+	}
+	delete(falanx.replicasOrder, id)
+	delete(falanx.logRecvC, id)
+	delete(falanx.fetchRecvC, id)
+	delete(falanx.logStopC, id)
+}
+
+// replicaList is used to get the replicas of current epoch in ascending order, the caller should hold the mutex
+func (falanx *falanxImpl) replicaList() []int {
+	var replicas []int
+	for id := range falanx.replicas {
+		replicas = append(replicas, int(id))
+	}
+	sort.Ints(replicas)
+	return replicas
+}
+
+// listenEpoch is used to process the new epochs agreed by executor one by one
+func (falanx *falanxImpl) listenEpoch() {
+	for {
+		select {
+		case <-falanx.close:
+			return
+
+		case event := <-falanx.epochC:
+			falanx.reconfigure(event)
+		}
+	}
+}
+
+// reconfigure is used to switch to the replicas of new epoch, the replica order instances for the added
+// replicas will be created and the ones for the removed replicas will be stopped, then the client order
+// instances and the other modules will be notified. the instance for current replica is kept even if it
+// has been removed, as the local order module delivers its own logs to it. the client order instances are
+// notified without the mutex, as they might be busy, and they won't be evicted before the notification.
+func (falanx *falanxImpl) reconfigure(event types.EpochEvent) {
+	current := make(map[uint64]bool)
+	for _, id := range event.Replicas {
+		current[uint64(id)] = true
+	}
+
+	falanx.mutex.Lock()
+	for id := range falanx.replicas {
+		if !current[id] && id != falanx.id {
+			falanx.removeReplicaOrder(id)
+		}
+	}
+	falanx.replicas = current
+	falanx.epoch = event.Epoch
+	for id := range current {
+		if _, ok := falanx.replicasOrder[id]; ok {
+			continue
+		}
+		falanx.addReplicaOrder(id, nil).Start()
+	}
+	epochCs := make([]chan types.EpochEvent, 0, len(falanx.reqEpochC))
+	pendings := make([]*int32, 0, len(falanx.reqEpochC))
+	for id, epochC := range falanx.reqEpochC {
+		epochCs = append(epochCs, epochC)
+		pendings = append(pendings, falanx.pendingClient(id))
+	}
+	falanx.mutex.Unlock()
+
+	for index, epochC := range epochCs {
+		select {
+		case epochC <- copyEpoch(event):
+		case <-falanx.close:
+		}
+		atomic.AddInt32(pendings[index], -1)
+	}
+
+	if !current[falanx.id] {
+		falanx.logger.Warningf("Replica %d has been removed in epoch %d", falanx.id, event.Epoch)
+	}
+	falanx.logger.Infof("Replica %d switch to epoch %d after batch %d, replicas %v", falanx.id, event.Epoch, event.Seq, event.Replicas)
+
+	for _, epochC := range []chan types.EpochEvent{falanx.filterEpochC, falanx.baEpochC, falanx.checkpointEpochC, falanx.clientEpochC} {
+		select {
+		case epochC <- copyEpoch(event):
+		case <-falanx.close:
+			return
+		}
+	}
+}
+
+// reconfigurePropose is used to propose the config transaction to switch to the replica set in the next
+// epoch, which will be agreed once f+1 replicas of current epoch have proposed the same replica set
+func (falanx *falanxImpl) reconfigurePropose(replicas []uint64) error {
+	falanx.mutex.RLock()
+	epoch := falanx.epoch
+	member := falanx.replicas[falanx.id]
+	falanx.mutex.RUnlock()
+
+	if !member {
+		return fmt.Errorf("replica %d is not in epoch %d", falanx.id, epoch)
+	}
+	reconfig := &pb.Reconfig{
+		Epoch:     epoch + 1,
+		Replicas:  replicas,
+		ReplicaId: falanx.id,
+	}
+	if err := zcommon.SignReconfig(falanx.tools, reconfig); err != nil {
+		return err
+	}
+	falanx.propose([]*pb.Transaction{{Reconfig: reconfig}})
+	return nil
+}
+
+// copyEpoch is used to deliver the epoch to every module with its own replica list
+func copyEpoch(event types.EpochEvent) types.EpochEvent {
+	replicas := make([]int, len(event.Replicas))
+	copy(replicas, event.Replicas)
+	event.Replicas = replicas
+	return event
+}
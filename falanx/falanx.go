@@ -1,12 +1,59 @@
 package falanx
 
 import (
+	containerType "github.com/Grivn/libfalanx/txcontainer/types"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
 	"github.com/Grivn/libfalanx/zcommon/types"
 )
 
-func NewFalanx(c types.Config) *falanxImpl {
-	return newFalanxImpl(c)
+// Falanx is the facade of falanx protocol which is used by the host application
+type Falanx interface {
+	// StartFalanx is used to start the modules of falanx
+	StartFalanx()
+
+	// StopFalanx is used to stop the modules of falanx
+	StopFalanx()
+
+	// StepMessage is used to process the consensus messages delivered by application itself, whose sender
+	// is unknown. the messages delivered into Config.Receiver by transport will be processed automatically.
+	StepMessage(msg *pb.ConsensusMessage)
+
+	// Propose is used to propose the transactions from clients
+	Propose(txs []*pb.Transaction)
+
+	// Commits is used to subscribe the committed batches, the batches will be delivered in the order of
+	// their sequence number and every batch will be delivered exactly once.
+	// the stream is bounded by Config.CommitLen, when it is full the ordering pipeline will be blocked
+	// until the application receives the commits, so the application should keep draining it.
+	Commits() <-chan *types.CommitEvent
+
+	// Completions is used to subscribe the transactions proposed by current replica which have been
+	// executed, every transaction will be reported once f+1 replicas have replied the same result.
+	Completions() <-chan *types.CompleteEvent
+
+	// MempoolMetrics is used to inspect the container of transaction payloads
+	MempoolMetrics() containerType.Metrics
+
+	// StableCheckpoint is used to read the latest executed batch whose digest of executed order has been
+	// agreed by 2f+1 replicas, with their signed checkpoints as proofs. it returns nil if there isn't
+	// any stable checkpoint yet
+	StableCheckpoint() *pb.StableCheckpoint
+
+	// Reconfigure is used to propose the replica set of the next epoch, which should be listed in ascending
+	// order. the replicas will switch to the new epoch after the same batch once f+1 replicas of current
+	// epoch have proposed the same replica set, and the ordered logs from the replicas outside of it will
+	// be rejected since then. the public keys of the added replicas should have been held by Config.Signer
+	Reconfigure(replicas []uint64) error
+}
+
+// NewFalanx is used to initiate falanx with the config, it returns an error if the config is invalid, e.g.
+// Config.Signer is nil
+func NewFalanx(c types.Config) (Falanx, error) {
+	falanx, err := newFalanxImpl(c)
+	if err != nil {
+		return nil, err
+	}
+	return falanx, nil
 }
 
 func (falanx *falanxImpl) StartFalanx() {
@@ -18,10 +65,29 @@ func (falanx *falanxImpl) StopFalanx() {
 }
 
 func (falanx *falanxImpl) StepMessage(msg *pb.ConsensusMessage) {
-	falanx.step(msg)
+	falanx.step(0, msg)
 }
 
 func (falanx *falanxImpl) Propose(txs []*pb.Transaction) {
-	falanx.forwardClient.ProposeTxs(txs)
-	return
+	falanx.propose(txs)
+}
+
+func (falanx *falanxImpl) Commits() <-chan *types.CommitEvent {
+	return falanx.commitC
+}
+
+func (falanx *falanxImpl) Completions() <-chan *types.CompleteEvent {
+	return falanx.completeC
+}
+
+func (falanx *falanxImpl) MempoolMetrics() containerType.Metrics {
+	return falanx.txContainer.Metrics()
+}
+
+func (falanx *falanxImpl) StableCheckpoint() *pb.StableCheckpoint {
+	return falanx.checkpoint.StableCheckpoint()
+}
+
+func (falanx *falanxImpl) Reconfigure(replicas []uint64) error {
+	return falanx.reconfigurePropose(replicas)
 }
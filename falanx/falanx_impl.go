@@ -1,21 +1,40 @@
 package falanx
 
 import (
+	"container/list"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/Grivn/libfalanx/api"
-	"github.com/Grivn/libfalanx/clientsorder"
+	"github.com/Grivn/libfalanx/checkpoint"
+	checkpointType "github.com/Grivn/libfalanx/checkpoint/types"
+	checkpointUtils "github.com/Grivn/libfalanx/checkpoint/utils"
 	clientOrderType "github.com/Grivn/libfalanx/clientsorder/types"
+	"github.com/Grivn/libfalanx/dagmanager"
+	dagType "github.com/Grivn/libfalanx/dagmanager/types"
+	"github.com/Grivn/libfalanx/executor"
+	executorType "github.com/Grivn/libfalanx/executor/types"
 	"github.com/Grivn/libfalanx/filter"
 	filterType "github.com/Grivn/libfalanx/filter/types"
+	filterUtils "github.com/Grivn/libfalanx/filter/utils"
 	"github.com/Grivn/libfalanx/forwardclient"
 	fakeClientType "github.com/Grivn/libfalanx/forwardclient/types"
 	"github.com/Grivn/libfalanx/graphengine"
+	graphType "github.com/Grivn/libfalanx/graphengine/types"
+	"github.com/Grivn/libfalanx/localba"
+	localBAType "github.com/Grivn/libfalanx/localba/types"
 	"github.com/Grivn/libfalanx/localorder"
 	localOrderType "github.com/Grivn/libfalanx/localorder/types"
 	"github.com/Grivn/libfalanx/logger"
-	"github.com/Grivn/libfalanx/replicasorder"
-	replicaOrderType "github.com/Grivn/libfalanx/replicasorder/types"
+	"github.com/Grivn/libfalanx/network"
+	netType "github.com/Grivn/libfalanx/network/types"
 	"github.com/Grivn/libfalanx/txcontainer"
 	containerType "github.com/Grivn/libfalanx/txcontainer/types"
+	"github.com/Grivn/libfalanx/zcommon"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
 	"github.com/Grivn/libfalanx/zcommon/types"
 	"github.com/gogo/protobuf/proto"
@@ -26,21 +45,54 @@ type falanxImpl struct {
 	// id: identifier of current replica
 	id uint64
 
+	// epoch =========================================================================================
+	// mutex:    guard the replicas of current epoch and the instances of client order and replica order,
+	//           which will be changed once a new epoch has been agreed
+	// epoch:    the current epoch, the replicas of the initial epoch are 1..N
+	// replicas: the replicas of current epoch, the ordered logs from other replicas will be rejected
+	mutex    sync.RWMutex
+	epoch    uint64
+	replicas map[uint64]bool
+
+	// clients =======================================================================================
+	// clientProgress: the latest requests accepted from the evicted clients
+	// evicted:        the evicted clients whose progress is kept, in the order of eviction
+	// maxEvicted:     the maximum amount of evicted clients whose progress is kept
+	// forgotten:      the latest timestamp in the progress which has been forgotten
+	// maxClients:     the maximum amount of client order instances, the requests from new clients will be
+	//                 rejected once it has been reached
+	// idleTimeout:    the client order instances will be evicted once they have been idle for idleTimeout
+	clientProgress map[uint64]clientProgress
+	evicted        *list.List
+	maxEvicted     int
+	forgotten      int64
+	maxClients     int
+	idleTimeout    time.Duration
+
 	// modules =======================================================================================
 	// forwardClient: used to forward the txs send from the clients which trust current replica
 	// txContainer:   used to contain the transactions
 	// localOrder:    used to generate current node's ordered logs
-	// clientsOrder:  used to process the ordered requests from clients
+	// clientsOrder:  used to process the ordered requests from clients, which are created on the first request
+	//                of every client and evicted once they have been idle
 	// replicasOrder: used to process the ordered logs from replicas
 	// txFilter:      used to generate graph
+	// localBA:       used to remove the replicas which cannot send logs in time
 	// graphEngine:   used to deal with the raw graph
+	// dagManager:    used to track the dependencies between finalized transactions
+	// executor:      used to execute the finished batches in order
+	// checkpoint:    used to agree on the executed order with other replicas
 	forwardClient api.ForwardClient
 	txContainer   api.TxsContainer
 	localOrder    api.ModuleControl
-	clientsOrder  map[uint64]api.ModuleControl
+	clientsOrder  map[uint64]api.ClientOrder
 	replicasOrder map[uint64]api.ModuleControl
 	txFilter      api.ModuleControl
+	localBA       api.ModuleControl
 	graphEngine   api.ModuleControl
+	dagManager    api.ModuleControl
+	executor      api.ModuleControl
+	checkpoint    api.Checkpoint
 
 	// channel =======================================================================================
 	// the channels which will be used to deliver messages between different modules
@@ -50,9 +102,32 @@ type falanxImpl struct {
 	// reqOrderC: collect the ordered txHash from different client and deliver them to local order module
 	// reqRecvC:  dispatch the ordered logs to specific replica order module
 	// reqOrderC: collect the ordered logs from different replica and deliver them to filter module
+	// fetchRecvC: dispatch the log fetch requests to specific replica order module
+	// reqFetchC:    dispatch the request fetch requests to specific client order module
+	// reqResponseC: dispatch the verified request fetch responses to specific client order module
+	// reqEpochC:    dispatch the replicas of new epoch to every client order module
+	// reqPending:   the amount of messages being dispatched to every client order module without the mutex,
+	//               the module won't be evicted until they have arrived
+	// logStopC:     closed once the replica order module has been stopped, so that the messages being
+	//               dispatched to it without the mutex will be dropped
+	// clientC:   collect the ordered requests accepted by client order modules and deliver them to executor
+	// idleC:     collect the idle client order modules, which will be evicted by falanx
+	// epochC:    receive the new epoch agreed by executor, which will be dispatched to the other modules
+	// netRecvC:  receive the messages from transport, which is the RecvC of Config.Receiver
+	//
+	// message -------> netRecvC ---> falanx
+	// the messages from network will be stepped one by one
 	//
 	// ordered_req ---> reqRecvC ---> clientsOrder
-	// the ordered reqs will be picked from clientsOrder one by one
+	// the ordered reqs will be picked from clientsOrder one by one, the instance for a client will be created
+	// once its first request has arrived
+	//
+	// client --------> idleC -------> falanx
+	// the idle clientsOrder will be stopped, and the next instance for the client will resume from its progress
+	//
+	// req_fetch -----> reqFetchC ---> clientsOrder
+	// req_response --> reqResponseC -> clientsOrder
+	// clientsOrder will fetch the missing requests from others, and skip the ones never issued by client
 	//
 	// txHash --------> reqOrderC --> localOrder
 	// localOrder will order the txHash from client and broadcast ordered logs
@@ -60,118 +135,444 @@ type falanxImpl struct {
 	// ordered_log ---> logRecvC ---> replicasOrder
 	// the ordered logs will be picked from replicasOrder one by one
 	//
+	// log_fetch -----> fetchRecvC -> replicasOrder
+	// replicasOrder will reply the logs it has received to the replicas which have missed them
+	//
 	// ordered_log ---> logOrderC --> txFilter
 	// txFilter will collect the logs from different replicasOrder and generate a graph
-	reqRecvC    map[uint64]chan *pb.OrderedReq
-	reqOrderC   chan string
-	logRecvC    map[uint64]chan *pb.OrderedLog
-	logOrderC   chan *pb.OrderedLog
-	close       chan bool
+	//
+	// graph ---------> graphC -----> graphEngine
+	// graphEngine will generate a linear order for the transactions in graph
+	//
+	// batch ---------> dagC -------> dagManager
+	// relation ------> resolveC ---> dagManager
+	// dagManager will hold the transactions whose relation with others hasn't been determined
+	//
+	// batch ---------> executeC ---> executor
+	// the finished batches will be executed one by one
+	// event ---------> baC --------> localBA
+	// vote ----------> baRecvC ----> localBA
+	// localBA will vote to remove the replicas reported by txFilter
+	//
+	// suspect -------> suspectC ---> localBA
+	// localBA will collect the suspect messages to find out the malicious replicas
+	//
+	// req -----------> clientC ----> executor
+	// executor will reply to the clients of the executed transactions
+	// tx_fetch ------> txFetchC ---> executor
+	// tx_response ---> txResponseC -> executor
+	// executor will fetch the missing payloads of the batch from others before executing it
+	//
+	// batch ---------> executedC ---> checkpoint
+	// checkpoint ----> checkpointC -> checkpoint
+	// batch ---------> stableC -----> txFilter
+	// checkpoint will broadcast the digest of executed order, and the batches will be collected by txFilter
+	// once 2f+1 replicas have generated the same checkpoint
+	//
+	// seq -----------> stableSeqC --> executor
+	// executor will drop the payloads kept for the batches covered by the stable checkpoint
+	//
+	// log seq -------> compactC ----> localOrder
+	// txFilter will notify localOrder of the logs covered by a persisted snapshot of stable batch, so that they
+	// could be dropped from the write-ahead log
+	//
+	// state_fetch ---> stateFetchC ---> checkpoint
+	// state_response > stateResponseC > checkpoint
+	// batches -------> transferC -----> executor
+	// batches -------> skipC ---------> txFilter
+	// batches -------> dagSkipC ------> dagManager
+	// checkpoint will fetch the stable checkpoint and the batches covered by it once current replica has
+	// fallen behind, the batches will be executed directly, txFilter will resume from the next batch and
+	// dagManager will drop the transferred transactions
+	//
+	// reply ---------> replyC -----> forwardClient
+	// forwardClient will report the transactions replied by f+1 replicas to the application
+	//
+	// epoch ---------> epochC -----> falanx
+	// epoch ---------> filterEpochC, baEpochC, checkpointEpochC, clientEpochC, reqEpochC
+	// executor will post the new epoch once f+1 replicas have proposed the same replica set for it, falanx
+	// will create the replica order modules for the added replicas and stop the ones for the removed
+	// replicas, and the other modules will switch to the new replica set after the same batch
+	//
+	// whitelist -----> whitelistC --> txFilter
+	// txFilter will select candidates from the replicas in whitelist since the batch agreed by localBA
+	// blacklist -----> blacklistC --> txFilter
+	// txFilter will ignore the logs from blacklisted replicas when relating txs since the batch agreed by localBA
+	reqRecvC         map[uint64]chan *pb.OrderedReq
+	reqOrderC        chan string
+	logRecvC         map[uint64]chan *pb.OrderedLog
+	fetchRecvC       map[uint64]chan *pb.LogFetch
+	reqFetchC        map[uint64]chan *pb.ReqFetch
+	reqResponseC     map[uint64]chan *pb.ReqResponse
+	reqEpochC        map[uint64]chan types.EpochEvent
+	reqPending       map[uint64]*int32
+	logStopC         map[uint64]chan bool
+	logOrderC        chan *pb.OrderedLog
+	clientC          chan *pb.OrderedReq
+	idleC            chan uint64
+	baRecvC          chan *pb.BaVote
+	suspectC         chan *pb.Suspect
+	replyC           chan *pb.Reply
+	txFetchC         chan *pb.TxFetch
+	txResponseC      chan *pb.TxSet
+	checkpointC      chan *pb.Checkpoint
+	stateFetchC      chan *pb.StateFetch
+	stateResponseC   chan *pb.StateResponse
+	epochC           chan types.EpochEvent
+	filterEpochC     chan types.EpochEvent
+	baEpochC         chan types.EpochEvent
+	checkpointEpochC chan types.EpochEvent
+	clientEpochC     chan types.EpochEvent
+	netRecvC         chan *netType.Message
+	close            chan bool
+
+	// external channel
+	// commitC:   notify the application of the committed batches
+	// completeC: notify the application of the transactions proposed by current replica which have been
+	//            replied by f+1 replicas
+	commitC   chan *types.CommitEvent
+	completeC chan *types.CompleteEvent
 
 	// essential =====================================================================================
+	// tools is used to verify the signatures of messages from network
+	// sender is used by the order modules created for the added replicas
+	tools  zcommon.Tools
+	sender network.Network
 	logger logger.Logger
 }
 
-func newFalanxImpl(c types.Config) *falanxImpl {
+func newFalanxImpl(c types.Config) (*falanxImpl, error) {
+	// every ordered log, request, checkpoint and vote is signed, so that falanx cannot work without a signer
+	if c.Signer == nil {
+		return nil, errors.New("nil signer")
+	}
+
 	reqRecvC := make(map[uint64]chan *pb.OrderedReq)
 	logRecvC := make(map[uint64]chan *pb.OrderedLog)
+	fetchRecvC := make(map[uint64]chan *pb.LogFetch)
+	reqFetchC := make(map[uint64]chan *pb.ReqFetch)
+	reqResponseC := make(map[uint64]chan *pb.ReqResponse)
 	reqOrderC := make(chan string)
 	logOrderC := make(chan *pb.OrderedLog)
-	graphC := make(chan interface{})
+	graphC := make(chan types.GraphEvent, types.DefaultChannelLen)
+	resolveC := make(chan types.Edge, types.DefaultChannelLen)
+	dagC := make(chan *dagType.DAGValue, types.DefaultChannelLen)
+	baC := make(chan types.LocalBAEvent, types.DefaultChannelLen)
+	baRecvC := make(chan *pb.BaVote, types.DefaultChannelLen)
+	whitelistC := make(chan types.ExcludeEvent, types.DefaultChannelLen)
+	suspectC := make(chan *pb.Suspect, types.DefaultChannelLen)
+	blacklistC := make(chan types.ExcludeEvent, types.DefaultChannelLen)
+	executeC := make(chan types.ExecuteEvent, types.DefaultChannelLen)
+
+	tools := zcommon.NewTools(c.Hash, c.Signer)
+
+	commitLen := c.CommitLen
+	if commitLen <= 0 {
+		commitLen = types.DefaultChannelLen
+	}
+	commitC := make(chan *types.CommitEvent, commitLen)
+	completeC := make(chan *types.CompleteEvent, commitLen)
+	replyC := make(chan *pb.Reply, types.DefaultChannelLen)
+	clientC := make(chan *pb.OrderedReq, types.DefaultChannelLen)
+	txFetchC := make(chan *pb.TxFetch, types.DefaultChannelLen)
+	txResponseC := make(chan *pb.TxSet, types.DefaultChannelLen)
+
+	executedC := make(chan types.ExecuteEvent, types.DefaultChannelLen)
+	checkpointC := make(chan *pb.Checkpoint, types.DefaultChannelLen)
+	stableC := make(chan types.ExecuteEvent, types.DefaultChannelLen)
+	stableSeqC := make(chan uint64, types.DefaultChannelLen)
+	stateFetchC := make(chan *pb.StateFetch, types.DefaultChannelLen)
+	stateResponseC := make(chan *pb.StateResponse, types.DefaultChannelLen)
+	transferC := make(chan types.TransferEvent, types.DefaultChannelLen)
+	skipC := make(chan types.TransferEvent, types.DefaultChannelLen)
+	dagSkipC := make(chan types.TransferEvent, types.DefaultChannelLen)
 
 	// initialize the tx container
 	containerConfig := containerType.Config{
-		Logger: c.Logger,
-		Tools:  c.Tools,
+		Capacity:    c.Mempool.Capacity,
+		ClientQuota: c.Mempool.ClientQuota,
+		TTL:         c.Mempool.TTL,
+		Logger:      c.Logger,
+		Tools:       tools,
 	}
 	txContainer := txcontainer.NewTxContainer(containerConfig)
 
-	// initialize the client order
-	clientsOrder := make(map[uint64]api.ModuleControl)
-	for i:=0; i<c.N; i++ {
-		id := uint64(i+1)
-		recvC := make(chan *pb.OrderedReq, types.DefaultChannelLen)
-		clientConfig := clientOrderType.Config{
-			ID:     id,
-			RecvC:  recvC,
-			OrderC: reqOrderC,
-			Logger: c.Logger,
+	epochC := make(chan types.EpochEvent, types.DefaultChannelLen)
+	filterEpochC := make(chan types.EpochEvent, types.DefaultChannelLen)
+	baEpochC := make(chan types.EpochEvent, types.DefaultChannelLen)
+	checkpointEpochC := make(chan types.EpochEvent, types.DefaultChannelLen)
+	clientEpochC := make(chan types.EpochEvent, types.DefaultChannelLen)
+
+	maxClients := c.Clients.MaxClients
+	if maxClients <= 0 {
+		maxClients = clientOrderType.DefaultMaxClients
+	}
+	idleTimeout := c.Clients.IdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = clientOrderType.DefaultIdleTimeout
+	}
+	maxEvicted := c.Clients.MaxEvicted
+	if maxEvicted <= 0 {
+		maxEvicted = clientOrderType.DefaultMaxEvicted
+	}
+
+	falanx := &falanxImpl{
+		id:               c.ID,
+		replicas:         make(map[uint64]bool),
+		clientProgress:   make(map[uint64]clientProgress),
+		evicted:          list.New(),
+		maxEvicted:       maxEvicted,
+		maxClients:       maxClients,
+		idleTimeout:      idleTimeout,
+		txContainer:      txContainer,
+		clientsOrder:     make(map[uint64]api.ClientOrder),
+		replicasOrder:    make(map[uint64]api.ModuleControl),
+		reqRecvC:         reqRecvC,
+		reqOrderC:        reqOrderC,
+		logRecvC:         logRecvC,
+		fetchRecvC:       fetchRecvC,
+		reqFetchC:        reqFetchC,
+		reqResponseC:     reqResponseC,
+		reqEpochC:        make(map[uint64]chan types.EpochEvent),
+		reqPending:       make(map[uint64]*int32),
+		logStopC:         make(map[uint64]chan bool),
+		logOrderC:        logOrderC,
+		clientC:          clientC,
+		idleC:            make(chan uint64, types.DefaultChannelLen),
+		baRecvC:          baRecvC,
+		suspectC:         suspectC,
+		replyC:           replyC,
+		txFetchC:         txFetchC,
+		txResponseC:      txResponseC,
+		checkpointC:      checkpointC,
+		stateFetchC:      stateFetchC,
+		stateResponseC:   stateResponseC,
+		epochC:           epochC,
+		filterEpochC:     filterEpochC,
+		baEpochC:         baEpochC,
+		checkpointEpochC: checkpointEpochC,
+		clientEpochC:     clientEpochC,
+		netRecvC:         c.Receiver.RecvC,
+		close:            make(chan bool),
+		commitC:          commitC,
+		completeC:        completeC,
+		tools:            tools,
+		sender:           c.Sender,
+		logger:           c.Logger,
+	}
+	for i := 0; i < c.N; i++ {
+		falanx.replicas[uint64(i+1)] = true
+	}
+
+	// initialize the client order for current replica, the ones for other clients will be created on demand
+	falanx.addClientOrder(c.ID)
+
+	// load the snapshot of filter, and the replica order should be resumed from the latest logs in it, the
+	// DAG manager and executor should follow the latest finalized batch in it
+	var snapshotStore filterUtils.SnapshotStore
+	var snapshot *pb.FilterSnapshot
+	var dagSeq uint64
+	progress := make(map[uint64]*pb.OrderedLog)
+	if c.DataDir != "" {
+		if err := os.MkdirAll(c.DataDir, 0700); err != nil {
+			panic(err)
 		}
-		reqRecvC[id] = recvC
-		clientsOrder[id] = clientsorder.NewClientOrder(clientConfig)
+		snapshotStore = filterUtils.NewSnapshotStore(filepath.Join(c.DataDir, types.FilterSnapshot))
+		var err error
+		if snapshot, err = snapshotStore.Load(); err != nil {
+			panic(err)
+		}
+		if snapshot != nil {
+			for _, log := range snapshot.Progress {
+				progress[log.ReplicaId] = log
+			}
+			dagSeq = snapshot.BatchSeq
+		}
+	}
+
+	// load the snapshot of checkpoint processor, the executor and checkpoint processor should resume the chain
+	// of digest from the latest executed batch in it
+	var checkpointStore checkpointUtils.SnapshotStore
+	var executed *pb.CheckpointSnapshot
+	var batches []types.ExecuteEvent
+	if c.DataDir != "" {
+		checkpointStore = checkpointUtils.NewSnapshotStore(filepath.Join(c.DataDir, types.CheckpointSnapshot))
+		var err error
+		if executed, err = checkpointStore.Load(); err != nil {
+			panic(err)
+		}
+	}
+	if executed == nil {
+		executed = &pb.CheckpointSnapshot{}
+	}
+	for _, batch := range executed.Batches {
+		batches = append(batches, types.ExecuteEvent{Seq: batch.Seq, TxHashes: batch.TxHashes})
 	}
 
 	// initialize the replica order
 	var replicas []int
-	replicasOrder := make(map[uint64]api.ModuleControl)
 	for i:=0; i<c.N; i++ {
 		id := uint64(i+1)
-		recvC := make(chan *pb.OrderedLog, types.DefaultChannelLen)
-		replicaConfig := replicaOrderType.Config{
-			ID:     id,
-			RecvC:  recvC,
-			OrderC: logOrderC,
-			Logger: c.Logger,
-		}
-		logRecvC[id] = recvC
-		replicasOrder[id] = replicasorder.NewReplicaOrder(replicaConfig)
+		falanx.addReplicaOrder(id, progress[id])
 		replicas = append(replicas, int(id))
 	}
 
 	// client
 	clientConfig := fakeClientType.Config{
-		ID:     c.ID,
-		SelfC:  reqRecvC[c.ID],
-		Tools:  c.Tools,
-		Sender: c.Sender,
-		Logger: c.Logger,
+		ID:        c.ID,
+		N:         c.N,
+		SelfC:     reqRecvC[c.ID],
+		ReplyC:    replyC,
+		CompleteC: completeC,
+		EpochC:    clientEpochC,
+		Tools:     tools,
+		Sender:    c.Sender,
+		Logger:    c.Logger,
 	}
 	fakeClient := forwardclient.NewClient(clientConfig)
 
-	// local order
+	// local order, the write-ahead log will be compacted once the snapshot covering the logs has become stable
+	var walPath string
+	var compactC chan uint64
+	if c.DataDir != "" {
+		walPath = filepath.Join(c.DataDir, types.LocalOrderWAL)
+		compactC = make(chan uint64, types.DefaultChannelLen)
+	}
 	localConfig := localOrderType.Config{
-		ID:      c.ID,
-		RecvC:   reqOrderC,
-		SelfC:   logRecvC[c.ID],
-		Network: c.Sender,
-		Logger:  c.Logger,
+		ID:       c.ID,
+		WALPath:  walPath,
+		RecvC:    reqOrderC,
+		SelfC:    logRecvC[c.ID],
+		CompactC: compactC,
+		Network:  c.Sender,
+		Tools:    tools,
+		Logger:   c.Logger,
 	}
 	localOrder := localorder.NewLocalOrder(localConfig)
 
 	// filter
 	filterConfig := filterType.Config{
-		Replicas: replicas,
-		Order:    logOrderC,
-		Graph:    graphC,
-		Logger:   c.Logger,
-		Tools:    c.Tools,
+		ID:        c.ID,
+		Replicas:  replicas,
+		Order:     logOrderC,
+		Graph:     graphC,
+		Resolve:   resolveC,
+		BA:        baC,
+		Whitelist: whitelistC,
+		Blacklist: blacklistC,
+		Stable:    stableC,
+		Transfer:  skipC,
+		Epoch:     filterEpochC,
+		Store:     snapshotStore,
+		Snapshot:  snapshot,
+		Compact:   compactC,
+		Container: txContainer,
+		Logger:    c.Logger,
+		Tools:     tools,
 	}
 	txFilter := filter.NewTransactionFilter(filterConfig)
 
-	graphEngine := graphengine.NewGraphEngine(graphC)
+	// local byzantine agreement
+	baConfig := localBAType.Config{
+		ID:         c.ID,
+		Replicas:   replicas,
+		EventC:     baC,
+		RecvC:      baRecvC,
+		WhitelistC: whitelistC,
+		SuspectC:   suspectC,
+		BlacklistC: blacklistC,
+		EpochC:     baEpochC,
+		Network:    c.Sender,
+		Tools:      tools,
+		Logger:     c.Logger,
+	}
+	localBA := localba.NewLocalBA(baConfig)
 
-	falanx := &falanxImpl{
-		id:            c.ID,
-		forwardClient: fakeClient,
-		txContainer:   txContainer,
-		localOrder:    localOrder,
-		clientsOrder:  clientsOrder,
-		replicasOrder: replicasOrder,
-		txFilter:      txFilter,
-		graphEngine:   graphEngine,
-		reqRecvC:      reqRecvC,
-		reqOrderC:     reqOrderC,
-		logRecvC:      logRecvC,
-		logOrderC:     logOrderC,
-		close:         make(chan bool),
-		logger:        c.Logger,
-	}
-
-	return falanx
+	// graph engine
+	graphConfig := graphType.Config{
+		GraphC: graphC,
+		DAGC:   dagC,
+		Logger: c.Logger,
+	}
+	graphEngine := graphengine.NewGraphEngine(graphConfig)
+
+	// dag manager
+	dagConfig := dagType.Config{
+		Sequence:  dagSeq,
+		RecvC:     dagC,
+		ResolveC:  resolveC,
+		TransferC: dagSkipC,
+		ExecuteC:  executeC,
+		Logger:    c.Logger,
+	}
+	dagManager := dagmanager.NewDAGProcessor(dagConfig)
+
+	// executor
+	executorConfig := executorType.Config{
+		ID:          c.ID,
+		Replicas:    replicas,
+		Seq:         executed.Seq,
+		DAGSeq:      dagSeq,
+		Executor:    c.Executor,
+		TxContainer: txContainer,
+		RecvC:       executeC,
+		CommitC:     commitC,
+		ReqC:        clientC,
+		SelfC:       replyC,
+		FetchC:      txFetchC,
+		ResponseC:   txResponseC,
+		ExecutedC:   executedC,
+		StableC:     stableSeqC,
+		TransferC:   transferC,
+		EpochC:      epochC,
+		Sender:      c.Sender,
+		Tools:       tools,
+		Logger:      c.Logger,
+	}
+	executeProcessor := executor.NewExecuteProcessor(executorConfig)
+
+	// checkpoint
+	checkpointConfig := checkpointType.Config{
+		ID:        c.ID,
+		N:         c.N,
+		Interval:  c.CheckpointInterval,
+		Seq:       executed.Seq,
+		Digest:    executed.Digest,
+		Stable:    executed.Stable,
+		Batches:   batches,
+		Store:     checkpointStore,
+		ExecutedC: executedC,
+		RecvC:     checkpointC,
+		StableC:   stableC,
+		ExecutorC: stableSeqC,
+		FetchC:    stateFetchC,
+		ResponseC: stateResponseC,
+		TransferC: transferC,
+		FilterC:   skipC,
+		DAGC:      dagSkipC,
+		EpochC:    checkpointEpochC,
+		Network:   c.Sender,
+		Tools:     tools,
+		Logger:    c.Logger,
+	}
+	checkpointProcessor := checkpoint.NewCheckpointProcessor(checkpointConfig)
+
+	falanx.forwardClient = fakeClient
+	falanx.localOrder = localOrder
+	falanx.txFilter = txFilter
+	falanx.localBA = localBA
+	falanx.graphEngine = graphEngine
+	falanx.dagManager = dagManager
+	falanx.executor = executeProcessor
+	falanx.checkpoint = checkpointProcessor
+
+	return falanx, nil
 }
 
 func (falanx *falanxImpl) start() {
 
+	falanx.forwardClient.Start()
+
 	falanx.localOrder.Start()
 
 	for _, replica := range falanx.replicasOrder {
@@ -184,8 +585,24 @@ func (falanx *falanxImpl) start() {
 
 	falanx.txFilter.Start()
 
+	falanx.localBA.Start()
+
 	falanx.graphEngine.Start()
 
+	falanx.dagManager.Start()
+
+	falanx.executor.Start()
+
+	falanx.checkpoint.Start()
+
+	if falanx.netRecvC != nil {
+		go falanx.listenNetwork()
+	}
+
+	go falanx.listenEpoch()
+
+	go falanx.listenClients()
+
 	falanx.logger.Info(`
 
 +=============================================================================+
@@ -201,11 +618,56 @@ func (falanx *falanxImpl) start() {
 `)
 }
 
+// stop is used to stop the listeners of falanx first, so that no message will be dispatched to the modules,
+// and then every module is stopped, the instances of client order and replica order are dropped with the
+// mutex held, so that they won't be stopped once again by eviction or reconfiguration
 func (falanx *falanxImpl) stop() {
 	close(falanx.close)
+
+	falanx.forwardClient.Stop()
+
+	falanx.localOrder.Stop()
+
+	falanx.mutex.Lock()
+	for id, replica := range falanx.replicasOrder {
+		replica.Stop()
+		delete(falanx.replicasOrder, id)
+	}
+	for id, client := range falanx.clientsOrder {
+		client.Stop()
+		delete(falanx.clientsOrder, id)
+	}
+	falanx.mutex.Unlock()
+
+	falanx.txFilter.Stop()
+
+	falanx.localBA.Stop()
+
+	falanx.graphEngine.Stop()
+
+	falanx.dagManager.Stop()
+
+	falanx.executor.Stop()
+
+	falanx.checkpoint.Stop()
 }
 
-func (falanx *falanxImpl) step(msg *pb.ConsensusMessage) {
+// listenNetwork is used to process the messages received by transport one by one
+func (falanx *falanxImpl) listenNetwork() {
+	for {
+		select {
+		case <-falanx.close:
+			return
+
+		case msg := <-falanx.netRecvC:
+			falanx.step(msg.From, msg.Msg)
+		}
+	}
+}
+
+// step is used to process the consensus message, from is the sender reported by transport, 0 if it is
+// unknown. the messages which are not signed should be sent by the replica they claim.
+func (falanx *falanxImpl) step(from uint64, msg *pb.ConsensusMessage) {
 	switch msg.Type {
 	case pb.Type_REQUEST_SET:
 		request := &pb.RequestSet{}
@@ -213,7 +675,7 @@ func (falanx *falanxImpl) step(msg *pb.ConsensusMessage) {
 		if err != nil {
 			return
 		}
-		falanx.forwardClient.ProposeTxs(request.Requests)
+		falanx.propose(request.Requests)
 	case pb.Type_ORDERED_REQ:
 		falanx.logger.Info("[REQ] Receive an ordered request")
 		req := &pb.OrderedReq{}
@@ -221,6 +683,14 @@ func (falanx *falanxImpl) step(msg *pb.ConsensusMessage) {
 		if err != nil {
 			return
 		}
+		if req.HashAlgorithm != falanx.tools.HashAlgorithm() {
+			falanx.logger.Warningf("[REQ] Reject ordered request from client %d: hash algorithm %s", req.ClientId, req.HashAlgorithm)
+			return
+		}
+		if err := zcommon.VerifyOrderedReq(falanx.tools, req); err != nil {
+			falanx.logger.Warningf("[REQ] Reject ordered request from client %d: %s", req.ClientId, err)
+			return
+		}
 		falanx.processOrderedReq(req)
 	case pb.Type_ORDERED_LOG:
 		falanx.logger.Info("[LOG] Receive an ordered log")
@@ -229,26 +699,336 @@ func (falanx *falanxImpl) step(msg *pb.ConsensusMessage) {
 		if err != nil {
 			return
 		}
+		if log.HashAlgorithm != falanx.tools.HashAlgorithm() {
+			falanx.logger.Warningf("[LOG] Reject ordered log from replica %d: hash algorithm %s", log.ReplicaId, log.HashAlgorithm)
+			return
+		}
+		if err := zcommon.VerifyOrderedLog(falanx.tools, log); err != nil {
+			falanx.logger.Warningf("[LOG] Reject ordered log from replica %d: %s", log.ReplicaId, err)
+			return
+		}
 		falanx.processOrderedLog(log)
+	case pb.Type_LOG_FETCH:
+		fetch := &pb.LogFetch{}
+		err := proto.Unmarshal(msg.Payload, fetch)
+		if err != nil {
+			return
+		}
+		if from != 0 && fetch.ReplicaId != from {
+			falanx.logger.Warningf("[LOG] Reject log fetch of replica %d from replica %d", fetch.ReplicaId, from)
+			return
+		}
+		falanx.processLogFetch(fetch)
+	case pb.Type_LOG_RESPONSE:
+		response := &pb.LogResponse{}
+		err := proto.Unmarshal(msg.Payload, response)
+		if err != nil {
+			return
+		}
+		// the logs relayed by others should be verified one by one, as they are signed by the origin
+		for _, log := range response.Logs {
+			if log.ReplicaId != response.Origin || log.HashAlgorithm != falanx.tools.HashAlgorithm() {
+				falanx.logger.Warningf("[LOG] Reject fetched log of replica %d from replica %d", log.ReplicaId, response.ReplicaId)
+				continue
+			}
+			if err := zcommon.VerifyOrderedLog(falanx.tools, log); err != nil {
+				falanx.logger.Warningf("[LOG] Reject fetched log of replica %d from replica %d: %s", log.ReplicaId, response.ReplicaId, err)
+				continue
+			}
+			falanx.processOrderedLog(log)
+		}
+	case pb.Type_REQ_FETCH:
+		fetch := &pb.ReqFetch{}
+		err := proto.Unmarshal(msg.Payload, fetch)
+		if err != nil {
+			return
+		}
+		if from != 0 && fetch.ReplicaId != from {
+			falanx.logger.Warningf("[REQ] Reject req fetch of replica %d from replica %d", fetch.ReplicaId, from)
+			return
+		}
+		falanx.processReqFetch(fetch)
+	case pb.Type_REQ_RESPONSE:
+		response := &pb.ReqResponse{}
+		err := proto.Unmarshal(msg.Payload, response)
+		if err != nil {
+			return
+		}
+		// the absent sequence numbers are counted for the replica which has signed the response
+		if err := zcommon.VerifyReqResponse(falanx.tools, response); err != nil {
+			falanx.logger.Warningf("[REQ] Reject req response of replica %d: %s", response.ReplicaId, err)
+			return
+		}
+		// the requests relayed by others should be verified one by one, as they are signed by the client
+		var reqs []*pb.OrderedReq
+		for _, req := range response.Reqs {
+			if req.ClientId != response.ClientId || req.HashAlgorithm != falanx.tools.HashAlgorithm() {
+				falanx.logger.Warningf("[REQ] Reject fetched request of client %d from replica %d", req.ClientId, response.ReplicaId)
+				continue
+			}
+			if err := zcommon.VerifyOrderedReq(falanx.tools, req); err != nil {
+				falanx.logger.Warningf("[REQ] Reject fetched request of client %d from replica %d: %s", req.ClientId, response.ReplicaId, err)
+				continue
+			}
+			reqs = append(reqs, req)
+		}
+		response.Reqs = reqs
+		falanx.processReqResponse(response)
+	case pb.Type_TX_SET:
+		set := &pb.TxSet{}
+		err := proto.Unmarshal(msg.Payload, set)
+		if err != nil {
+			return
+		}
+		if from != 0 && set.ReplicaId != from {
+			falanx.logger.Warningf("[TX] Reject tx set of client %d from replica %d", set.ReplicaId, from)
+			return
+		}
+		// the sender might not be known by transport, so that the client is identified by the signature, or
+		// anyone could exhaust the quota of others
+		if err := zcommon.VerifyTxSet(falanx.tools, set); err != nil {
+			falanx.logger.Warningf("[TX] Reject tx set of client %d: %s", set.ReplicaId, err)
+			return
+		}
+		// the payloads are stored with the hash calculated by ourselves, and the ones exceeding the
+		// quota of the client will be fetched once they have been referenced by a paved batch
+		for _, tx := range set.Txs {
+			if err := falanx.txContainer.Add(set.ReplicaId, tx); err != nil {
+				falanx.logger.Debugf("[TX] Reject payload from client %d: %s", set.ReplicaId, err)
+			}
+		}
+	case pb.Type_TX_FETCH:
+		fetch := &pb.TxFetch{}
+		err := proto.Unmarshal(msg.Payload, fetch)
+		if err != nil {
+			return
+		}
+		if from != 0 && fetch.ReplicaId != from {
+			falanx.logger.Warningf("[TX] Reject tx fetch of replica %d from replica %d", fetch.ReplicaId, from)
+			return
+		}
+		falanx.txFetchC <- fetch
+	case pb.Type_TX_RESPONSE:
+		response := &pb.TxSet{}
+		err := proto.Unmarshal(msg.Payload, response)
+		if err != nil {
+			return
+		}
+		falanx.txResponseC <- response
+	case pb.Type_CHECKPOINT:
+		checkpoint := &pb.Checkpoint{}
+		err := proto.Unmarshal(msg.Payload, checkpoint)
+		if err != nil {
+			return
+		}
+		if err := zcommon.VerifyCheckpoint(falanx.tools, checkpoint); err != nil {
+			falanx.logger.Warningf("[CHECKPOINT] Reject checkpoint %d of replica %d: %s", checkpoint.Seq, checkpoint.ReplicaId, err)
+			return
+		}
+		falanx.checkpointC <- checkpoint
+	case pb.Type_STATE_FETCH:
+		fetch := &pb.StateFetch{}
+		err := proto.Unmarshal(msg.Payload, fetch)
+		if err != nil {
+			return
+		}
+		if from != 0 && fetch.ReplicaId != from {
+			falanx.logger.Warningf("[CHECKPOINT] Reject state fetch of replica %d from replica %d", fetch.ReplicaId, from)
+			return
+		}
+		falanx.stateFetchC <- fetch
+	case pb.Type_STATE_RESPONSE:
+		response := &pb.StateResponse{}
+		err := proto.Unmarshal(msg.Payload, response)
+		if err != nil {
+			return
+		}
+		if from != 0 && response.ReplicaId != from {
+			falanx.logger.Warningf("[CHECKPOINT] Reject state response of replica %d from replica %d", response.ReplicaId, from)
+			return
+		}
+		if response.Checkpoint == nil {
+			return
+		}
+		// the stable checkpoint is proved by the signed checkpoints from 2f+1 replicas
+		for _, proof := range response.Checkpoint.Proofs {
+			if err := zcommon.VerifyCheckpoint(falanx.tools, proof); err != nil {
+				falanx.logger.Warningf("[CHECKPOINT] Reject state response from replica %d: %s", response.ReplicaId, err)
+				return
+			}
+		}
+		falanx.stateResponseC <- response
+	case pb.Type_BA_VOTE:
+		vote := &pb.BaVote{}
+		err := proto.Unmarshal(msg.Payload, vote)
+		if err != nil {
+			return
+		}
+		if err := zcommon.VerifyBaVote(falanx.tools, vote); err != nil {
+			falanx.logger.Warningf("[BA] Reject vote of replica %d: %s", vote.ReplicaId, err)
+			return
+		}
+		falanx.baRecvC <- vote
+	case pb.Type_SUSPECT:
+		suspect := &pb.Suspect{}
+		err := proto.Unmarshal(msg.Payload, suspect)
+		if err != nil {
+			return
+		}
+		if err := zcommon.VerifySuspect(falanx.tools, suspect); err != nil {
+			falanx.logger.Warningf("[BA] Reject suspect of replica %d: %s", suspect.ReplicaId, err)
+			return
+		}
+		falanx.suspectC <- suspect
+	case pb.Type_REPLY:
+		reply := &pb.Reply{}
+		err := proto.Unmarshal(msg.Payload, reply)
+		if err != nil {
+			return
+		}
+		if reply.ClientId != falanx.id {
+			return
+		}
+		falanx.replyC <- reply
+	}
+}
+
+func (falanx *falanxImpl) propose(txs []*pb.Transaction) {
+	// only the transactions accepted by local container will be proposed, the others should be retried later
+	var accepted []*pb.Transaction
+	for _, tx := range txs {
+		if err := falanx.txContainer.Add(falanx.id, tx); err != nil {
+			falanx.logger.Warningf("Replica %d reject proposed transaction: %s", falanx.id, err)
+			continue
+		}
+		accepted = append(accepted, tx)
+	}
+	if len(accepted) == 0 {
+		return
 	}
+	falanx.forwardClient.ProposeTxs(accepted)
 }
 
+// processOrderedReq is used to dispatch the ordered request to the instance of its client. like the other
+// process functions, the message is dispatched without holding the mutex, as the instance might be busy, and
+// the eviction or reconfiguration shouldn't wait for it. the client order instance is marked pending until
+// the message has arrived, so that it won't be evicted, and the messages for the stopped replica order
+// instances are dropped
 func (falanx *falanxImpl) processOrderedReq(req *pb.OrderedReq) {
 	falanx.logger.Debugf("Replica %d receive an ordered request from client %d", falanx.id, req.ClientId)
+	falanx.mutex.RLock()
 	recvC, ok := falanx.reqRecvC[req.ClientId]
+	var pending *int32
 	if ok {
-		recvC <- req
+		pending = falanx.pendingClient(req.ClientId)
+	}
+	falanx.mutex.RUnlock()
+
+	if !ok {
+		falanx.mutex.Lock()
+		if ok = falanx.admitClient(req.ClientId); ok {
+			recvC = falanx.reqRecvC[req.ClientId]
+			pending = falanx.pendingClient(req.ClientId)
+		} else {
+			falanx.logger.Warningf("[REQ] Reject ordered request from client %d, %d clients are being served", req.ClientId, len(falanx.clientsOrder))
+		}
+		falanx.mutex.Unlock()
+	}
+	if !ok {
 		return
 	}
+
+	defer atomic.AddInt32(pending, -1)
+	select {
+	case recvC <- req:
+	case <-falanx.close:
+	}
 }
 
 func (falanx *falanxImpl) processOrderedLog(log *pb.OrderedLog) {
 	falanx.logger.Debugf("Replica %d receive an ordered log from replica %d, seq %d", falanx.id, log.ReplicaId, log.Sequence)
+	falanx.mutex.RLock()
+	member := falanx.replicas[log.ReplicaId]
+	epoch := falanx.epoch
 	recvC, ok := falanx.logRecvC[log.ReplicaId]
+	stopC := falanx.logStopC[log.ReplicaId]
+	falanx.mutex.RUnlock()
+
+	if !member {
+		falanx.logger.Warningf("[LOG] Reject ordered log from replica %d, which is not in epoch %d", log.ReplicaId, epoch)
+		return
+	}
+	if !ok {
+		falanx.logger.Errorf("invalid replica %d", log.ReplicaId)
+		return
+	}
+
+	select {
+	case recvC <- log:
+	case <-stopC:
+	case <-falanx.close:
+	}
+}
+
+func (falanx *falanxImpl) processReqFetch(fetch *pb.ReqFetch) {
+	falanx.logger.Debugf("Replica %d receive a req fetch from replica %d, client %d, seq [%d, %d]", falanx.id, fetch.ReplicaId, fetch.ClientId, fetch.FromSeq, fetch.ToSeq)
+	falanx.mutex.RLock()
+	fetchC, ok := falanx.reqFetchC[fetch.ClientId]
+	var pending *int32
 	if ok {
-		recvC <- log
+		pending = falanx.pendingClient(fetch.ClientId)
+	}
+	falanx.mutex.RUnlock()
+
+	if !ok {
+		// the requests of the clients which haven't been served or have been evicted are not kept
+		falanx.logger.Debugf("No client order instance for client %d", fetch.ClientId)
 		return
 	}
 
-	falanx.logger.Errorf("invalid replica %d", log.ReplicaId)
+	defer atomic.AddInt32(pending, -1)
+	select {
+	case fetchC <- fetch:
+	case <-falanx.close:
+	}
+}
+
+func (falanx *falanxImpl) processReqResponse(response *pb.ReqResponse) {
+	falanx.mutex.RLock()
+	responseC, ok := falanx.reqResponseC[response.ClientId]
+	var pending *int32
+	if ok {
+		pending = falanx.pendingClient(response.ClientId)
+	}
+	falanx.mutex.RUnlock()
+
+	if !ok {
+		falanx.logger.Debugf("No client order instance for client %d", response.ClientId)
+		return
+	}
+
+	defer atomic.AddInt32(pending, -1)
+	select {
+	case responseC <- response:
+	case <-falanx.close:
+	}
+}
+
+func (falanx *falanxImpl) processLogFetch(fetch *pb.LogFetch) {
+	falanx.logger.Debugf("Replica %d receive a log fetch from replica %d, origin %d, seq [%d, %d]", falanx.id, fetch.ReplicaId, fetch.Origin, fetch.FromSeq, fetch.ToSeq)
+	falanx.mutex.RLock()
+	fetchC, ok := falanx.fetchRecvC[fetch.Origin]
+	stopC := falanx.logStopC[fetch.Origin]
+	falanx.mutex.RUnlock()
+
+	if !ok {
+		falanx.logger.Errorf("invalid replica %d", fetch.Origin)
+		return
+	}
+
+	select {
+	case fetchC <- fetch:
+	case <-stopC:
+	case <-falanx.close:
+	}
 }
@@ -0,0 +1,43 @@
+package falanx
+
+import (
+	"testing"
+
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	"github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// TestRejectHashAlgorithm delivers the ordered logs signed by replica 2 to replica 1, the one calculated
+// with a different hash algorithm should be rejected before it's dispatched to the order module
+func TestRejectHashAlgorithm(t *testing.T) {
+	cluster := newTestCluster(t, 4, func(c *types.Config) {
+		c.Hash = pb.HashAlgorithm_SHA3
+	})
+	node := cluster.nodes[0]
+	sender := cluster.nodes[1]
+
+	step := func(algorithm pb.HashAlgorithm) {
+		log := &pb.OrderedLog{ReplicaId: 2, Sequence: 1, TxHash: "tx", HashAlgorithm: algorithm}
+		if err := zcommon.SignOrderedLog(sender.tools, log); err != nil {
+			t.Fatal(err)
+		}
+		payload, err := log.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		node.step(2, &pb.ConsensusMessage{Type: pb.Type_ORDERED_LOG, Payload: payload})
+	}
+
+	for _, algorithm := range []pb.HashAlgorithm{pb.HashAlgorithm_HASH_UNSPECIFIED, pb.HashAlgorithm_SHA256, pb.HashAlgorithm_BLAKE2B} {
+		step(algorithm)
+		if len(node.logRecvC[2]) != 0 {
+			t.Fatalf("accept the ordered log with hash algorithm %s", algorithm)
+		}
+	}
+
+	step(pb.HashAlgorithm_SHA3)
+	if len(node.logRecvC[2]) != 1 {
+		t.Fatalf("reject the ordered log with the selected hash algorithm")
+	}
+}
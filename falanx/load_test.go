@@ -0,0 +1,186 @@
+package falanx
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	checkpointTypes "github.com/Grivn/libfalanx/checkpoint/types"
+	checkpointUtils "github.com/Grivn/libfalanx/checkpoint/utils"
+	dagTypes "github.com/Grivn/libfalanx/dagmanager/types"
+	filterTypes "github.com/Grivn/libfalanx/filter/types"
+	"github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// TestSustainedLoad keeps proposing transactions from all the replicas to a cluster which persists its
+// states, the write-ahead logs and the filter snapshots, which hold the states of local order and txFilter,
+// shouldn't grow with the throughput once the batches have been covered by the stable checkpoints. neither
+// should the states kept in memory by txFilter, dagManager and checkpoint. the checkpoints should keep
+// becoming stable with the transactions proposed by all the replicas.
+func TestSustainedLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "falanx-load")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const (
+		phases   = 32
+		txs      = 40
+		interval = 2
+	)
+	cluster := newTestCluster(t, 4, func(c *types.Config) {
+		c.CheckpointInterval = interval
+		c.DataDir = filepath.Join(dir, fmt.Sprint(c.ID))
+	})
+	cluster.start()
+	defer cluster.stop()
+
+	var bound int64
+	var stable uint64
+	for phase := 0; phase < phases; phase++ {
+		cluster.propose(fmt.Sprintf("load-%d", phase), txs/len(cluster.nodes))
+		cluster.waitCommitted(t, (phase+1)*txs, 60*time.Second)
+
+		if phase == 0 {
+			// the states of the first phase might not have been collected in time, and the last batches of every
+			// phase might not be covered by a stable checkpoint until the next phase, as the transactions from
+			// different replicas are not paved in the same batch
+			time.Sleep(time.Second)
+			bound = 16 * persistedSize(t, dir, len(cluster.nodes))
+			continue
+		}
+		waitPersisted(t, dir, len(cluster.nodes), bound, 10*time.Second)
+		waitBounded(t, cluster, txs, interval, 10*time.Second)
+		stable = waitStable(t, cluster, dir, stable, txs, interval, 10*time.Second)
+	}
+	cluster.checkCommitted(t)
+
+	for index, node := range cluster.nodes {
+		if metrics := node.MempoolMetrics(); metrics.Size != 0 {
+			t.Fatalf("replica %d keeps %d txs in mempool after execution", index+1, metrics.Size)
+		}
+	}
+}
+
+// waitBounded is used to wait until the states kept in memory by every replica have been collected, the
+// unstable states are bounded by the transactions of one phase, while the executed marks of dagManager and
+// the history of checkpoint are bounded by the window of batches to keep them
+func waitBounded(t *testing.T, cluster *testCluster, txs int, interval int, timeout time.Duration) {
+	executed := dagTypes.DefaultExecutedBatches*filterTypes.DefaultGraphSize + txs
+	history := interval * checkpointTypes.DefaultHistory
+	deadline := time.Now().Add(timeout)
+	for {
+		var err error
+		for index, node := range cluster.nodes {
+			filter := node.txFilter.(interface{ Metrics() filterTypes.Metrics }).Metrics()
+			dag := node.dagManager.(interface{ Metrics() dagTypes.Metrics }).Metrics()
+			checkpoint := node.checkpoint.(interface {
+				Metrics() checkpointTypes.Metrics
+			}).Metrics()
+
+			switch {
+			case filter.Logs > txs || filter.Tombstones > txs || filter.OrderedBy > txs || filter.Pending > txs ||
+				filter.Finished > txs || filter.Recorders > txs || filter.Verified > txs:
+				err = fmt.Errorf("replica %d keeps filter states %+v, expect at most %d", index+1, filter, txs)
+			case dag.Batches+dag.Vertices+dag.Edges+dag.Pending+dag.Resolved > txs || dag.Executed > executed:
+				err = fmt.Errorf("replica %d keeps DAG states %+v, expect at most %d executed", index+1, dag, executed)
+			case checkpoint.Local > checkpointTypes.DefaultWindow || checkpoint.Votes > len(cluster.nodes)*checkpointTypes.DefaultWindow ||
+				checkpoint.Executed > txs || checkpoint.History > history:
+				err = fmt.Errorf("replica %d keeps checkpoint states %+v", index+1, checkpoint)
+			}
+		}
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitStable is used to wait until the checkpoints of the executed batches have become stable on all the
+// replicas, which means that less than one interval of executed batches are above the stable checkpoint.
+// the replicas should agree on the digest of the same checkpoint, and the checkpoint snapshots should only
+// keep the batches of latest DefaultHistory intervals and the ones above the stable checkpoint
+func waitStable(t *testing.T, cluster *testCluster, dir string, previous uint64, txs int, interval int, timeout time.Duration) uint64 {
+	history := interval*checkpointTypes.DefaultHistory + txs
+	deadline := time.Now().Add(timeout)
+	for {
+		var err error
+		low := uint64(0)
+		digests := make(map[uint64]string)
+		for index, node := range cluster.nodes {
+			stable := node.StableCheckpoint()
+			checkpoint := node.checkpoint.(interface {
+				Metrics() checkpointTypes.Metrics
+			}).Metrics()
+			if stable == nil || stable.Seq < previous || checkpoint.Executed >= interval {
+				err = fmt.Errorf("replica %d keeps %d executed batches above stable checkpoint %v", index+1, checkpoint.Executed, stable)
+				break
+			}
+			if digest, ok := digests[stable.Seq]; ok && digest != stable.Digest {
+				t.Fatalf("replica %d diverges in stable checkpoint %d, digest %s, expect %s", index+1, stable.Seq, stable.Digest, digest)
+			}
+			digests[stable.Seq] = stable.Digest
+			if low == 0 || stable.Seq < low {
+				low = stable.Seq
+			}
+
+			snapshot, loadErr := checkpointUtils.NewSnapshotStore(filepath.Join(dir, fmt.Sprint(index+1), types.CheckpointSnapshot)).Load()
+			if loadErr != nil {
+				t.Fatal(loadErr)
+			}
+			if snapshot == nil || len(snapshot.Batches) > history {
+				err = fmt.Errorf("replica %d persists checkpoint snapshot %v, expect at most %d batches", index+1, snapshot != nil, history)
+				break
+			}
+		}
+		if err == nil {
+			return low
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitPersisted is used to wait until the persisted states of every replica have been compacted under bound
+func waitPersisted(t *testing.T, dir string, n int, bound int64, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for {
+		size := persistedSize(t, dir, n)
+		if size <= bound {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("persisted %d bytes, expect at most %d bytes", size, bound)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// persistedSize returns the largest size of the write-ahead logs and the filter snapshots of the replicas
+func persistedSize(t *testing.T, dir string, n int) int64 {
+	var size int64
+	for id := 1; id <= n; id++ {
+		for _, name := range []string{types.LocalOrderWAL, types.FilterSnapshot} {
+			info, err := os.Stat(filepath.Join(dir, fmt.Sprint(id), name))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size() > size {
+				size = info.Size()
+			}
+		}
+	}
+	return size
+}
@@ -0,0 +1,36 @@
+package falanx
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPartitionAndHeal runs a cluster of 4 replicas through partitions, the messages lost during partition
+// are fetched once the replicas find the gaps in the logs received after healing
+func TestPartitionAndHeal(t *testing.T) {
+	cluster := newTestCluster(t, 4, nil)
+	cluster.start()
+	defer cluster.stop()
+
+	cluster.propose("before", 5)
+	cluster.waitCommitted(t, 20, 20*time.Second)
+
+	// neither of the groups could collect the quorum, so that the transactions proposed during partition
+	// could only be committed after healing
+	cluster.hub.Partition([]uint64{1, 2}, []uint64{3, 4})
+	cluster.propose("split", 5)
+	time.Sleep(time.Second)
+	cluster.hub.Heal()
+	cluster.propose("split-healed", 5)
+	cluster.waitCommitted(t, 60, 60*time.Second)
+
+	// replica 4 is isolated from the others, and it catches up after healing
+	cluster.hub.Partition([]uint64{1, 2, 3}, []uint64{4})
+	cluster.propose("isolated", 5)
+	time.Sleep(time.Second)
+	cluster.hub.Heal()
+	cluster.propose("isolated-healed", 5)
+	cluster.waitCommitted(t, 100, 60*time.Second)
+
+	cluster.checkCommitted(t)
+}
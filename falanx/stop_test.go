@@ -0,0 +1,59 @@
+package falanx
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Grivn/libfalanx/localorder/utils"
+	"github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// TestStopModules stops a running cluster, the goroutines of every module should exit and the write-ahead
+// logs should have been closed, so that they could be replayed by the restarted replicas
+func TestStopModules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "falanx-stop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	before := runtime.NumGoroutine()
+	cluster := newTestCluster(t, 4, func(c *types.Config) {
+		c.DataDir = filepath.Join(dir, fmt.Sprint(c.ID))
+	})
+	cluster.start()
+	cluster.propose("stop", 5)
+	cluster.waitCommitted(t, 20, 20*time.Second)
+	cluster.stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<20)
+			t.Fatalf("%d goroutines remain after stop, %d before start\n%s", runtime.NumGoroutine(), before, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for id := 1; id <= len(cluster.nodes); id++ {
+		wal, err := utils.NewWAL(filepath.Join(dir, fmt.Sprint(id), types.LocalOrderWAL))
+		if err != nil {
+			t.Fatal(err)
+		}
+		logs, err := wal.Replay()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(logs) == 0 {
+			t.Fatalf("replica %d persisted no local order", id)
+		}
+		if err := wal.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"math"
+
+	"github.com/Grivn/libfalanx/filter/types"
+	"github.com/Grivn/libfalanx/filter/utils"
+	"github.com/Grivn/libfalanx/logger"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// reconfigure is used to pave the txs with the logs from the replicas of new epoch, the logs from the removed
+// replicas will be dropped, and current batch will be paved from the first round once again.
+func (p *pavingMgr) reconfigure(event tp.EpochEvent) {
+	p.logger.Infof("[PAVE] switch to epoch %d, replicas %v", event.Epoch, event.Replicas)
+	p.n = len(event.Replicas)
+	p.f = faultTolerance(p.n)
+	reconfigureRecorder(p.vpRecorder, event.Replicas, p.logger)
+	p.whitelist = event.Replicas
+	p.removals = nil
+	p.round = 0
+	p.pavedTxs = make(map[string]bool)
+	for _, log := range p.early.release(event.Replicas) {
+		p.add(log)
+	}
+}
+
+// reconfigure is used to re-select the candidates for every transaction from the replicas of new epoch.
+func (v *verifyingMgr) reconfigure(event tp.EpochEvent) {
+	v.logger.Infof("[VERIFY] switch to epoch %d, replicas %v", event.Epoch, event.Replicas)
+	v.n = len(event.Replicas)
+	v.f = faultTolerance(v.n)
+	v.whitelist = event.Replicas
+	v.replicas = replicaSet(event.Replicas)
+	for _, recorder := range v.txRecorder {
+		recorder.Reconfigure(event.Replicas, v.n, v.f)
+	}
+	for _, log := range v.early.release(event.Replicas) {
+		v.add(log)
+	}
+}
+
+// reconfigure is used to relate the txs with the logs from the replicas of new epoch. the logs from the
+// removed replicas will never arrive, so the votes from them for the undetermined relations are dropped, and
+// the waiting txs of current batch will be related with the new replica set.
+func (g *graphingMgr) reconfigure(event tp.EpochEvent) {
+	g.logger.Infof("[GRAPH] switch to epoch %d, replicas %v", event.Epoch, event.Replicas)
+	removed := reconfigureRecorder(g.vpRecorder, event.Replicas, g.logger)
+	for _, id := range removed {
+		delete(g.blacklist, id)
+		delete(g.readmit, id)
+		delete(g.progress, id)
+	}
+	// the local BA has been reset for the new epoch, and the exclusions held for the old one are dropped
+	g.held = nil
+	for _, cert := range g.certStore {
+		resetCert(cert, removed)
+	}
+	for _, cert := range g.pending {
+		resetCert(cert, removed)
+	}
+
+	for _, log := range g.early.release(event.Replicas) {
+		g.add(log)
+	}
+
+	// the logs from the removed replicas are no longer awaited to forget the collected txs
+	g.forgetCollected()
+
+	g.resolvePending()
+	if g.graphing {
+		g.relateTxs()
+	}
+}
+
+// resetCert is used to scan the logs for an undetermined relation once again, if the votes from the removed
+// replicas have been counted in it, as we cannot tell which side they have preferred
+func resetCert(cert *types.RelationCert, removed []uint64) {
+	if cert.Finished {
+		return
+	}
+	for _, id := range removed {
+		if cert.Scanned[id] {
+			cert.Scanned = make(map[uint64]bool)
+			cert.FormerPreferred = 0
+			cert.LatterPreferred = 0
+			return
+		}
+	}
+}
+
+// earlyLogs is used to hold the logs from the replicas which are not in current epoch of the manager, as
+// the logs from the added replicas might arrive before the epoch event. struct: replica id ==> logs
+type earlyLogs map[uint64][]*pb.OrderedLog
+
+func (e earlyLogs) hold(log *pb.OrderedLog) {
+	e[log.ReplicaId] = append(e[log.ReplicaId], log)
+}
+
+// release is used to return the held logs from the replicas of new epoch, and the ones from the replicas
+// outside of it are the late logs from the removed replicas, which will be dropped
+func (e earlyLogs) release(replicas []int) []*pb.OrderedLog {
+	var logs []*pb.OrderedLog
+	for _, id := range replicas {
+		logs = append(logs, e[uint64(id)]...)
+	}
+	for id := range e {
+		delete(e, id)
+	}
+	return logs
+}
+
+func replicaSet(replicas []int) map[uint64]bool {
+	set := make(map[uint64]bool)
+	for _, id := range replicas {
+		set[uint64(id)] = true
+	}
+	return set
+}
+
+// reconfigureRecorder is used to create the log lists for the added replicas, and drop the ones for the
+// removed replicas, which will be returned.
+func reconfigureRecorder(vpRecorder map[uint64]utils.TxList, replicas []int, logger logger.Logger) []uint64 {
+	current := make(map[uint64]bool)
+	for _, id := range replicas {
+		current[uint64(id)] = true
+		if _, ok := vpRecorder[uint64(id)]; !ok {
+			vpRecorder[uint64(id)] = utils.NewTxList(logger)
+		}
+	}
+
+	var removed []uint64
+	for id := range vpRecorder {
+		if !current[id] {
+			delete(vpRecorder, id)
+			removed = append(removed, id)
+		}
+	}
+	return removed
+}
+
+// copyEpoch is used to deliver the epoch to every manager with its own replica list, as the list might be
+// sorted or modified by them
+func copyEpoch(event tp.EpochEvent) tp.EpochEvent {
+	replicas := make([]int, len(event.Replicas))
+	copy(replicas, event.Replicas)
+	event.Replicas = replicas
+	return event
+}
+
+func faultTolerance(n int) int {
+	f := int(math.Floor((float64(n) - 1) / 4))
+	if f == 0 {
+		f = 1
+	}
+	return f
+}
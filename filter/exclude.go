@@ -0,0 +1,121 @@
+package filter
+
+import (
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// exclude is used to remove the replica from whitelist from the batch agreed by local BA, so that every
+// replica paves the batch with the same whitelist. the removal for a later batch is held until paving
+// manager reaches it, and the one for a batch which has been paved takes effect from the next batch, as the
+// paved batch cannot be paved once again.
+func (p *pavingMgr) exclude(event tp.ExcludeEvent) {
+	if event.Seq > p.batchSeq {
+		p.logger.Infof("[PAVE] hold the removal of replica %d until batch %d", event.ID, event.Seq)
+		p.removals = append(p.removals, event)
+		return
+	}
+	if len(p.pavedTxs) >= p.maxLen {
+		p.logger.Warningf("[PAVE] batch %d has been paved, remove replica %d from batch %d", p.batchSeq, event.ID, p.batchSeq+1)
+		p.removals = append(p.removals, tp.ExcludeEvent{ID: event.ID, Seq: p.batchSeq + 1})
+		return
+	}
+	if event.Seq < p.batchSeq {
+		p.logger.Warningf("[PAVE] batch %d has been paved, remove replica %d from batch %d", event.Seq, event.ID, p.batchSeq)
+	}
+	p.update(removeReplica(p.whitelist, event.ID))
+}
+
+// applyRemovals is used to remove the replicas held for current batch before paving it
+func (p *pavingMgr) applyRemovals() {
+	var held []tp.ExcludeEvent
+	for _, event := range p.removals {
+		if event.Seq > p.batchSeq {
+			held = append(held, event)
+			continue
+		}
+		p.logger.Infof("[PAVE] remove replica %d from batch %d", event.ID, p.batchSeq)
+		p.whitelist = removeReplica(p.whitelist, event.ID)
+	}
+	p.removals = held
+}
+
+// exclude is used to blacklist the replica from the batch agreed by local BA until DefaultBlacklistBatches
+// batches later, so that every replica relates the txs of a batch with the logs from the same replicas. the
+// exclusion for a later batch is held until graphing manager reaches it, and the one for a batch which has
+// been finalized takes effect from current batch.
+func (g *graphingMgr) exclude(event tp.ExcludeEvent) {
+	readmit := event.Seq + tp.DefaultBlacklistBatches
+	if readmit <= g.preferSeq {
+		g.logger.Debugf("[GRAPH] ignore the expired exclusion of replica %d in batch %d", event.ID, event.Seq)
+		return
+	}
+	if event.Seq > g.preferSeq {
+		g.logger.Infof("[GRAPH] hold the exclusion of replica %d until batch %d", event.ID, event.Seq)
+		g.held = append(g.held, event)
+		return
+	}
+	if event.Seq < g.preferSeq {
+		g.logger.Warningf("[GRAPH] batch %d has been finalized, exclude the logs from replica %d from batch %d", event.Seq, event.ID, g.preferSeq)
+	}
+	g.blacklistReplica(event.ID, readmit)
+	g.forgetCollected()
+
+	// the relations waiting for the logs from the blacklisted replica could be determined without it
+	g.resolvePending()
+	if g.graphing {
+		g.relateTxs()
+	}
+}
+
+// blacklistReplica is used to ignore the logs from the replica until batch readmit, the blacklisted replica
+// would be blacklisted until the later one if it has been suspected once again.
+func (g *graphingMgr) blacklistReplica(id uint64, readmit uint64) {
+	g.logger.Infof("[GRAPH] exclude the logs from replica %d until batch %d", id, readmit)
+	g.blacklist[id] = true
+	if readmit > g.readmit[id] {
+		g.readmit[id] = readmit
+	}
+}
+
+// advance is used to move on to the next batch once current one has been finalized or abandoned. the
+// blacklisted replicas whose exclusion has expired are readmitted, and the exclusions held for the next
+// batch take effect.
+func (g *graphingMgr) advance() {
+	g.preferSeq++
+	if g.batchC != nil {
+		g.batchC <- g.preferSeq
+	}
+
+	for id, readmit := range g.readmit {
+		if readmit <= g.preferSeq {
+			g.logger.Infof("[GRAPH] readmit the logs from replica %d from batch %d", id, g.preferSeq)
+			delete(g.blacklist, id)
+			delete(g.readmit, id)
+		}
+	}
+
+	if len(g.held) == 0 {
+		return
+	}
+	var held []tp.ExcludeEvent
+	for _, event := range g.held {
+		if event.Seq > g.preferSeq {
+			held = append(held, event)
+			continue
+		}
+		g.blacklistReplica(event.ID, event.Seq+tp.DefaultBlacklistBatches)
+	}
+	g.held = held
+	g.forgetCollected()
+}
+
+// removeReplica returns a new whitelist without particular replica
+func removeReplica(whitelist []int, id uint64) []int {
+	var removed []int
+	for _, replica := range whitelist {
+		if uint64(replica) != id {
+			removed = append(removed, replica)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,70 @@
+package filter
+
+import (
+	"fmt"
+	"testing"
+
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// TestPavingExclude removes the replicas from whitelist in different batches, the removals should take
+// effect once paving manager has reached the agreed batches
+func TestPavingExclude(t *testing.T) {
+	p := newTestFilter(nil, nil).pavingMgr
+	p.batchSeq = 2
+
+	p.exclude(tp.ExcludeEvent{ID: 4, Seq: 3})
+	if fmt.Sprint(p.whitelist) != "[1 2 3 4]" {
+		t.Fatalf("remove replica 4 in batch 2 with whitelist %v", p.whitelist)
+	}
+	p.resume(3)
+	if fmt.Sprint(p.whitelist) != "[1 2 3]" {
+		t.Fatalf("whitelist %v in batch 3, expect [1 2 3]", p.whitelist)
+	}
+
+	// batch 3 has been paved, so that the removal for it takes effect from the next batch
+	for index := 0; index < p.maxLen; index++ {
+		p.pavedTxs[fmt.Sprintf("tx-%d", index)] = true
+	}
+	p.exclude(tp.ExcludeEvent{ID: 3, Seq: 3})
+	if fmt.Sprint(p.whitelist) != "[1 2 3]" || len(p.pavedTxs) != p.maxLen {
+		t.Fatalf("the paved batch 3 is paved once again with whitelist %v", p.whitelist)
+	}
+	p.resume(4)
+	if fmt.Sprint(p.whitelist) != "[1 2]" {
+		t.Fatalf("whitelist %v in batch 4, expect [1 2]", p.whitelist)
+	}
+}
+
+// TestGraphingExclude blacklists replica 4 in a later batch, it should be excluded once graphing manager
+// has reached the batch, and it should be readmitted after DefaultBlacklistBatches batches
+func TestGraphingExclude(t *testing.T) {
+	g := newTestFilter(nil, nil).graphingMgr
+	g.preferSeq = 2
+
+	g.exclude(tp.ExcludeEvent{ID: 4, Seq: 3})
+	if g.blacklist[4] || g.counted() != 4 {
+		t.Fatalf("replica 4 is blacklisted in batch 2")
+	}
+	g.advance()
+	if !g.blacklist[4] || g.counted() != 3 {
+		t.Fatalf("replica 4 is not blacklisted in batch 3")
+	}
+
+	for g.preferSeq < 3+tp.DefaultBlacklistBatches-1 {
+		g.advance()
+	}
+	if !g.blacklist[4] {
+		t.Fatalf("replica 4 is readmitted in batch %d", g.preferSeq)
+	}
+	g.advance()
+	if g.blacklist[4] || g.counted() != 4 {
+		t.Fatalf("replica 4 is not readmitted in batch %d", g.preferSeq)
+	}
+
+	// the exclusion which has expired is ignored, such as the one forwarded once again
+	g.exclude(tp.ExcludeEvent{ID: 4, Seq: 3})
+	if g.blacklist[4] {
+		t.Fatalf("replica 4 is blacklisted with the expired exclusion")
+	}
+}
@@ -12,4 +12,9 @@ func (tf *transactionsFilterImpl) Start() {
 
 func (tf *transactionsFilterImpl) Stop() {
 	tf.stop()
-}
\ No newline at end of file
+}
+
+// Metrics is used to inspect the amount of states kept by filter
+func (tf *transactionsFilterImpl) Metrics() types.Metrics {
+	return tf.metrics()
+}
@@ -83,53 +83,104 @@ type transactionsFilterImpl struct {
 	// of timer should be started one by one, which means we would like to track the only one
 	// sequence number's legality at one moment.
 	//
-	whitelist    []int
-	delay        time.Duration
-	paving       bool
-	gathering    bool
-	appointing   bool
-	pavedTxs     []string
-	gatheredTxs  map[string]bool
-	appointedTxs map[string]bool
-	graphSize    int
+	whitelist     []int
+	delay         time.Duration
+	paving        bool
+	pavingSeq     uint64
+	gathering     bool
+	gatheringSeq  uint64
+	appointing    bool
+	appointingSeq uint64
+	pavedTxs      []string
+	gatheredTxs   map[string]bool
+	appointedTxs  map[string]bool
+	graphSize     int
 
 	waiting []string
 
 	// channel =====================================================================
 	// replicaOrder:    channel used to deliver the ordered logs from replicas
-	// graphEngine:     channel used to deliver the transactions to candidate_filter
+	// graphEngine:     channel used to deliver the finalized relation graph to graph engine
+	// timerC:          channel used to receive the requests to arm or disarm timers from managers
 	// pavingTimer:     channel used to process timeout events for paving check
 	// pavingExit:      channel used to stop
 	// gatheringTimer:  channel used to process timeout events for gathering check
 	// gatheringExit:   channel used to stop
 	// appointingTimer: channel used to process timeout events for appointing check
 	// appointingExit:  channel used to stop
+	// baC:             channel used to trigger local byzantine agreement to remove replicas
+	// whitelistC:      channel used to receive the replicas removed from whitelist by local byzantine agreement
+	// blacklistC:      channel used to receive the replicas blacklisted by local byzantine agreement
 	// close:           channel used to stop
 	//
 	// replica_order ------------ replicaOrder -----> recorder
+	// managers ----------------- timerC -----------> timers
+	// timers ------------------- timeoutC ---------> managers
 	// txsGraph ----------------- paving -----------> timeout or pavedTxs
 	// pavedTxs, txRecorder ----- gathering --------> timeout or gatheredTxs
 	// gatheredTxs, txRecorder -- appointing -------> timeout or appointedTxs
 	// appointedTxs ------------- graphEngine ------> graph_engine
+	// timeout ------------------ baC --------------> local_ba
+	// local_ba ----------------- whitelistC -------> pavingMgr, verifyingMgr
+	// local_ba ----------------- blacklistC -------> graphingMgr
 	//
 	replicaOrder chan *pb.OrderedLog
-	graphEngine  chan interface{}
+	graphEngine  chan tp.GraphEvent
 	certStore map[types.RelationId]*types.RelationCert
 
-	pavingTimer     chan bool
+	timerC          chan types.TimerEvent
+	pavingTimer     chan uint64
 	pavingExit      chan bool
-	gatheringTimer  chan bool
+	gatheringTimer  chan uint64
 	gatheringExit   chan bool
-	appointingTimer chan bool
+	appointingTimer chan uint64
 	appointingExit  chan bool
+	baC             chan tp.LocalBAEvent
+	whitelistC      chan tp.ExcludeEvent
+	blacklistC      chan tp.ExcludeEvent
+	stableC         chan tp.ExecuteEvent
+	transferC       chan tp.TransferEvent
+	epochC          chan tp.EpochEvent
 	close           chan bool
 
 	pavingRecvC    chan *pb.OrderedLog
 	verifyingRecvC chan *pb.OrderedLog
 	graphingRecvC  chan *pb.OrderedLog
 
+	pavingWhitelistC    chan tp.ExcludeEvent
+	verifyingWhitelistC chan []int
+	graphingBlacklistC  chan tp.ExcludeEvent
+
+	pavingEpochC    chan tp.EpochEvent
+	verifyingEpochC chan tp.EpochEvent
+	graphingEpochC  chan tp.EpochEvent
+
+	graphingStableC   chan tp.ExecuteEvent
+	graphingTransferC chan []string
+
+	pavingTimeoutC    chan uint64
+	verifyingTimeoutC chan uint64
+	graphingTimeoutC  chan uint64
+
+	pavingMetricsC    chan chan types.Metrics
+	verifyingMetricsC chan chan types.Metrics
+	graphingMetricsC  chan chan types.Metrics
+
 	commC chan *pb.OrderedLog
 
+	// snapshot ====================================================================
+	// store:     used to persist the snapshots, nil if the snapshot is disabled
+	// recovery:  the snapshot to resume from on start
+	// snapshotC: channel used to receive the snapshots from graphing manager
+	// persisted: the progress of current replica in the persisted snapshots which haven't become stable
+	// compactC:  channel used to notify local order of the logs covered by a persisted stable snapshot
+	id        uint64
+	store     utils.SnapshotStore
+	recovery  *pb.FilterSnapshot
+	snapshotC chan *pb.FilterSnapshot
+	persisted []types.Persisted
+	compactC  chan uint64
+
 	// logger
 	logger    logger.Logger
 }
@@ -155,28 +206,135 @@ func newTransactionsFilterImpl(c types.Config) *transactionsFilterImpl {
 	pavingRecvC := make(chan *pb.OrderedLog, tp.DefaultChannelLen)
 	pavedC := make(chan types.PavedTxs)
 
-	finishedC := make(chan []string)
+	finishedC := make(chan types.Finished)
 
 	verifyingRecvC := make(chan *pb.OrderedLog, tp.DefaultChannelLen)
 	verifyC := make(chan string, tp.DefaultChannelLen)
 
 	graphingRecvC := make(chan *pb.OrderedLog, tp.DefaultChannelLen)
 
+	pavingWhitelistC := make(chan tp.ExcludeEvent, tp.DefaultChannelLen)
+	verifyingWhitelistC := make(chan []int, tp.DefaultChannelLen)
+	graphingBlacklistC := make(chan tp.ExcludeEvent, tp.DefaultChannelLen)
+	batchC := make(chan uint64, tp.DefaultChannelLen)
+
+	pavingEpochC := make(chan tp.EpochEvent, tp.DefaultChannelLen)
+	verifyingEpochC := make(chan tp.EpochEvent, tp.DefaultChannelLen)
+	graphingEpochC := make(chan tp.EpochEvent, tp.DefaultChannelLen)
+
+	graphingStableC := make(chan tp.ExecuteEvent, tp.DefaultChannelLen)
+	graphingTransferC := make(chan []string, tp.DefaultChannelLen)
+	pavingGCC := make(chan types.Collected, tp.DefaultChannelLen)
+	verifyingGCC := make(chan types.Collected, tp.DefaultChannelLen)
+
+	pavingMetricsC := make(chan chan types.Metrics)
+	verifyingMetricsC := make(chan chan types.Metrics)
+	graphingMetricsC := make(chan chan types.Metrics)
+
+	timerC := make(chan types.TimerEvent, tp.DefaultChannelLen)
+	pavingTimeoutC := make(chan uint64, tp.DefaultChannelLen)
+	verifyingTimeoutC := make(chan uint64, tp.DefaultChannelLen)
+	graphingTimeoutC := make(chan uint64, tp.DefaultChannelLen)
+
+	var snapshotC chan *pb.FilterSnapshot
+	if c.Store != nil {
+		snapshotC = make(chan *pb.FilterSnapshot, tp.DefaultChannelLen)
+	}
+
 	closeC := make(chan bool)
 
+	pavingConfig := types.PavingConfig{
+		N:          n,
+		F:          f,
+		Whitelist:  c.Replicas,
+		VPRecorder: vpRecorderPaving,
+		RecvC:      pavingRecvC,
+		PavedC:     pavedC,
+		FinishedC:  finishedC,
+		WhitelistC: pavingWhitelistC,
+		TimerC:     timerC,
+		TimeoutC:   pavingTimeoutC,
+		BAC:        c.BA,
+		GCC:        pavingGCC,
+		EpochC:     pavingEpochC,
+		Container:  c.Container,
+		MetricsC:   pavingMetricsC,
+		Close:      closeC,
+		Logger:     c.Logger,
+	}
+	verifyingConfig := types.VerifyingConfig{
+		N:          n,
+		F:          f,
+		Whitelist:  c.Replicas,
+		RecvC:      verifyingRecvC,
+		VerifyC:    verifyC,
+		WhitelistC: verifyingWhitelistC,
+		BatchC:     batchC,
+		TimerC:     timerC,
+		TimeoutC:   verifyingTimeoutC,
+		BAC:        c.BA,
+		GCC:        verifyingGCC,
+		EpochC:     verifyingEpochC,
+		MetricsC:   verifyingMetricsC,
+		Close:      closeC,
+		Logger:     c.Logger,
+	}
+	graphingConfig := types.GraphingConfig{
+		VPRecorder:   vpRecorderGraphing,
+		RecvC:        graphingRecvC,
+		VerifyC:      verifyC,
+		PavedC:       pavedC,
+		FinishedC:    finishedC,
+		GraphC:       c.Graph,
+		ResolveC:     c.Resolve,
+		TimerC:       timerC,
+		TimeoutC:     graphingTimeoutC,
+		BAC:          c.BA,
+		BlacklistC:   graphingBlacklistC,
+		BatchC:       batchC,
+		SnapshotC:    snapshotC,
+		StableC:      graphingStableC,
+		TransferC:    graphingTransferC,
+		PavingGCC:    pavingGCC,
+		VerifyingGCC: verifyingGCC,
+		EpochC:       graphingEpochC,
+		MetricsC:     graphingMetricsC,
+		Close:        closeC,
+		Logger:       c.Logger,
+	}
+
 	return &transactionsFilterImpl{
 		n:     n,
 		f:     f,
 		multi: multi,
 
-		pavingMgr:    newPavingMgr(n, f, vpRecorderPaving, pavingRecvC, pavedC, closeC, c.Logger, finishedC),
-		verifyingMgr: newGatheringMgr(n, f, c.Replicas, verifyingRecvC, verifyC, closeC, c.Logger),
-		graphingMgr:  newRelatingMgr(n, f, vpRecorderGraphing, graphingRecvC, verifyC, pavedC, closeC, c.Logger, finishedC),
+		pavingMgr:    newPavingMgr(pavingConfig),
+		verifyingMgr: newGatheringMgr(verifyingConfig),
+		graphingMgr:  newRelatingMgr(graphingConfig),
 
 		pavingRecvC:    pavingRecvC,
 		verifyingRecvC: verifyingRecvC,
 		graphingRecvC:  graphingRecvC,
 
+		pavingWhitelistC:    pavingWhitelistC,
+		verifyingWhitelistC: verifyingWhitelistC,
+		graphingBlacklistC:  graphingBlacklistC,
+
+		pavingEpochC:    pavingEpochC,
+		verifyingEpochC: verifyingEpochC,
+		graphingEpochC:  graphingEpochC,
+
+		graphingStableC:   graphingStableC,
+		graphingTransferC: graphingTransferC,
+
+		pavingTimeoutC:    pavingTimeoutC,
+		verifyingTimeoutC: verifyingTimeoutC,
+		graphingTimeoutC:  graphingTimeoutC,
+
+		pavingMetricsC:    pavingMetricsC,
+		verifyingMetricsC: verifyingMetricsC,
+		graphingMetricsC:  graphingMetricsC,
+
 		amountSeq:  uint64(0),
 		txsGraph:   make(map[uint64]map[uint64]string),
 		vpRecorder: nil,
@@ -196,21 +354,39 @@ func newTransactionsFilterImpl(c types.Config) *transactionsFilterImpl {
 		graphSize:       types.DefaultGraphSize,
 		replicaOrder:    c.Order,
 		graphEngine:     c.Graph,
-		pavingTimer:     make(chan bool),
+		timerC:          timerC,
+		pavingTimer:     make(chan uint64),
 		pavingExit:      make(chan bool),
-		gatheringTimer:  make(chan bool),
+		gatheringTimer:  make(chan uint64),
 		gatheringExit:   make(chan bool),
-		appointingTimer: make(chan bool),
+		appointingTimer: make(chan uint64),
 		appointingExit:  make(chan bool),
-		close:           make(chan bool),
+		baC:             c.BA,
+		whitelistC:      c.Whitelist,
+		blacklistC:      c.Blacklist,
+		stableC:         c.Stable,
+		transferC:       c.Transfer,
+		epochC:          c.Epoch,
+		close:           closeC,
 
 		commC: make(chan *pb.OrderedLog),
 
+		id:        c.ID,
+		store:     c.Store,
+		recovery:  c.Snapshot,
+		snapshotC: snapshotC,
+		compactC:  c.Compact,
+
 		logger: c.Logger,
 	}
 }
 
 func (tf *transactionsFilterImpl) start() {
+	tf.restore(tf.recovery)
+	tf.recovery = nil
+	if tf.store != nil {
+		go tf.listenSnapshot()
+	}
 	go tf.listenTimerEvent()
 	go tf.pavingMgr.listener()
 	go tf.verifyingMgr.listener()
@@ -218,10 +394,8 @@ func (tf *transactionsFilterImpl) start() {
 }
 
 func (tf *transactionsFilterImpl) stop() {
+	// the armed timers exit with the close channel
 	close(tf.close)
-	tf.stopPavingTimer()
-	tf.stopGatheringTimer()
-	tf.stopAppointingTimer()
 }
 
 func (tf *transactionsFilterImpl) listenTimerEvent() {
@@ -235,20 +409,54 @@ func (tf *transactionsFilterImpl) listenTimerEvent() {
 			tf.pavingRecvC <- log
 			tf.graphingRecvC <- log
 
-		case <-tf.graphEngine:
-
-
-		case <-tf.pavingTimer:
+		case event := <-tf.whitelistC:
+			tf.logger.Infof("[FILTER] remove replica %d from whitelist in batch %d", event.ID, event.Seq)
+			tf.whitelist = removeReplica(tf.whitelist, event.ID)
+			tf.pavingWhitelistC <- event
+			// the whitelist is only used to select the candidates to suspect in verifying manager, which won't
+			// affect the content of batches, so that it's updated at once
+			tf.verifyingWhitelistC <- tf.whitelist
+
+		case event := <-tf.blacklistC:
+			tf.logger.Infof("[FILTER] blacklist replica %d in batch %d", event.ID, event.Seq)
+			tf.graphingBlacklistC <- event
+
+		case event := <-tf.stableC:
+			tf.logger.Debugf("[FILTER] executed batch %d has become stable", event.Seq)
+			tf.graphingStableC <- event
+
+		case event := <-tf.epochC:
+			tf.logger.Infof("[FILTER] switch to epoch %d after executed batch %d, replicas %v", event.Epoch, event.Seq, event.Replicas)
+			tf.whitelist = event.Replicas
+			tf.pavingEpochC <- copyEpoch(event)
+			tf.verifyingEpochC <- copyEpoch(event)
+			tf.graphingEpochC <- copyEpoch(event)
+
+		case event := <-tf.transferC:
+			tf.logger.Infof("[FILTER] state transfer up to executed batch %d", event.Seq)
+			var txHashes []string
+			for _, batch := range event.Batches {
+				txHashes = append(txHashes, batch.TxHashes...)
+			}
+			tf.graphingTransferC <- txHashes
+
+		case event := <-tf.timerC:
+			tf.processTimerEvent(event)
+
+		case seq := <-tf.pavingTimer:
+			tf.logger.Infof("[FILTER] paving timer expired, seq %d", seq)
 			tf.stopPavingTimer()
-			// TODO(wgr): trigger ba remove
+			tf.pavingTimeoutC <- seq
 
-		case <-tf.gatheringTimer:
+		case seq := <-tf.gatheringTimer:
+			tf.logger.Infof("[FILTER] gathering timer expired, seq %d", seq)
 			tf.stopGatheringTimer()
-			// TODO(wgr): trigger ba remove
+			tf.verifyingTimeoutC <- seq
 
-		case <-tf.appointingTimer:
+		case seq := <-tf.appointingTimer:
+			tf.logger.Infof("[FILTER] appointing timer expired, seq %d", seq)
 			tf.stopAppointingTimer()
-			// TODO(wgr): trigger ba remove
+			tf.graphingTimeoutC <- seq
 		}
 	}
 }
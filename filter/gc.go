@@ -0,0 +1,196 @@
+package filter
+
+import (
+	"sort"
+
+	"github.com/Grivn/libfalanx/filter/types"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// stable is used to record the transactions which have become stable, and the batches will be garbage
+// collected one by one once all their transactions have become stable. the batches up to the stable one are
+// collected anyway, as DAG manager has executed all their transactions with it or before it, except the ones
+// skipped as duplicated ones, which would never become stable again.
+func (g *graphingMgr) stable(event tp.ExecuteEvent) {
+	for _, txHash := range event.TxHashes {
+		seq, ok := g.finalized[txHash]
+		if !ok {
+			continue
+		}
+		delete(g.finalized, txHash)
+		g.remains[seq]--
+	}
+
+	stableSeq := g.stableSeq
+	for {
+		next := g.stableSeq + 1
+		remains, ok := g.remains[next]
+		if !ok || (remains > 0 && next > event.Seq) {
+			break
+		}
+		g.collect(next)
+	}
+
+	// the snapshot is taken once again with the collected states, and the stable batch recorded in it is
+	// used to compact the write-ahead log of local order
+	if g.stableSeq > stableSeq {
+		g.snapshot()
+	}
+}
+
+// collect is used to drop the states for the transactions in stable batch, their hash will be kept as
+// tombstones until they could be forgotten.
+func (g *graphingMgr) collect(seq uint64) {
+	collected := types.Collected{Seq: seq}
+	for _, txHash := range g.batches[seq] {
+		delete(g.finalized, txHash)
+		delete(g.verifiedTxs, txHash)
+		g.collected[txHash] = true
+		collected.Stable = append(collected.Stable, txHash)
+		if g.forget(txHash) {
+			collected.Forgotten = append(collected.Forgotten, txHash)
+			continue
+		}
+		g.tombstones[seq] = append(g.tombstones[seq], txHash)
+	}
+	delete(g.batches, seq)
+	delete(g.remains, seq)
+	g.stableSeq = seq
+	collected.Forgotten = append(collected.Forgotten, g.expire()...)
+
+	g.logger.Debugf("[GRAPH] collect stable batch %d, stable %d, forgotten %d", seq, len(collected.Stable), len(collected.Forgotten))
+	g.notify(collected)
+}
+
+// forget is used to drop the tombstone of a collected transaction, once all the replicas which haven't
+// been blacklisted have delivered their logs for it, and it isn't concerned by any pending pair.
+func (g *graphingMgr) forget(txHash string) bool {
+	if g.pendingTx[txHash] > 0 {
+		return false
+	}
+	for id := range g.vpRecorder {
+		if !g.blacklist[id] && !g.orderedBy[txHash][id] {
+			return false
+		}
+	}
+	delete(g.collected, txHash)
+	delete(g.executed, txHash)
+	delete(g.orderedBy, txHash)
+	return true
+}
+
+// expire is used to forget the tombstones which have been kept for DefaultTombstoneBatches stable batches,
+// so that the replicas which never deliver their logs, e.g. the crashed ones, cannot keep them forever. the
+// ones concerned by pending pairs are kept for another window. a log arriving after its tombstone has
+// expired would be treated as a new transaction, so the window should cover the lag of the slowest correct
+// replica.
+func (g *graphingMgr) expire() []string {
+	var expired []string
+	for seq, txHashes := range g.tombstones {
+		if seq+types.DefaultTombstoneBatches > g.stableSeq {
+			continue
+		}
+		delete(g.tombstones, seq)
+		for _, txHash := range txHashes {
+			if !g.collected[txHash] {
+				// it has been forgotten once all the replicas delivered their logs
+				continue
+			}
+			if g.pendingTx[txHash] > 0 {
+				g.tombstones[g.stableSeq] = append(g.tombstones[g.stableSeq], txHash)
+				continue
+			}
+			g.logger.Debugf("[GRAPH] tombstone %s expires in stable batch %d, missing logs %v", txHash, g.stableSeq, g.absent(txHash))
+			delete(g.collected, txHash)
+			delete(g.executed, txHash)
+			delete(g.orderedBy, txHash)
+			expired = append(expired, txHash)
+		}
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+// absent is used to list the replicas which haven't delivered their logs for the transaction
+func (g *graphingMgr) absent(txHash string) []uint64 {
+	var absent []uint64
+	for id := range g.vpRecorder {
+		if !g.orderedBy[txHash][id] {
+			absent = append(absent, id)
+		}
+	}
+	sort.Slice(absent, func(i, j int) bool { return absent[i] < absent[j] })
+	return absent
+}
+
+// forgetCollected is used to check all the tombstones once again, as the logs from the replicas which have
+// been blacklisted are no longer awaited.
+func (g *graphingMgr) forgetCollected() {
+	var forgotten []string
+	for txHash := range g.collected {
+		if g.forget(txHash) {
+			forgotten = append(forgotten, txHash)
+		}
+	}
+	if len(forgotten) == 0 {
+		return
+	}
+	sort.Strings(forgotten)
+	g.notify(types.Collected{Seq: g.stableSeq, Forgotten: forgotten})
+}
+
+func (g *graphingMgr) notify(collected types.Collected) {
+	g.pavingGC <- collected
+	g.verifyGC <- collected
+}
+
+// collect is used to forget the finished transactions, the logs for them will not arrive any more.
+func (p *pavingMgr) collect(collected types.Collected) {
+	// the logs are delivered to paving manager before graphing manager, so that the ones which have been
+	// used to forget the txs must have been buffered in recvC, and they should be processed at first
+	for i := len(p.recvC); i > 0; i-- {
+		p.add(<-p.recvC)
+	}
+	p.remove(collected.Transferred)
+	if collected.BatchSeq > p.batchSeq {
+		p.resume(collected.BatchSeq)
+	}
+	for _, txHash := range collected.Forgotten {
+		delete(p.finished, txHash)
+	}
+}
+
+// collect is used to drop the recorders for stable transactions, while the verified mark is kept until
+// the transactions have been forgotten, so that the late logs for them won't be verified once again.
+func (v *verifyingMgr) collect(collected types.Collected) {
+	// the logs which have been used to forget the txs should be processed at first, see pavingMgr.collect
+	for i := len(v.recvC); i > 0; i-- {
+		v.add(<-v.recvC)
+	}
+	for _, txHash := range collected.Stable {
+		delete(v.txRecorder, txHash)
+	}
+	// the transactions executed by state transfer are treated as verified ones, so that the pending entries
+	// for them will be skipped by scanner
+	for _, txHash := range collected.Transferred {
+		v.verifiedTxs[txHash] = true
+		delete(v.txRecorder, txHash)
+	}
+	if len(collected.Forgotten) == 0 {
+		return
+	}
+	forgotten := make(map[string]bool)
+	for _, txHash := range collected.Forgotten {
+		delete(v.verifiedTxs, txHash)
+		forgotten[txHash] = true
+	}
+
+	// the duplicated pending entries for a forgotten tx should be dropped too, as its recorder has gone
+	var pendingTxs []string
+	for _, txHash := range v.pendingTxs {
+		if !forgotten[txHash] {
+			pendingTxs = append(pendingTxs, txHash)
+		}
+	}
+	v.pendingTxs = pendingTxs
+}
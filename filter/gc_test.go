@@ -0,0 +1,61 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/Grivn/libfalanx/filter/types"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// TestTombstoneExpire finalizes tx a which is never ordered by replica 4, its tombstone should be kept until
+// DefaultTombstoneBatches stable batches later, while tx b concerned by a pending pair should be kept longer
+func TestTombstoneExpire(t *testing.T) {
+	g := newTestFilter(nil, nil).graphingMgr
+	for _, txHash := range []string{"a", "b"} {
+		g.batches[1] = append(g.batches[1], txHash)
+		g.finalized[txHash] = 1
+		g.remains[1]++
+		g.executed[txHash] = true
+		g.orderedBy[txHash] = map[uint64]bool{1: true, 2: true, 3: true}
+	}
+	g.pendingTx["b"] = 1
+
+	g.stable(tp.ExecuteEvent{Seq: 1, TxHashes: []string{"a", "b"}})
+	if !g.collected["a"] || !g.collected["b"] {
+		t.Fatalf("the tombstones are forgotten without the logs from replica 4")
+	}
+
+	for seq := uint64(2); seq <= 1+types.DefaultTombstoneBatches; seq++ {
+		if !g.collected["a"] {
+			t.Fatalf("the tombstone of a expires in stable batch %d", g.stableSeq)
+		}
+		g.remains[seq] = 0
+		g.stable(tp.ExecuteEvent{Seq: seq})
+	}
+	if g.collected["a"] || g.executed["a"] || g.orderedBy["a"] != nil {
+		t.Fatalf("the tombstone of a doesn't expire in stable batch %d", g.stableSeq)
+	}
+	if !g.collected["b"] || !g.executed["b"] {
+		t.Fatalf("the tombstone of b expires with pending pairs")
+	}
+}
+
+// TestStableDuplicated finalizes tx a in batch 2 once again, which is skipped by DAG manager and never becomes
+// stable, batch 2 should be collected once it has been covered by the stable batch
+func TestStableDuplicated(t *testing.T) {
+	g := newTestFilter(nil, nil).graphingMgr
+	for seq, txHashes := range map[uint64][]string{1: {"b"}, 2: {"a", "c"}} {
+		for _, txHash := range txHashes {
+			g.batches[seq] = append(g.batches[seq], txHash)
+			g.finalized[txHash] = seq
+			g.remains[seq]++
+			g.executed[txHash] = true
+		}
+	}
+
+	g.stable(tp.ExecuteEvent{Seq: 1, TxHashes: []string{"b"}})
+	g.stable(tp.ExecuteEvent{Seq: 2, TxHashes: []string{"c"}})
+	if g.stableSeq != 2 || len(g.batches) != 0 || len(g.finalized) != 0 {
+		t.Fatalf("stable batch %d, batches %v, finalized %v", g.stableSeq, g.batches, g.finalized)
+	}
+}
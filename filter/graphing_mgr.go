@@ -5,13 +5,11 @@ import (
 	"github.com/Grivn/libfalanx/filter/utils"
 	"github.com/Grivn/libfalanx/logger"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
-	"math"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+	"sort"
 )
 
 type graphingMgr struct {
-	n int
-	f int
-
 	verifiedTxs map[string]bool
 
 	vpRecorder map[uint64]utils.TxList
@@ -22,35 +20,145 @@ type graphingMgr struct {
 
 	preferSeq uint64
 
+	// paved is used to keep the paved txs for preferSeq which contain unverified txs, and we will try to
+	// relate them once again when the txs have been verified
+	paved *types.PavedTxs
+
 	recvC   chan *pb.OrderedLog
 	verifyC chan string
 	pavedC  chan types.PavedTxs
-	finishC chan []string
+	finishC chan types.Finished
 	close   chan bool
 
+	// graphC is used to deliver the finalized relation graph of every batch to graph engine
+	// resolveC is used to deliver the determined relation of pending pairs to DAG manager
+	graphC   chan tp.GraphEvent
+	resolveC chan tp.Edge
+
+	// timerC is used to arm or disarm the appointing timer for current batch
+	// timeoutC is used to receive the expired batch seq from filter
+	// baC is used to report the replicas which have not ordered the waiting txs on timeout
+	timerC     chan types.TimerEvent
+	timeoutC   chan uint64
+	baC        chan tp.LocalBAEvent
+	appointing bool
+
+	// blacklist
+	// the replicas which have been suspected by f+1 replicas, their logs won't be used to determine the
+	// relations between transactions from the batch agreed by local BA until DefaultBlacklistBatches later.
+	// blacklist: the replicas blacklisted in current batch
+	// readmit:   the batch to readmit every blacklisted replica
+	// held:      the exclusions for the batches which haven't been reached
+	// batchC:    channel used to post the batch to work on to verifying manager
+	blacklistC chan tp.ExcludeEvent
+	blacklist  map[uint64]bool
+	readmit    map[uint64]uint64
+	held       []tp.ExcludeEvent
+	batchC     chan uint64
+
+	// epoch
+	// epochC: channel used to receive the replicas of new epoch
+	// early:  the logs from the replicas which will be added in the new epoch
+	epochC chan tp.EpochEvent
+	early  earlyLogs
+
 	waiting  []string
 	finished []string
 	executed map[string]bool
 	graphing bool
 
+	// pending
+	// the relations between the finalized transactions and the ones outside of finalized batches, which
+	// cannot be determined at the moment of finalization. the transactions in pending pairs will be kept
+	// in vpRecorder until all their pending relations have been determined.
+	// pendingTx: the amount of pending pairs for particular transaction
+	pending   map[types.RelationId]*types.RelationCert
+	pendingTx map[string]int
+
+	// snapshot
+	// progress:  the latest log received from every replica, which indicates the sequence number to resume
+	//            ordering the logs after restart
+	// snapshotC: channel used to deliver the snapshot taken once a batch has been finalized, the snapshot
+	//            is disabled if it's nil
+	progress  map[uint64]*pb.OrderedLog
+	snapshotC chan *pb.FilterSnapshot
+
+	// garbage collection
+	// the states for the transactions in stable batches will be dropped. however, the hash of a finalized
+	// transaction will be kept in executed as a tombstone until all the replicas which haven't been
+	// blacklisted have delivered their logs for it, or the late logs would be paved as new transactions. the
+	// tombstones expire DefaultTombstoneBatches stable batches later, so that they are bounded even though
+	// some replicas never deliver their logs.
+	// batches:    the finalized transactions of every batch which hasn't become stable
+	// finalized:  the batch of every finalized transaction which hasn't become stable
+	// remains:    the amount of transactions which haven't become stable for every batch
+	// stableSeq:  the latest stable batch, all the batches before it have become stable
+	// orderedBy:  the replicas which have delivered their logs for particular transaction
+	// collected:  the tombstones of the transactions in stable batches
+	// tombstones: the tombstones which haven't been forgotten, grouped by the stable batch collecting them
+	// stableC:    channel used to receive the transactions which have become stable
+	// transferC:  channel used to receive the transactions which have been executed by state transfer
+	// pavingGC:   channel used to notify paving manager of the collected transactions
+	// verifyGC:   channel used to notify verifying manager of the collected transactions
+	batches    map[uint64][]string
+	finalized  map[string]uint64
+	remains    map[uint64]int
+	stableSeq  uint64
+	orderedBy  map[string]map[uint64]bool
+	collected  map[string]bool
+	tombstones map[uint64][]string
+	stableC    chan tp.ExecuteEvent
+	transferC  chan []string
+	pavingGC   chan types.Collected
+	verifyGC   chan types.Collected
+
+	// metricsC is used to receive the queries for the amount of states kept by graphing manager
+	metricsC chan chan types.Metrics
+
 	logger logger.Logger
 }
 
-func newRelatingMgr(n, f int, vpRecorder map[uint64]utils.TxList, recvC chan *pb.OrderedLog, verifyC chan string, pavedC chan types.PavedTxs, close chan bool, logger logger.Logger, delC chan []string) *graphingMgr {
+func newRelatingMgr(c types.GraphingConfig) *graphingMgr {
 	return &graphingMgr{
 		certStore:   make(map[types.RelationId]*types.RelationCert),
-		vpRecorder:  vpRecorder,
+		vpRecorder:  c.VPRecorder,
 		verifiedTxs: make(map[string]bool),
 		executed:    make(map[string]bool),
 		graphSize:   types.DefaultGraphSize,
-		recvC:       recvC,
-		finishC:     delC,
-		verifyC:     verifyC,
-		pavedC:      pavedC,
-		close:       close,
+		recvC:       c.RecvC,
+		finishC:     c.FinishedC,
+		verifyC:     c.VerifyC,
+		pavedC:      c.PavedC,
+		close:       c.Close,
+		graphC:      c.GraphC,
+		resolveC:    c.ResolveC,
+		timerC:      c.TimerC,
+		timeoutC:    c.TimeoutC,
+		baC:         c.BAC,
+		blacklistC:  c.BlacklistC,
+		blacklist:   make(map[uint64]bool),
+		readmit:     make(map[uint64]uint64),
+		batchC:      c.BatchC,
+		pending:     make(map[types.RelationId]*types.RelationCert),
+		pendingTx:   make(map[string]int),
+		progress:    make(map[uint64]*pb.OrderedLog),
+		snapshotC:   c.SnapshotC,
+		batches:     make(map[uint64][]string),
+		finalized:   make(map[string]uint64),
+		remains:     make(map[uint64]int),
+		orderedBy:   make(map[string]map[uint64]bool),
+		collected:   make(map[string]bool),
+		tombstones:  make(map[uint64][]string),
+		stableC:     c.StableC,
+		transferC:   c.TransferC,
+		pavingGC:    c.PavingGCC,
+		verifyGC:    c.VerifyingGCC,
+		metricsC:    c.MetricsC,
+		epochC:      c.EpochC,
+		early:       make(earlyLogs),
 		graphing:    false,
 		preferSeq:   1,
-		logger:      logger,
+		logger:      c.Logger,
 	}
 }
 
@@ -62,6 +170,7 @@ func (g *graphingMgr) listener() {
 
 		case log := <-g.recvC:
 			g.add(log)
+			g.resolvePending()
 			if !g.graphing {
 				continue
 			}
@@ -70,6 +179,10 @@ func (g *graphingMgr) listener() {
 
 		case txHash := <-g.verifyC:
 			g.verified(txHash)
+			if g.graphing || g.paved == nil {
+				continue
+			}
+			g.generateGraph(*g.paved)
 
 		case pavedTxs := <-g.pavedC:
 			if g.graphing {
@@ -81,6 +194,24 @@ func (g *graphingMgr) listener() {
 				continue
 			}
 			g.generateGraph(pavedTxs)
+
+		case seq := <-g.timeoutC:
+			g.timeout(seq)
+
+		case event := <-g.blacklistC:
+			g.exclude(event)
+
+		case event := <-g.stableC:
+			g.stable(event)
+
+		case txHashes := <-g.transferC:
+			g.transfer(txHashes)
+
+		case event := <-g.epochC:
+			g.reconfigure(event)
+
+		case replyC := <-g.metricsC:
+			replyC <- g.metrics()
 		}
 	}
 }
@@ -90,7 +221,24 @@ func (g *graphingMgr) add(log *pb.OrderedLog) {
 		panic("nil log!")
 	}
 
-	if g.executed[log.TxHash] {
+	if _, ok := g.vpRecorder[log.ReplicaId]; !ok {
+		g.early.hold(log)
+		return
+	}
+
+	if latest := g.progress[log.ReplicaId]; latest == nil || log.Sequence > latest.Sequence {
+		g.progress[log.ReplicaId] = log
+	}
+
+	if g.orderedBy[log.TxHash] == nil {
+		g.orderedBy[log.TxHash] = make(map[uint64]bool)
+	}
+	g.orderedBy[log.TxHash][log.ReplicaId] = true
+
+	if g.executed[log.TxHash] && g.pendingTx[log.TxHash] == 0 {
+		if g.collected[log.TxHash] && g.forget(log.TxHash) {
+			g.notify(types.Collected{Seq: g.stableSeq, Forgotten: []string{log.TxHash}})
+		}
 		return
 	}
 
@@ -114,10 +262,12 @@ func (g *graphingMgr) generateGraph(pavedTxs types.PavedTxs) {
 	for txHash := range pavedTxs.Txs {
 		if !g.verifiedTxs[txHash] {
 			g.logger.Debugf("[Unverified] reject paved tx %s", txHash)
+			g.paved = &pavedTxs
 			return
 		}
 		g.waiting = append(g.waiting, txHash)
 	}
+	g.paved = nil
 	g.finished = nil
 	g.graphing = true
 
@@ -129,53 +279,105 @@ func (g *graphingMgr) relateTxs() {
 		return
 	}
 
-	self := g.waiting[0]
+	// relate the waiting txs one by one until we cannot determine the relations for the first one
+	for len(g.waiting) > 0 {
+		self := g.waiting[0]
 
-	finished := true
-	for _, other := range g.waiting {
-		if self == other {
-			continue
-		}
-		switch g.check(self, other) {
-		case types.FormerPriority:
-			g.logger.Infof("%s ===> %s", self, other)
-			cert := g.getRelationCert(other, self)
-			if !cert.Finished {
-				cert.Finished = true
-				cert.Status = types.LatterPriority
+		finished := true
+		for _, other := range g.waiting {
+			if self == other {
+				continue
 			}
-		case types.LatterPriority:
-			g.logger.Infof("%s ===> %s", other, self)
-			cert := g.getRelationCert(other, self)
-			if !cert.Finished {
-				cert.Finished = true
-				cert.Status = types.FormerPriority
+			switch g.check(self, other) {
+			case types.FormerPriority:
+				g.logger.Infof("%s ===> %s", self, other)
+				cert := g.getRelationCert(other, self)
+				if !cert.Finished {
+					cert.Finished = true
+					cert.Status = types.LatterPriority
+				}
+			case types.LatterPriority:
+				g.logger.Infof("%s ===> %s", other, self)
+				cert := g.getRelationCert(other, self)
+				if !cert.Finished {
+					cert.Finished = true
+					cert.Status = types.FormerPriority
+				}
+			case types.NotEfficient:
+				g.logger.Infof("cannot compare %s and %s", self, other)
+				finished = false
 			}
-		case types.NotEfficient:
-			g.logger.Infof("cannot compare %s and %s", self, other)
-			finished = false
 		}
-	}
-	if finished {
+		if !finished {
+			g.startTimer()
+			return
+		}
 		g.waiting = g.waiting[1:]
 		g.finished = append(g.finished, self)
 	}
 
-	if len(g.waiting) == 0 {
-		g.generateRawGraph()
+	g.stopTimer()
+	g.generateRawGraph()
+}
+
+// timeout is used to report the replicas which have not ordered all the waiting txs of current batch,
+// the expired events for previous batches will be ignored.
+func (g *graphingMgr) timeout(seq uint64) {
+	if !g.appointing || !g.graphing || seq != g.preferSeq {
+		g.logger.Debugf("[GRAPH] ignore expired timer, seq %d, current %d", seq, g.preferSeq)
+		return
 	}
+	g.appointing = false
+
+	var missing []uint64
+	for id, vp := range g.vpRecorder {
+		if g.blacklist[id] {
+			continue
+		}
+		for _, txHash := range g.waiting {
+			if _, err := vp.GetSequence(txHash); err != nil {
+				missing = append(missing, id)
+				break
+			}
+		}
+	}
+	if len(missing) > 0 {
+		sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+		g.logger.Warningf("[GRAPH] timeout for batch %d, replicas %v have not ordered the waiting txs", seq, missing)
+		g.baC <- tp.LocalBAEvent{Seq: seq, TxHash: g.waiting[0], MissingReplicas: missing}
+	}
+
+	g.startTimer()
+}
+
+func (g *graphingMgr) startTimer() {
+	if g.appointing {
+		return
+	}
+	g.appointing = true
+	g.timerC <- types.TimerEvent{Type: types.AppointingTimer, Seq: g.preferSeq, Start: true}
+}
+
+func (g *graphingMgr) stopTimer() {
+	if !g.appointing {
+		return
+	}
+	g.appointing = false
+	g.timerC <- types.TimerEvent{Type: types.AppointingTimer, Seq: g.preferSeq, Start: false}
 }
 
 func (g *graphingMgr) check(former, latter string) types.BeforeCheck {
-	cert := g.getRelationCert(former, latter)
+	return g.checkCert(g.getRelationCert(former, latter), former, latter)
+}
 
+func (g *graphingMgr) checkCert(cert *types.RelationCert, former, latter string) types.BeforeCheck {
 	if cert.Finished {
 		// we have already finished the determination of the order between former and latter
 		return cert.Status
 	}
 
 	for id, vp := range g.vpRecorder {
-		if cert.Scanned[id] {
+		if cert.Scanned[id] || g.blacklist[id] {
 			continue
 		}
 		seqFormer, errFormer := vp.GetSequence(former)
@@ -195,21 +397,46 @@ func (g *graphingMgr) check(former, latter string) types.BeforeCheck {
 			cert.LatterPreferred++
 		}
 
-		if cert.FormerPreferred > g.moreThanHalf() {
-			// more than half replicas have decided the former one has a higher priority
-			cert.Status = types.FormerPriority
-			cert.Finished = true
+		if g.decide(cert, former, latter) {
 			break
 		}
-		if cert.LatterPreferred > g.moreThanHalf() {
-			// more than half replicas have decided the latter one has a higher priority
+	}
+	g.decide(cert, former, latter)
+	return cert.Status
+}
+
+// decide is used to finish the relation cert once more than half of the replicas concerned have preferred
+// the same transaction, or all of them have voted and they are split evenly
+func (g *graphingMgr) decide(cert *types.RelationCert, former, latter string) bool {
+	if cert.Finished {
+		return true
+	}
+
+	if cert.FormerPreferred >= g.moreThanHalf() {
+		// more than half replicas have decided the former one has a higher priority
+		cert.Status = types.FormerPriority
+		cert.Finished = true
+		return true
+	}
+	if cert.LatterPreferred >= g.moreThanHalf() {
+		// more than half replicas have decided the latter one has a higher priority
+		cert.Status = types.LatterPriority
+		cert.Finished = true
+		return true
+	}
+
+	if cert.FormerPreferred == cert.LatterPreferred && cert.FormerPreferred+cert.LatterPreferred >= g.counted() {
+		// all the replicas concerned have voted and they are split evenly, the smaller hash is preferred so that
+		// every replica will determine the same relation
+		if former < latter {
+			cert.Status = types.FormerPriority
+		} else {
 			cert.Status = types.LatterPriority
-			cert.Finished = true
-			break
 		}
+		cert.Finished = true
+		return true
 	}
-
-	return cert.Status
+	return false
 }
 
 func (g *graphingMgr) getRelationCert(former, latter string) *types.RelationCert {
@@ -220,15 +447,19 @@ func (g *graphingMgr) getRelationCert(former, latter string) *types.RelationCert
 		return value
 	}
 
-	cert := &types.RelationCert{
+	cert := newRelationCert()
+	g.certStore[idr] = cert
+	return cert
+}
+
+func newRelationCert() *types.RelationCert {
+	return &types.RelationCert{
 		Finished:        false,
 		Status:          types.NotEfficient,
 		Scanned:         make(map[uint64]bool),
 		FormerPreferred: 0,
 		LatterPreferred: 0,
 	}
-	g.certStore[idr] = cert
-	return cert
 }
 
 func (g *graphingMgr) generateRawGraph() {
@@ -236,58 +467,213 @@ func (g *graphingMgr) generateRawGraph() {
 		return
 	}
 
-	graph := make(map[string][]string)
 	g.logger.Infof("Trying to generate graph")
 
+	vertices := make([]string, len(g.finished))
+	copy(vertices, g.finished)
+	graph := tp.GraphEvent{
+		Seq:      g.preferSeq,
+		Vertices: vertices,
+	}
 	for idr, cert := range g.certStore {
-		if cert.Status == types.FormerPriority {
-			graph[idr.From] = append(graph[idr.From], idr.To)
+		if cert.Status != types.FormerPriority {
+			continue
+		}
+
+		// the relation might be determined by the mirror cert, so that the votes should be collected from it
+		votes := cert.FormerPreferred
+		mirror := g.getRelationCert(idr.To, idr.From)
+		if mirror.LatterPreferred > votes {
+			votes = mirror.LatterPreferred
 		}
+		graph.Edges = append(graph.Edges, tp.Edge{From: idr.From, To: idr.To, Votes: votes})
 	}
+	g.relateExternal(&graph)
 
 	g.graphing = false
 	g.printGraph(graph)
-	g.preferSeq++
+	g.advance()
 
-	g.finish()
+	g.finish(graph)
 }
 
-func (g *graphingMgr) printGraph(graph map[string][]string) {
-	for from := range graph {
-		toList := graph[from]
-		g.logger.Infof("%s out degree is %d", from, len(toList))
-		for _, to := range toList {
-			g.logger.Infof("    ===> %s", to)
+// relateExternal is used to find the transactions outside of the finalized batch which should be executed
+// before the finalized ones. the outsiders ordered before a finalized transaction by the counted replicas are
+// concerned, and an outsider is preferred iff more than half of the counted replicas have ordered it before
+// the finalized one. these replicas must contain one of the replicas which have delivered the finalized
+// transaction before finalization, as more than half of them are needed to relate it inside the batch, so
+// that every replica will find the same preferred outsiders no matter when the logs arrive.
+// the relations preferring the finalized transaction are implied by the order of finalization, and they are
+// dropped, as the outsiders concerned by them depend on the logs which have arrived.
+func (g *graphingMgr) relateExternal(graph *tp.GraphEvent) {
+	inBatch := make(map[string]bool)
+	for _, txHash := range g.finished {
+		inBatch[txHash] = true
+	}
+
+	for _, latter := range g.finished {
+		outsiders := make(map[string]bool)
+		for id, vp := range g.vpRecorder {
+			if g.blacklist[id] {
+				continue
+			}
+			for _, former := range vp.GetFormerHashes(latter) {
+				if inBatch[former] || g.executed[former] {
+					continue
+				}
+				outsiders[former] = true
+			}
+		}
+
+		var formers []string
+		for former := range outsiders {
+			formers = append(formers, former)
+		}
+		sort.Strings(formers)
+
+		for _, former := range formers {
+			cert := newRelationCert()
+			switch g.checkExternal(cert, former, latter) {
+			case types.FormerPriority:
+				graph.External = append(graph.External, tp.Edge{From: former, To: latter, Votes: cert.FormerPreferred})
+			case types.NotEfficient:
+				g.logger.Infof("[GRAPH] pending relation between %s and %s", former, latter)
+				g.pending[types.RelationId{From: former, To: latter}] = cert
+				g.pendingTx[former]++
+				g.pendingTx[latter]++
+				graph.Pending = append(graph.Pending, tp.Pair{Former: former, Latter: latter})
+			}
 		}
 	}
 }
 
-func (g *graphingMgr) finish() {
+// checkExternal is used to relate an outsider with a finalized transaction. every counted replica which has
+// delivered the finalized one votes at once, as the logs are delivered in order, so that the outsider has been
+// ordered after it or never by the replica if it's absent in front of the finalized one.
+func (g *graphingMgr) checkExternal(cert *types.RelationCert, former, latter string) types.BeforeCheck {
+	if cert.Finished {
+		return cert.Status
+	}
+
+	for id, vp := range g.vpRecorder {
+		if cert.Scanned[id] || g.blacklist[id] {
+			continue
+		}
+		seqLatter, err := vp.GetSequence(latter)
+		if err != nil {
+			// current replica hasn't delivered the finalized one
+			continue
+		}
+
+		cert.Scanned[id] = true
+		if seqFormer, err := vp.GetSequence(former); err == nil && seqFormer < seqLatter {
+			cert.FormerPreferred++
+		} else {
+			cert.LatterPreferred++
+		}
+		if g.decide(cert, former, latter) {
+			break
+		}
+	}
+	g.decide(cert, former, latter)
+	return cert.Status
+}
+
+// resolvePending is used to check the pending pairs with the newly received logs, and the determined
+// relations will be posted to DAG manager to release the pairs, the outsider has been preferred iff the
+// edge starts from it
+func (g *graphingMgr) resolvePending() {
+	for idr, cert := range g.pending {
+		var edge tp.Edge
+		switch g.checkExternal(cert, idr.From, idr.To) {
+		case types.FormerPriority:
+			edge = tp.Edge{From: idr.From, To: idr.To, Votes: cert.FormerPreferred}
+		case types.LatterPriority:
+			edge = tp.Edge{From: idr.To, To: idr.From, Votes: cert.LatterPreferred}
+		default:
+			continue
+		}
+		g.logger.Infof("[GRAPH] resolve pending relation %s ===> %s", edge.From, edge.To)
+
+		delete(g.pending, idr)
+		g.releasePending(idr.From)
+		g.releasePending(idr.To)
+		g.resolveC <- edge
+	}
+}
+
+func (g *graphingMgr) releasePending(txHash string) {
+	g.pendingTx[txHash]--
+	if g.pendingTx[txHash] > 0 {
+		return
+	}
+	delete(g.pendingTx, txHash)
+	if g.executed[txHash] {
+		g.removeLogs(txHash)
+	}
+	if g.collected[txHash] && g.forget(txHash) {
+		g.notify(types.Collected{Seq: g.stableSeq, Forgotten: []string{txHash}})
+	}
+}
+
+func (g *graphingMgr) removeLogs(txHash string) {
+	for _, vp := range g.vpRecorder {
+		vp.RemoveByHash(txHash)
+	}
+}
+
+func (g *graphingMgr) printGraph(graph tp.GraphEvent) {
+	for _, edge := range graph.Edges {
+		g.logger.Infof("%s ===> %s, votes %d", edge.From, edge.To, edge.Votes)
+	}
+}
+
+func (g *graphingMgr) finish(graph tp.GraphEvent) {
 	g.logger.Infof("============================ Call execute %d ============================", g.preferSeq-1)
+	seq := g.preferSeq - 1
 	for _, txHash := range g.finished {
-		g.logger.Infof("[EXEC] %s", txHash)
+		g.logger.Infof("[FINISH] %s", txHash)
+		if !g.executed[txHash] {
+			g.batches[seq] = append(g.batches[seq], txHash)
+			g.finalized[txHash] = seq
+			g.remains[seq]++
+		}
 		g.executed[txHash] = true
-		for _, vp := range g.vpRecorder {
-			vp.RemoveByHash(txHash)
+		if g.pendingTx[txHash] == 0 {
+			g.removeLogs(txHash)
 		}
 	}
 	g.certStore = make(map[types.RelationId]*types.RelationCert)
-	go g.inform()
+	g.postGraph(graph)
+	g.snapshot()
+	go g.inform(types.Finished{Seq: seq, TxHashes: g.finished})
 }
 
-func (g *graphingMgr) inform() {
-	g.logger.Infof("[GRAPH] post finished event")
-	g.finishC <- g.finished
+// postGraph is used to post the relation graph of finished batch to graph engine, the graphs are
+// posted one by one so that the batches could be linearized and executed in order
+func (g *graphingMgr) postGraph(graph tp.GraphEvent) {
+	g.graphC <- graph
 }
 
-func (g *graphingMgr) allReplicas() int {
-	return g.n
+func (g *graphingMgr) inform(finished types.Finished) {
+	g.logger.Infof("[GRAPH] post finished event")
+	g.finishC <- finished
 }
 
-func (g *graphingMgr) allQuorumReplicas() int {
-	return g.n- g.f
+// moreThanHalf returns the amount of votes which is more than half of the replicas concerned when relating
+// txs. the blacklisted replicas are excluded, as their logs are ignored, so that the relations could still
+// be determined by the rest of them
+func (g *graphingMgr) moreThanHalf() int {
+	return g.counted()/2 + 1
 }
 
-func (g *graphingMgr) moreThanHalf() int {
-	return int(math.Ceil(float64(g.n+1)/float64(2)))
+// counted returns the amount of replicas whose logs are concerned when relating txs
+func (g *graphingMgr) counted() int {
+	counted := 0
+	for id := range g.vpRecorder {
+		if !g.blacklist[id] {
+			counted++
+		}
+	}
+	return counted
 }
\ No newline at end of file
@@ -7,17 +7,45 @@ import (
 	"github.com/Grivn/libfalanx/filter/types"
 )
 
-func (tf *transactionsFilterImpl) startPavingTimer(exitCh chan bool) {
-	tf.paving = true
-	go func() {
-		timer := time.NewTimer(tf.delay)
-		select {
-		case <-timer.C:
-			tf.pavingTimer <- true
-		case <-exitCh:
-			return
+// processTimerEvent is used to arm or disarm the timers according to the requests from managers,
+// only one instance will be maintained for every type of timer, so that the timer for a new sequence
+// number will replace the old one.
+func (tf *transactionsFilterImpl) processTimerEvent(event types.TimerEvent) {
+	switch event.Type {
+	case types.PavingTimer:
+		if event.Start {
+			if tf.paving {
+				tf.stopPavingTimer()
+			}
+			tf.startPavingTimer(event.Seq)
+		} else if tf.paving && tf.pavingSeq == event.Seq {
+			tf.stopPavingTimer()
 		}
-	}()
+	case types.GatheringTimer:
+		if event.Start {
+			if tf.gathering {
+				tf.stopGatheringTimer()
+			}
+			tf.startGatheringTimer(event.Seq)
+		} else if tf.gathering && tf.gatheringSeq == event.Seq {
+			tf.stopGatheringTimer()
+		}
+	case types.AppointingTimer:
+		if event.Start {
+			if tf.appointing {
+				tf.stopAppointingTimer()
+			}
+			tf.startAppointingTimer(event.Seq)
+		} else if tf.appointing && tf.appointingSeq == event.Seq {
+			tf.stopAppointingTimer()
+		}
+	}
+}
+
+func (tf *transactionsFilterImpl) startPavingTimer(seq uint64) {
+	tf.paving = true
+	tf.pavingSeq = seq
+	tf.startTimer(seq, tf.pavingTimer, tf.pavingExit)
 }
 
 func (tf *transactionsFilterImpl) stopPavingTimer() {
@@ -26,17 +54,10 @@ func (tf *transactionsFilterImpl) stopPavingTimer() {
 	tf.paving = false
 }
 
-func (tf *transactionsFilterImpl) startGatheringTimer(exitCh chan bool) {
+func (tf *transactionsFilterImpl) startGatheringTimer(seq uint64) {
 	tf.gathering = true
-	go func() {
-		timer := time.NewTimer(tf.delay)
-		select {
-		case <-timer.C:
-			tf.gatheringTimer <- true
-		case <-exitCh:
-			return
-		}
-	}()
+	tf.gatheringSeq = seq
+	tf.startTimer(seq, tf.gatheringTimer, tf.gatheringExit)
 }
 
 func (tf *transactionsFilterImpl) stopGatheringTimer() {
@@ -45,25 +66,37 @@ func (tf *transactionsFilterImpl) stopGatheringTimer() {
 	tf.gathering = false
 }
 
-func (tf *transactionsFilterImpl) startAppointingTimer(exitCh chan bool) {
+func (tf *transactionsFilterImpl) startAppointingTimer(seq uint64) {
 	tf.appointing = true
+	tf.appointingSeq = seq
+	tf.startTimer(seq, tf.appointingTimer, tf.appointingExit)
+}
+
+func (tf *transactionsFilterImpl) stopAppointingTimer() {
+	close(tf.appointingExit)
+	tf.appointingExit = make(chan bool)
+	tf.appointing = false
+}
+
+func (tf *transactionsFilterImpl) startTimer(seq uint64, timeoutC chan uint64, exitCh chan bool) {
 	go func() {
 		timer := time.NewTimer(tf.delay)
+		defer timer.Stop()
 		select {
 		case <-timer.C:
-			tf.appointingTimer <- true
+			select {
+			case timeoutC <- seq:
+			case <-exitCh:
+			case <-tf.close:
+			}
 		case <-exitCh:
 			return
+		case <-tf.close:
+			return
 		}
 	}()
 }
 
-func (tf *transactionsFilterImpl) stopAppointingTimer() {
-	close(tf.appointingExit)
-	tf.appointingExit = make(chan bool)
-	tf.appointing = false
-}
-
 func (tf *transactionsFilterImpl) getRelationCert(former, latter string) *types.RelationCert {
 	idr := types.RelationId{From: former, To: latter}
 	value, ok := tf.certStore[idr]
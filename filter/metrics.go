@@ -0,0 +1,51 @@
+package filter
+
+import "github.com/Grivn/libfalanx/filter/types"
+
+// metrics is used to query the amount of states kept by every manager, the queries are answered by their
+// listeners so that they won't race with the processing of logs. it returns the empty metrics once the
+// filter has been stopped.
+func (tf *transactionsFilterImpl) metrics() types.Metrics {
+	metrics := tf.query(tf.graphingMetricsC)
+	paving := tf.query(tf.pavingMetricsC)
+	verifying := tf.query(tf.verifyingMetricsC)
+	metrics.Finished = paving.Finished
+	metrics.Recorders = verifying.Recorders
+	metrics.Verified = verifying.Verified
+	return metrics
+}
+
+func (tf *transactionsFilterImpl) query(metricsC chan chan types.Metrics) types.Metrics {
+	replyC := make(chan types.Metrics, 1)
+	select {
+	case metricsC <- replyC:
+	case <-tf.close:
+		return types.Metrics{}
+	}
+	select {
+	case metrics := <-replyC:
+		return metrics
+	case <-tf.close:
+		return types.Metrics{}
+	}
+}
+
+func (g *graphingMgr) metrics() types.Metrics {
+	metrics := types.Metrics{
+		Tombstones: len(g.executed),
+		OrderedBy:  len(g.orderedBy),
+		Pending:    len(g.pending),
+	}
+	for _, vp := range g.vpRecorder {
+		metrics.Logs += vp.Len()
+	}
+	return metrics
+}
+
+func (p *pavingMgr) metrics() types.Metrics {
+	return types.Metrics{Finished: len(p.finished)}
+}
+
+func (v *verifyingMgr) metrics() types.Metrics {
+	return types.Metrics{Recorders: len(v.txRecorder), Verified: len(v.verifiedTxs)}
+}
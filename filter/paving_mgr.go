@@ -1,10 +1,13 @@
 package filter
 
 import (
+	"github.com/Grivn/libfalanx/api"
 	"github.com/Grivn/libfalanx/filter/types"
 	"github.com/Grivn/libfalanx/filter/utils"
 	"github.com/Grivn/libfalanx/logger"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+	"sort"
 )
 
 type pavingMgr struct {
@@ -33,35 +36,74 @@ type pavingMgr struct {
 	//txsGraph   map[uint64]map[uint64]string
 	vpRecorder map[uint64]utils.TxList
 
-	pavedRecorder map[uint64]map[string]bool
+	// finished is used to record the txs which have been finalized, the logs for them arriving later should
+	// be ignored, or they would be paved into another batch once again. the txs will be forgotten once all
+	// the replicas have delivered their logs for them, which is notified by graphing manager with gcC
+	finished map[string]bool
+	gcC      chan types.Collected
 
-	recvC chan *pb.OrderedLog
-	commC chan types.PavedTxs
-	delC  chan []string
-	close chan bool
+	recvC      chan *pb.OrderedLog
+	commC      chan types.PavedTxs
+	delC       chan types.Finished
+	close      chan bool
+
+	// whitelistC is used to receive the replicas removed from whitelist by local BA
+	// removals is used to hold the removals for the batches which haven't been reached
+	whitelistC chan tp.ExcludeEvent
+	removals   []tp.ExcludeEvent
+
+	// epochC is used to receive the replicas of new epoch
+	// early is used to hold the logs from the replicas which will be added in the new epoch
+	epochC chan tp.EpochEvent
+	early  earlyLogs
+
+	// timerC is used to arm or disarm the paving timer for current batch
+	// timeoutC is used to receive the expired batch seq from filter
+	// baC is used to report the replicas which have not sent logs on timeout
+	timerC   chan types.TimerEvent
+	timeoutC chan uint64
+	baC      chan tp.LocalBAEvent
+	timing   bool
 
 	batchSeq uint64
 
 	maxLen int
 
+	// container is used to pin the paved transactions, so that their payloads won't be evicted
+	container api.TxsContainer
+
+	// metricsC is used to receive the queries for the amount of states kept by paving manager
+	metricsC chan chan types.Metrics
+
 	logger logger.Logger
 }
 
-func newPavingMgr(n, f int, vpRecorder map[uint64]utils.TxList, recvC chan *pb.OrderedLog, commC chan types.PavedTxs, close chan bool, logger logger.Logger, delC chan []string) *pavingMgr {
+func newPavingMgr(c types.PavingConfig) *pavingMgr {
 	return &pavingMgr{
-		n:           n,
-		f:           f,
-		round:       0,
+		n:         c.N,
+		f:         c.F,
+		whitelist: c.Whitelist,
+		round:     0,
 		//txsGraph:    make(map[uint64]map[uint64]string),
-		pavedTxs:    make(map[string]bool),
-		batchSeq:    1,
-		vpRecorder:  vpRecorder,
-		recvC:       recvC,
-		delC:        delC,
-		commC:       commC,
-		close:       close,
-		maxLen:      types.DefaultGraphSize,
-		logger:      logger,
+		pavedTxs:   make(map[string]bool),
+		finished:   make(map[string]bool),
+		gcC:        c.GCC,
+		batchSeq:   1,
+		vpRecorder: c.VPRecorder,
+		recvC:      c.RecvC,
+		delC:       c.FinishedC,
+		whitelistC: c.WhitelistC,
+		epochC:     c.EpochC,
+		early:      make(earlyLogs),
+		timerC:     c.TimerC,
+		timeoutC:   c.TimeoutC,
+		baC:        c.BAC,
+		commC:      c.PavedC,
+		close:      c.Close,
+		maxLen:     types.DefaultGraphSize,
+		container:  c.Container,
+		metricsC:   c.MetricsC,
+		logger:     c.Logger,
 	}
 }
 
@@ -75,9 +117,27 @@ func (p *pavingMgr) listener() {
 			p.add(log)
 			p.scanner()
 
-		case finishedTxs := <-p.delC:
-			p.finish(finishedTxs)
+		case finished := <-p.delC:
+			p.finish(finished)
+			p.scanner()
+
+		case event := <-p.whitelistC:
+			p.exclude(event)
+			p.scanner()
+
+		case event := <-p.epochC:
+			p.reconfigure(event)
+			p.scanner()
+
+		case seq := <-p.timeoutC:
+			p.timeout(seq)
+
+		case collected := <-p.gcC:
+			p.collect(collected)
 			p.scanner()
+
+		case replyC := <-p.metricsC:
+			replyC <- p.metrics()
 		}
 	}
 }
@@ -87,18 +147,54 @@ func (p *pavingMgr) add(log *pb.OrderedLog) {
 		panic("nil log!")
 	}
 
+	if p.finished[log.TxHash] {
+		return
+	}
+
+	if _, ok := p.vpRecorder[log.ReplicaId]; !ok {
+		p.early.hold(log)
+		return
+	}
+
 	// update vpRecorder
 	p.vpRecorder[log.ReplicaId].Add(log)
 }
 
-func (p *pavingMgr) finish(finishedTxs []string) {
+func (p *pavingMgr) finish(finished types.Finished) {
 	p.logger.Infof("[PAVE] received finished event, try to remove")
-	for _, txHash := range finishedTxs {
+	p.remove(finished.TxHashes)
+
+	// the finished event might arrive after the filter has been fast-forwarded by state transfer
+	if finished.Seq < p.batchSeq {
+		p.logger.Debugf("[PAVE] finished batch %d is behind current batch %d", finished.Seq, p.batchSeq)
+		return
+	}
+	p.resume(finished.Seq + 1)
+}
+
+// remove is used to mark the txs as finished, and the logs for them will not be paved any more
+func (p *pavingMgr) remove(txHashes []string) {
+	for _, txHash := range txHashes {
+		p.finished[txHash] = true
 		for _, vp := range p.vpRecorder {
 			vp.RemoveByHash(txHash)
 		}
 	}
-	p.batchSeq++
+}
+
+// resume is used to start paving the txs for particular batch from the first round
+func (p *pavingMgr) resume(batchSeq uint64) {
+	p.stopTimer()
+	p.batchSeq = batchSeq
+	p.round = 0
+	p.pavedTxs = make(map[string]bool)
+	p.applyRemovals()
+}
+
+// update is used to pave the txs with the logs from replicas in new whitelist
+func (p *pavingMgr) update(whitelist []int) {
+	p.logger.Infof("[PAVE] update whitelist %v", whitelist)
+	p.whitelist = whitelist
 	p.round = 0
 	p.pavedTxs = make(map[string]bool)
 }
@@ -115,15 +211,68 @@ func (p *pavingMgr) scanner() {
 				p.round = round
 			}
 			p.logger.Infof("[PAVE] not efficient txs, len %d, round %d", len(p.pavedTxs), p.round)
+			p.startTimer()
 			return
 		}
 		p.pavedTxs[log.TxHash] = true
 		round++
 	}
+	p.stopTimer()
 	p.communicate()
 }
 
+// timeout is used to report the replicas in whitelist which have not sent the logs needed by current
+// round, the expired events for previous batches will be ignored.
+func (p *pavingMgr) timeout(seq uint64) {
+	if !p.timing || seq != p.batchSeq {
+		p.logger.Debugf("[PAVE] ignore expired timer, seq %d, current %d", seq, p.batchSeq)
+		return
+	}
+	p.timing = false
+
+	needed := int(p.roundSEQ(p.round))
+	var missing []uint64
+	for _, i := range p.whitelist {
+		id := uint64(i)
+		if p.vpRecorder[id].Len() < needed {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+
+	p.logger.Warningf("[PAVE] timeout for batch %d, replicas %v have not sent log %d", seq, missing, needed)
+	p.baC <- tp.LocalBAEvent{Seq: seq, MissingReplicas: missing}
+
+	// re-arm the timer, so that we could try to remove the silent replicas once again if the previous
+	// local agreement has not taken effect
+	p.startTimer()
+}
+
+func (p *pavingMgr) startTimer() {
+	if p.timing {
+		return
+	}
+	p.timing = true
+	p.timerC <- types.TimerEvent{Type: types.PavingTimer, Seq: p.batchSeq, Start: true}
+}
+
+func (p *pavingMgr) stopTimer() {
+	if !p.timing {
+		return
+	}
+	p.timing = false
+	p.timerC <- types.TimerEvent{Type: types.PavingTimer, Seq: p.batchSeq, Start: false}
+}
+
 func (p *pavingMgr) communicate() {
+	if p.container != nil {
+		for txHash := range p.pavedTxs {
+			p.container.Pin(txHash)
+		}
+	}
 	comm := types.PavedTxs{
 		Seq: p.batchSeq,
 		Txs: p.pavedTxs,
@@ -132,9 +281,9 @@ func (p *pavingMgr) communicate() {
 }
 
 func (p *pavingMgr) roundID(round uint64) uint64 {
-	return round%uint64(p.n) + 1
+	return uint64(p.whitelist[round%uint64(len(p.whitelist))])
 }
 
 func (p *pavingMgr) roundSEQ(round uint64) uint64 {
-	return round/uint64(p.n) + 1
+	return round/uint64(len(p.whitelist)) + 1
 }
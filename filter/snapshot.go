@@ -0,0 +1,179 @@
+package filter
+
+import (
+	"sort"
+
+	"github.com/Grivn/libfalanx/filter/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// snapshot is used to capture the states of filter once a batch has been finalized. the snapshot is taken
+// by graphing manager, as the recorders of paving and verifying manager could be re-constructed from the
+// logs kept by it:
+// 1) the logs in vpRecorder of paving manager are the ones in graphing manager except the finished txs
+// 2) the txRecorder of verifying manager only concerns the txs which haven't been verified
+func (g *graphingMgr) snapshot() {
+	if g.snapshotC == nil {
+		return
+	}
+
+	snapshot := &pb.FilterSnapshot{BatchSeq: g.preferSeq - 1, StableSeq: g.stableSeq}
+	for txHash := range g.executed {
+		snapshot.Executed = append(snapshot.Executed, txHash)
+	}
+	sort.Strings(snapshot.Executed)
+	for txHash := range g.verifiedTxs {
+		snapshot.Verified = append(snapshot.Verified, txHash)
+	}
+	sort.Strings(snapshot.Verified)
+	for id := range g.blacklist {
+		snapshot.Blacklist = append(snapshot.Blacklist, &pb.Blacklisted{ReplicaId: id, ReadmitSeq: g.readmit[id]})
+	}
+	sort.Slice(snapshot.Blacklist, func(i, j int) bool { return snapshot.Blacklist[i].ReplicaId < snapshot.Blacklist[j].ReplicaId })
+
+	var replicas []uint64
+	for id := range g.vpRecorder {
+		replicas = append(replicas, id)
+	}
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i] < replicas[j] })
+	for _, id := range replicas {
+		snapshot.Logs = append(snapshot.Logs, g.vpRecorder[id].GetLogs()...)
+		if latest, ok := g.progress[id]; ok {
+			snapshot.Progress = append(snapshot.Progress, latest)
+		}
+	}
+
+	for idr := range g.pending {
+		snapshot.Pending = append(snapshot.Pending, &pb.PendingPair{Former: idr.From, Latter: idr.To})
+	}
+	sort.Slice(snapshot.Pending, func(i, j int) bool {
+		if snapshot.Pending[i].Former != snapshot.Pending[j].Former {
+			return snapshot.Pending[i].Former < snapshot.Pending[j].Former
+		}
+		return snapshot.Pending[i].Latter < snapshot.Pending[j].Latter
+	})
+
+	g.snapshotC <- snapshot
+}
+
+// restore is used to resume graphing manager from the last finalized batch, the pending pairs will be
+// checked once again with the logs received after restart.
+func (g *graphingMgr) restore(snapshot *pb.FilterSnapshot) {
+	g.preferSeq = snapshot.BatchSeq + 1
+
+	// the batches finalized before restart are not tracked any more, the garbage collection is resumed from
+	// the next batch, and the finalized transactions are treated as the tombstones collected in the last one
+	g.stableSeq = snapshot.BatchSeq
+	for _, txHash := range snapshot.Executed {
+		g.executed[txHash] = true
+		g.collected[txHash] = true
+	}
+	if len(snapshot.Executed) > 0 {
+		g.tombstones[g.stableSeq] = append([]string(nil), snapshot.Executed...)
+	}
+	for _, txHash := range snapshot.Verified {
+		g.verifiedTxs[txHash] = true
+	}
+	for _, blacklisted := range snapshot.Blacklist {
+		g.blacklist[blacklisted.ReplicaId] = true
+		g.readmit[blacklisted.ReplicaId] = blacklisted.ReadmitSeq
+	}
+	for _, log := range snapshot.Progress {
+		g.progress[log.ReplicaId] = log
+	}
+	for _, pair := range snapshot.Pending {
+		g.pending[types.RelationId{From: pair.Former, To: pair.Latter}] = newRelationCert()
+		g.pendingTx[pair.Former]++
+		g.pendingTx[pair.Latter]++
+	}
+	for _, log := range snapshot.Logs {
+		g.vpRecorder[log.ReplicaId].Add(log)
+	}
+}
+
+// restore is used to resume paving manager at the batch following the last finalized one.
+func (p *pavingMgr) restore(snapshot *pb.FilterSnapshot) {
+	p.batchSeq = snapshot.BatchSeq + 1
+	for _, txHash := range snapshot.Executed {
+		p.finished[txHash] = true
+	}
+	for _, log := range snapshot.Logs {
+		p.add(log)
+	}
+}
+
+// restore is used to re-construct the txRecorder for the txs which haven't been verified.
+func (v *verifyingMgr) restore(snapshot *pb.FilterSnapshot) {
+	v.batchSeq = snapshot.BatchSeq + 1
+	for _, txHash := range snapshot.Executed {
+		v.verifiedTxs[txHash] = true
+	}
+	for _, txHash := range snapshot.Verified {
+		v.verifiedTxs[txHash] = true
+	}
+
+	// the logs are re-added in the order of sequence number, which is similar to the order they arrived
+	logs := make([]*pb.OrderedLog, len(snapshot.Logs))
+	copy(logs, snapshot.Logs)
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Sequence < logs[j].Sequence })
+	for _, log := range logs {
+		v.add(log)
+	}
+}
+
+// restore is used to resume the filter with the snapshot persisted before restart, it should be called
+// before the managers start.
+func (tf *transactionsFilterImpl) restore(snapshot *pb.FilterSnapshot) {
+	if snapshot == nil {
+		return
+	}
+
+	tf.logger.Infof("[FILTER] restore from snapshot, batch %d, logs %d, pending %d", snapshot.BatchSeq, len(snapshot.Logs), len(snapshot.Pending))
+	tf.graphingMgr.restore(snapshot)
+	tf.pavingMgr.restore(snapshot)
+	tf.verifyingMgr.restore(snapshot)
+}
+
+func (tf *transactionsFilterImpl) listenSnapshot() {
+	for {
+		select {
+		case <-tf.close:
+			return
+
+		case snapshot := <-tf.snapshotC:
+			if err := tf.store.Save(snapshot); err != nil {
+				tf.logger.Errorf("[FILTER] save snapshot of batch %d failed: %s", snapshot.BatchSeq, err)
+				continue
+			}
+			tf.logger.Debugf("[FILTER] saved snapshot of batch %d", snapshot.BatchSeq)
+			tf.compact(snapshot)
+		}
+	}
+}
+
+// compact is used to find the latest persisted snapshot which has become stable, the logs of current replica
+// covered by it won't be replayed after restart, so that they could be dropped from the write-ahead log
+func (tf *transactionsFilterImpl) compact(snapshot *pb.FilterSnapshot) {
+	if tf.compactC == nil {
+		return
+	}
+	for _, log := range snapshot.Progress {
+		if log.ReplicaId == tf.id {
+			tf.persisted = append(tf.persisted, types.Persisted{BatchSeq: snapshot.BatchSeq, Sequence: log.Sequence})
+			break
+		}
+	}
+
+	var seq uint64
+	for len(tf.persisted) > 0 && tf.persisted[0].BatchSeq <= snapshot.StableSeq {
+		seq = tf.persisted[0].Sequence
+		tf.persisted = tf.persisted[1:]
+	}
+	if seq == 0 {
+		return
+	}
+	select {
+	case tf.compactC <- seq:
+	case <-tf.close:
+	}
+}
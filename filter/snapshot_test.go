@@ -0,0 +1,132 @@
+package filter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/Grivn/libfalanx/filter/types"
+	"github.com/Grivn/libfalanx/filter/utils"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// testLogger drops the logs, the failures are reported by the tests themselves
+type testLogger struct{}
+
+func (testLogger) Debug(v ...interface{})                   {}
+func (testLogger) Debugf(format string, v ...interface{})   {}
+func (testLogger) Info(v ...interface{})                    {}
+func (testLogger) Infof(format string, v ...interface{})    {}
+func (testLogger) Warning(v ...interface{})                 {}
+func (testLogger) Warningf(format string, v ...interface{}) {}
+func (testLogger) Error(v ...interface{})                   {}
+func (testLogger) Errorf(format string, v ...interface{})   {}
+
+func newTestFilter(store utils.SnapshotStore, snapshot *pb.FilterSnapshot) *transactionsFilterImpl {
+	return newTransactionsFilterImpl(types.Config{
+		ID:       1,
+		Replicas: []int{1, 2, 3, 4},
+		Store:    store,
+		Snapshot: snapshot,
+		Logger:   testLogger{},
+	})
+}
+
+func newTestLog(id, seq uint64, txHash string) *pb.OrderedLog {
+	return &pb.OrderedLog{ReplicaId: id, Sequence: seq, TxHash: txHash, Timestamp: int64(seq)}
+}
+
+// TestSnapshotRoundTrip persists the snapshot taken by graphing manager and restores a new filter from it,
+// the managers should be resumed with the same states
+func TestSnapshotRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "falanx-snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := utils.NewSnapshotStore(filepath.Join(dir, "filter.snapshot"))
+
+	// replica 4 has been blacklisted, tx a has been finalized in batch 2, while its relation with tx c is pending
+	tf := newTestFilter(store, nil)
+	g := tf.graphingMgr
+	g.preferSeq = 3
+	g.stableSeq = 1
+	g.executed["a"] = true
+	g.verifiedTxs["a"] = true
+	g.verifiedTxs["b"] = true
+	g.blacklistReplica(4, 10)
+	g.pending[types.RelationId{From: "c", To: "a"}] = newRelationCert()
+	g.pendingTx["c"]++
+	g.pendingTx["a"]++
+	for _, log := range []*pb.OrderedLog{
+		newTestLog(1, 1, "a"), newTestLog(1, 2, "b"), newTestLog(1, 3, "c"),
+		newTestLog(2, 1, "c"), newTestLog(2, 2, "a"),
+		newTestLog(3, 1, "b"),
+	} {
+		g.add(log)
+	}
+	g.snapshot()
+
+	if err := store.Save(<-tf.snapshotC); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := newTestFilter(store, snapshot)
+	restored.restore(snapshot)
+
+	r := restored.graphingMgr
+	if r.preferSeq != 3 || r.stableSeq != 2 {
+		t.Fatalf("resume from batch %d, stable %d, expect 3 and 2", r.preferSeq, r.stableSeq)
+	}
+	if fmt.Sprint(keys(r.executed)) != "[a]" || fmt.Sprint(keys(r.verifiedTxs)) != "[a b]" {
+		t.Fatalf("restore executed %v, verified %v", keys(r.executed), keys(r.verifiedTxs))
+	}
+	if len(r.blacklist) != 1 || !r.blacklist[4] || r.readmit[4] != 10 {
+		t.Fatalf("restore blacklist %v until %v, expect replica 4 until batch 10", r.blacklist, r.readmit)
+	}
+	if len(r.pending) != 1 || r.pending[types.RelationId{From: "c", To: "a"}] == nil || r.pendingTx["a"] != 1 || r.pendingTx["c"] != 1 {
+		t.Fatalf("restore pending pairs %v", r.pending)
+	}
+	for id, progress := range map[uint64]uint64{1: 3, 2: 2, 3: 1} {
+		if r.progress[id] == nil || r.progress[id].Sequence != progress {
+			t.Fatalf("restore progress of replica %d %v, expect %d", id, r.progress[id], progress)
+		}
+	}
+	for id, vp := range g.vpRecorder {
+		if fmt.Sprint(vp.GetLogs()) != fmt.Sprint(r.vpRecorder[id].GetLogs()) {
+			t.Fatalf("restore logs of replica %d %v, expect %v", id, r.vpRecorder[id].GetLogs(), vp.GetLogs())
+		}
+	}
+	// the blacklisted replica is still excluded when relating txs
+	if r.counted() != 3 {
+		t.Fatalf("count %d replicas after restore, expect 3", r.counted())
+	}
+
+	// the finalized tx is not paved once again, and the verified ones are not verified once again
+	p := restored.pavingMgr
+	if p.batchSeq != 3 || !p.finished["a"] {
+		t.Fatalf("resume paving from batch %d, finished %v", p.batchSeq, p.finished)
+	}
+	if _, err := p.vpRecorder[2].GetSequence("a"); err == nil {
+		t.Fatalf("the log for finalized tx is paved once again")
+	}
+	v := restored.verifyingMgr
+	if !v.verifiedTxs["a"] || !v.verifiedTxs["b"] || v.verifiedTxs["c"] {
+		t.Fatalf("restore verified txs %v", v.verifiedTxs)
+	}
+}
+
+func keys(set map[string]bool) []string {
+	var list []string
+	for key := range set {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,52 @@
+package filter
+
+import (
+	"github.com/Grivn/libfalanx/filter/types"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// transfer is used to fast-forward the filter with the transactions executed by state transfer. the batch
+// in progress will be abandoned, as some of its transactions might have been executed, and the managers
+// will resume from the next batch. the transferred transactions are treated as collected ones, so that
+// their hash will be kept as tombstones to reject the late logs until they could be forgotten.
+func (g *graphingMgr) transfer(txHashes []string) {
+	g.stopTimer()
+	g.graphing = false
+	g.waiting = nil
+	g.finished = nil
+	g.paved = nil
+	g.certStore = make(map[types.RelationId]*types.RelationCert)
+
+	collected := types.Collected{Seq: g.stableSeq}
+	for _, txHash := range txHashes {
+		if g.executed[txHash] {
+			// the finalized transactions will be collected once their batch has become stable
+			continue
+		}
+		g.executed[txHash] = true
+		g.collected[txHash] = true
+		delete(g.verifiedTxs, txHash)
+		if g.pendingTx[txHash] == 0 {
+			g.removeLogs(txHash)
+		}
+		collected.Transferred = append(collected.Transferred, txHash)
+		if g.forget(txHash) {
+			collected.Forgotten = append(collected.Forgotten, txHash)
+			continue
+		}
+		g.tombstones[g.stableSeq] = append(g.tombstones[g.stableSeq], txHash)
+	}
+
+	// there isn't any transaction in the abandoned batch, so that it could be collected directly, and an empty
+	// graph is posted for it to keep the sequence numbers of batches executed by DAG manager contiguous
+	seq := g.preferSeq
+	g.remains[seq] = 0
+	g.advance()
+	collected.BatchSeq = g.preferSeq
+	g.postGraph(tp.GraphEvent{Seq: seq})
+
+	g.logger.Infof("[GRAPH] state transfer abandons batch %d, transferred %d, resume from batch %d", seq, len(collected.Transferred), g.preferSeq)
+	g.snapshot()
+	g.notify(collected)
+	g.stable(tp.ExecuteEvent{})
+}
@@ -1,21 +1,159 @@
 package types
 
 import (
+	"github.com/Grivn/libfalanx/api"
+	"github.com/Grivn/libfalanx/filter/utils"
 	"github.com/Grivn/libfalanx/logger"
 	"github.com/Grivn/libfalanx/zcommon"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
 )
 
+// Config is used to initiate the filter
+// Store:     the store to persist the snapshot taken once a batch has been finalized, it is disabled if it's nil
+// Snapshot:  the snapshot loaded from Store on restart, the filter will be resumed from it if it isn't nil
+// Compact:   post the sequence number of the latest log of current replica covered by a persisted snapshot of
+//            stable batch, so that the write-ahead log of local order could be compacted, it could be nil
+// Stable:    the executed batches which have become stable, the states for the transactions in them will
+//            be garbage collected, it could be nil if the garbage collection is disabled
+// Transfer:  the batches executed by state transfer, the filter will skip the transactions in them and
+//            resume from the next batch, it could be nil if the state transfer is disabled
+// Epoch:     the replicas of new epoch, which is posted once the batch completing the reconfiguration has
+//            been executed, it could be nil if the replica set is fixed
+// Container: the transactions paved into batches will be pinned in it, so that they won't be evicted before
+//            execution, it could be nil if the payloads are maintained by the application
 type Config struct {
+	ID       uint64
 	Replicas []int
 
-	Order  chan *pb.OrderedLog
-	Graph  chan interface{}
+	Order     chan *pb.OrderedLog
+	Graph     chan tp.GraphEvent
+	Resolve   chan tp.Edge
+	BA        chan tp.LocalBAEvent
+	Whitelist chan tp.ExcludeEvent
+	Blacklist chan tp.ExcludeEvent
+	Stable    chan tp.ExecuteEvent
+	Transfer  chan tp.TransferEvent
+	Epoch     chan tp.EpochEvent
+
+	Store    utils.SnapshotStore
+	Snapshot *pb.FilterSnapshot
+	Compact  chan uint64
+
+	Container api.TxsContainer
 
 	Logger logger.Logger
 	Tools  zcommon.Tools
 }
 
+// PavingConfig is used to initiate the paving manager
+// Whitelist:  the replicas which could be selected as candidates before any of them has been removed
+// VPRecorder: the logs from every replica, which are used to pave the transactions into batches
+// PavedC:     post the paved transactions of every batch to graphing manager
+// FinishedC:  receive the finalized batches from graphing manager
+// WhitelistC: receive the replicas removed from whitelist by local BA, and the batches to remove them
+// TimerC:     arm or disarm the paving timer for particular batch
+// TimeoutC:   receive the expired paving timers
+// BAC:        report the replicas which haven't sent logs in time to local BA
+// GCC:        receive the transactions collected by graphing manager
+// EpochC:     receive the replicas of new epoch
+// Container:  pin the paved transactions, it could be nil if the payloads are maintained by the application
+// MetricsC:   receive the queries for the amount of states kept by paving manager
+// Close:      closed once the filter has been stopped
+type PavingConfig struct {
+	N          int
+	F          int
+	Whitelist  []int
+	VPRecorder map[uint64]utils.TxList
+	RecvC      chan *pb.OrderedLog
+	PavedC     chan PavedTxs
+	FinishedC  chan Finished
+	WhitelistC chan tp.ExcludeEvent
+	TimerC     chan TimerEvent
+	TimeoutC   chan uint64
+	BAC        chan tp.LocalBAEvent
+	GCC        chan Collected
+	EpochC     chan tp.EpochEvent
+	Container  api.TxsContainer
+	MetricsC   chan chan Metrics
+	Close      chan bool
+	Logger     logger.Logger
+}
+
+// VerifyingConfig is used to initiate the verifying manager
+// Whitelist:  the replicas which could be selected as candidates before any of them has been removed
+// VerifyC:    post the verified transactions to graphing manager
+// WhitelistC: receive the whitelist shrunk by local BA
+// BatchC:     receive the batch graphing manager is working on, which the suspect messages are generated for
+// TimerC:     arm or disarm the gathering timer for particular sequence number
+// TimeoutC:   receive the expired gathering timers
+// BAC:        report the replicas which haven't sent logs in time to local BA
+// GCC:        receive the transactions collected by graphing manager
+// EpochC:     receive the replicas of new epoch
+// MetricsC:   receive the queries for the amount of states kept by verifying manager
+// Close:      closed once the filter has been stopped
+type VerifyingConfig struct {
+	N          int
+	F          int
+	Whitelist  []int
+	RecvC      chan *pb.OrderedLog
+	VerifyC    chan string
+	WhitelistC chan []int
+	BatchC     chan uint64
+	TimerC     chan TimerEvent
+	TimeoutC   chan uint64
+	BAC        chan tp.LocalBAEvent
+	GCC        chan Collected
+	EpochC     chan tp.EpochEvent
+	MetricsC   chan chan Metrics
+	Close      chan bool
+	Logger     logger.Logger
+}
+
+// GraphingConfig is used to initiate the graphing manager
+// VPRecorder:   the logs from every replica, which are used to relate the transactions
+// VerifyC:      receive the verified transactions from verifying manager
+// PavedC:       receive the paved transactions of every batch from paving manager
+// FinishedC:    post the finalized batches to paving manager
+// GraphC:       post the relation graph of every finalized batch to graph engine
+// ResolveC:     post the determined relations of pending pairs to DAG manager
+// TimerC:       arm or disarm the appointing timer for particular batch
+// TimeoutC:     receive the expired appointing timers
+// BAC:          report the replicas which haven't sent logs in time to local BA
+// BlacklistC:   receive the replicas blacklisted by local BA, and the batches to blacklist them
+// BatchC:       post the batch to work on to verifying manager, it could be nil
+// SnapshotC:    post the snapshot taken once a batch has been finalized, it could be nil
+// StableC:      receive the executed batches which have become stable
+// TransferC:    receive the transactions executed by state transfer
+// PavingGCC:    post the collected transactions to paving manager
+// VerifyingGCC: post the collected transactions to verifying manager
+// EpochC:       receive the replicas of new epoch
+// MetricsC:     receive the queries for the amount of states kept by graphing manager
+// Close:        closed once the filter has been stopped
+type GraphingConfig struct {
+	VPRecorder   map[uint64]utils.TxList
+	RecvC        chan *pb.OrderedLog
+	VerifyC      chan string
+	PavedC       chan PavedTxs
+	FinishedC    chan Finished
+	GraphC       chan tp.GraphEvent
+	ResolveC     chan tp.Edge
+	TimerC       chan TimerEvent
+	TimeoutC     chan uint64
+	BAC          chan tp.LocalBAEvent
+	BlacklistC   chan tp.ExcludeEvent
+	BatchC       chan uint64
+	SnapshotC    chan *pb.FilterSnapshot
+	StableC      chan tp.ExecuteEvent
+	TransferC    chan []string
+	PavingGCC    chan Collected
+	VerifyingGCC chan Collected
+	EpochC       chan tp.EpochEvent
+	MetricsC     chan chan Metrics
+	Close        chan bool
+	Logger       logger.Logger
+}
+
 type BeforeCheck uint64
 
 const (
@@ -39,9 +177,76 @@ type RelationCert struct {
 
 const (
 	DefaultGraphSize = 5
+
+	// DefaultTombstoneBatches is the amount of stable batches to keep the tombstone of a collected transaction
+	// for the replicas which haven't delivered their logs for it
+	DefaultTombstoneBatches = 64
 )
 
+// TimerType indicates the timers maintained by filter
+type TimerType int
+
+const (
+	PavingTimer TimerType = iota
+	GatheringTimer
+	AppointingTimer
+)
+
+// TimerEvent is used by managers to arm or disarm the timer for particular sequence number
+type TimerEvent struct {
+	Type  TimerType
+	Seq   uint64
+	Start bool
+}
+
 type PavedTxs struct {
 	Seq uint64
 	Txs map[string]bool
 }
+
+// Finished is used by graphing manager to notify paving manager of the finalized batch
+type Finished struct {
+	Seq      uint64
+	TxHashes []string
+}
+
+// Collected is used by graphing manager to notify the other managers of the garbage collected transactions
+// Seq:         the latest stable batch
+// Stable:      the transactions in the batch which has become stable, the states for them could be dropped
+// Forgotten:   the transactions whose logs have been delivered by all the replicas, or whose tombstones have
+//              expired, so that their hash is no longer used to reject the late logs
+// Transferred: the transactions executed by state transfer, they should be treated as finished ones
+// BatchSeq:    the batch to resume paving from after state transfer, it is 0 if there isn't any transfer
+type Collected struct {
+	Seq         uint64
+	Stable      []string
+	Forgotten   []string
+	Transferred []string
+	BatchSeq    uint64
+}
+
+// Metrics is the amount of states kept by filter, which are bounded by garbage collection
+// Logs:       the logs kept by graphing manager to relate the transactions
+// Tombstones: the finalized transactions kept to reject the late logs, including the ones not stable yet
+// OrderedBy:  the transactions whose ordering replicas are tracked
+// Pending:    the pending pairs which haven't been determined
+// Finished:   the finalized transactions kept by paving manager
+// Recorders:  the transactions whose ordering replicas are tracked by verifying manager
+// Verified:   the verified transactions kept by verifying manager
+type Metrics struct {
+	Logs       int
+	Tombstones int
+	OrderedBy  int
+	Pending    int
+	Finished   int
+	Recorders  int
+	Verified   int
+}
+
+// Persisted is the progress of current replica in a persisted snapshot
+// BatchSeq: the finalized batch which the snapshot has been taken for
+// Sequence: the sequence number of the latest log of current replica in the snapshot
+type Persisted struct {
+	BatchSeq uint64
+	Sequence uint64
+}
@@ -19,6 +19,8 @@ type TxList interface {
 	GetSequence(key string) (uint64, error)
 	GetByOrder(order int) *pb.OrderedLog
 	GetHashList(max int) []string
+	GetFormerHashes(hash string) []string
+	GetLogs() []*pb.OrderedLog
 
 	RemoveByHash(hash string)
 }
@@ -44,6 +46,14 @@ func (tli *txListImpl) Pop() *pb.OrderedLog {
 	return log
 }
 
+func (tli *txListImpl) GetFormerHashes(hash string) []string {
+	return tli.getFormerHashes(hash)
+}
+
+func (tli *txListImpl) GetLogs() []*pb.OrderedLog {
+	return tli.getLogs()
+}
+
 func (tli *txListImpl) GetFrontLog() *pb.OrderedLog {
 	return tli.frontLog()
 }
@@ -136,6 +146,37 @@ func (tli *txListImpl) getHashList(max int) []string {
 	return hashList
 }
 
+// getFormerHashes returns the hash of logs which are in front of the particular one
+func (tli *txListImpl) getFormerHashes(hash string) []string {
+	target := tli.get(hash)
+	if target == nil {
+		return nil
+	}
+
+	var hashList []string
+	for element := tli.list.Front(); element != target; element = element.Next() {
+		log, ok := element.Value.(*pb.OrderedLog)
+		if !ok {
+			panic("parsing error")
+		}
+		hashList = append(hashList, log.TxHash)
+	}
+	return hashList
+}
+
+// getLogs returns all the logs in list order
+func (tli *txListImpl) getLogs() []*pb.OrderedLog {
+	var logs []*pb.OrderedLog
+	for element := tli.list.Front(); element != nil; element = element.Next() {
+		log, ok := element.Value.(*pb.OrderedLog)
+		if !ok {
+			panic("parsing error")
+		}
+		logs = append(logs, log)
+	}
+	return logs
+}
+
 func (tli *txListImpl) frontLog() *pb.OrderedLog {
 	e := tli.list.Front()
 	log, ok := e.Value.(*pb.OrderedLog)
@@ -8,6 +8,7 @@ import (
 type TxRecorder interface {
 	Add(id uint64)
 	Update(whitelist []int)
+	Reconfigure(replicas []int, n int, f int)
 	PendingLen() int
 	OrderLen() int
 	GetMalicious() []uint64
@@ -26,6 +27,10 @@ func (tr *txRecorderImpl) Update(whitelist []int) {
 	tr.update(whitelist)
 }
 
+func (tr *txRecorderImpl) Reconfigure(replicas []int, n int, f int) {
+	tr.reconfigure(replicas, n, f)
+}
+
 func (tr *txRecorderImpl) PendingLen() int {
 	return tr.pendingLen()
 }
@@ -88,6 +93,9 @@ func (tr *txRecorderImpl) add(id uint64) {
 		return
 	}
 	tr.ordered[id] = true
+	if _, ok := tr.pending[id]; ok {
+		tr.pending[id] = false
+	}
 }
 
 func (tr *txRecorderImpl) update(whitelist []int) {
@@ -104,6 +112,23 @@ func (tr *txRecorderImpl) update(whitelist []int) {
 	tr.candidates = candidates
 }
 
+// reconfigure is used to switch to the replicas of new epoch, the orders from the removed replicas will be
+// dropped, and the candidates will be re-selected from the new replicas
+func (tr *txRecorderImpl) reconfigure(replicas []int, n int, f int) {
+	current := make(map[uint64]bool)
+	for _, id := range replicas {
+		current[uint64(id)] = true
+	}
+	for id := range tr.ordered {
+		if !current[id] {
+			delete(tr.ordered, id)
+		}
+	}
+	tr.n = n
+	tr.f = f
+	tr.update(replicas)
+}
+
 func (tr *txRecorderImpl) pendingLen() int {
 	return len(tr.pending)
 }
@@ -119,6 +144,7 @@ func (tr *txRecorderImpl) getMalicious() []uint64 {
 			malicious = append(malicious, id)
 		}
 	}
+	sort.Slice(malicious, func(i, j int) bool { return malicious[i] < malicious[j] })
 	return malicious
 }
 
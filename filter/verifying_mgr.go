@@ -1,9 +1,11 @@
 package filter
 
 import (
+	"github.com/Grivn/libfalanx/filter/types"
 	"github.com/Grivn/libfalanx/filter/utils"
 	"github.com/Grivn/libfalanx/logger"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
 )
 
 type verifyingMgr struct {
@@ -22,28 +24,69 @@ type verifyingMgr struct {
 	pendingTxs  []string
 	verifiedTxs map[string]bool
 
-	recvC chan *pb.OrderedLog
-	commC chan string
-	close chan bool
-
+	// gcC is used to receive the collected txs from graphing manager
+	gcC chan types.Collected
+
+	recvC      chan *pb.OrderedLog
+	commC      chan string
+	whitelistC chan []int
+	close      chan bool
+
+	// batchC is used to receive the batch graphing manager is working on
+	// batchSeq is the batch which the suspect messages are generated for, as the correct replicas will be
+	// blocked at the same batch by the txs which haven't been verified
+	batchC   chan uint64
+	batchSeq uint64
+
+	// replicas is the replica set of current epoch
+	// epochC is used to receive the replicas of new epoch
+	// early is used to hold the logs from the replicas which will be added in the new epoch
+	replicas map[uint64]bool
+	epochC   chan tp.EpochEvent
+	early    earlyLogs
+
+	// timerC is used to arm or disarm the gathering timer for the head of pending txs
+	// timeoutC is used to receive the expired seq from filter
+	// baC is used to report the candidates which have not ordered the head of pending txs on timeout
+	timerC   chan types.TimerEvent
+	timeoutC chan uint64
+	baC      chan tp.LocalBAEvent
+
+	// seqNo indicates the sequence number of the head of pending txs, which is used to arm the timer
+	seqNo     uint64
 	gathering bool
 
+	// metricsC is used to receive the queries for the amount of states kept by verifying manager
+	metricsC chan chan types.Metrics
+
 	logger logger.Logger
 }
 
-func newGatheringMgr(n, f int, whitelist []int, recvC chan *pb.OrderedLog, commC chan string, close chan bool, logger logger.Logger) *verifyingMgr {
+func newGatheringMgr(c types.VerifyingConfig) *verifyingMgr {
 	return &verifyingMgr{
-		n:           n,
-		f:           f,
-		whitelist:   whitelist,
+		n:           c.N,
+		f:           c.F,
+		whitelist:   c.Whitelist,
 		txRecorder:  make(map[string]utils.TxRecorder),
 		pendingTxs:  nil,
 		verifiedTxs: make(map[string]bool),
-		recvC:       recvC,
-		commC:       commC,
-		close:       close,
+		gcC:         c.GCC,
+		recvC:       c.RecvC,
+		commC:       c.VerifyC,
+		whitelistC:  c.WhitelistC,
+		batchC:      c.BatchC,
+		batchSeq:    1,
+		replicas:    replicaSet(c.Whitelist),
+		epochC:      c.EpochC,
+		early:       make(earlyLogs),
+		timerC:      c.TimerC,
+		timeoutC:    c.TimeoutC,
+		baC:         c.BAC,
+		close:       c.Close,
+		seqNo:       1,
 		gathering:   false,
-		logger:      logger,
+		metricsC:    c.MetricsC,
+		logger:      c.Logger,
 	}
 }
 
@@ -56,6 +99,26 @@ func (v *verifyingMgr) listener() {
 		case log := <-v.recvC:
 			v.add(log)
 			v.scanner()
+
+		case whitelist := <-v.whitelistC:
+			v.update(whitelist)
+
+		case seq := <-v.batchC:
+			v.batchSeq = seq
+
+		case event := <-v.epochC:
+			v.reconfigure(event)
+			v.scanner()
+
+		case seq := <-v.timeoutC:
+			v.timeout(seq)
+
+		case collected := <-v.gcC:
+			v.collect(collected)
+			v.scanner()
+
+		case replyC := <-v.metricsC:
+			replyC <- v.metrics()
 		}
 	}
 }
@@ -65,6 +128,16 @@ func (v *verifyingMgr) add(log *pb.OrderedLog) {
 		panic("nil log!")
 	}
 
+	if !v.replicas[log.ReplicaId] {
+		v.early.hold(log)
+		return
+	}
+
+	// the recorder for a stable tx has been collected, and the late logs for it are useless
+	if v.verifiedTxs[log.TxHash] && v.txRecorder[log.TxHash] == nil {
+		return
+	}
+
 	// update txRecorder
 	if v.txRecorder[log.TxHash] == nil {
 		v.txRecorder[log.TxHash] = utils.NewTxRecorder(v.whitelist, log.TxHash, v.n, v.f)
@@ -77,14 +150,22 @@ func (v *verifyingMgr) add(log *pb.OrderedLog) {
 	}
 }
 
-func (v *verifyingMgr) scanner() {
-	if len(v.pendingTxs) == 0 {
-		return
+// update is used to re-select the candidates for every transaction with the new whitelist
+func (v *verifyingMgr) update(whitelist []int) {
+	v.logger.Infof("[VERIFY] update whitelist %v", whitelist)
+	v.whitelist = whitelist
+	for _, recorder := range v.txRecorder {
+		recorder.Update(whitelist)
 	}
+}
 
-	v.softStartTimer()
-	txHash := v.pendingTxs[0]
-	if v.verifiedTxs[txHash] || v.txRecorder[txHash].OrderLen() >= v.allQuorumReplicas() {
+func (v *verifyingMgr) scanner() {
+	for len(v.pendingTxs) > 0 {
+		txHash := v.pendingTxs[0]
+		if !v.verifiedTxs[txHash] && v.txRecorder[txHash].OrderLen() < v.allQuorumReplicas() {
+			v.softStartTimer()
+			return
+		}
 		v.stopTimer()
 
 		if !v.verifiedTxs[txHash] {
@@ -93,12 +174,31 @@ func (v *verifyingMgr) scanner() {
 		}
 
 		v.pendingTxs = v.pendingTxs[1:]
-		if len(v.pendingTxs) > 0 {
-			v.scanner()
-		}
+		v.seqNo++
 	}
 }
 
+// timeout is used to report the candidates which have not ordered the head of pending txs, the expired
+// events for the txs which have been verified will be ignored.
+func (v *verifyingMgr) timeout(seq uint64) {
+	if !v.gathering || seq != v.seqNo || len(v.pendingTxs) == 0 {
+		v.logger.Debugf("[VERIFY] ignore expired timer, seq %d, current %d", seq, v.seqNo)
+		return
+	}
+	v.gathering = false
+
+	txHash := v.pendingTxs[0]
+	missing := v.txRecorder[txHash].GetMalicious()
+	if len(missing) > 0 {
+		v.logger.Warningf("[VERIFY] timeout for tx %s, replicas %v have not ordered it", txHash, missing)
+		// the candidates selected for current tx should have ordered it, so that they would be suspected
+		v.baC <- tp.LocalBAEvent{Seq: v.batchSeq, TxHash: txHash, MissingReplicas: missing, Suspect: true}
+	}
+
+	// re-arm the timer for the same tx
+	v.softStartTimer()
+}
+
 func (v *verifyingMgr) communicate(hash string) {
 	v.commC <- hash
 }
@@ -108,10 +208,15 @@ func (v *verifyingMgr) softStartTimer() {
 		return
 	}
 	v.gathering = true
+	v.timerC <- types.TimerEvent{Type: types.GatheringTimer, Seq: v.seqNo, Start: true}
 }
 
 func (v *verifyingMgr) stopTimer() {
+	if !v.gathering {
+		return
+	}
 	v.gathering = false
+	v.timerC <- types.TimerEvent{Type: types.GatheringTimer, Seq: v.seqNo, Start: false}
 }
 
 func (v *verifyingMgr) allReplicas() int {
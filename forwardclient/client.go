@@ -9,6 +9,14 @@ func NewClient(c types.Config) *clientImpl {
 	return newClientImpl(c)
 }
 
+func (c *clientImpl) Start() {
+	c.start()
+}
+
+func (c *clientImpl) Stop() {
+	c.stop()
+}
+
 func (c *clientImpl) ProposeTxs(txs []*pb.Transaction) {
 	c.propose(txs)
 }
@@ -1,6 +1,8 @@
 package forwardclient
 
 import (
+	"math"
+	"sync"
 	"time"
 
 	"github.com/Grivn/libfalanx/forwardclient/types"
@@ -8,6 +10,7 @@ import (
 	"github.com/Grivn/libfalanx/network"
 	"github.com/Grivn/libfalanx/zcommon"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
 
 	"github.com/golang/protobuf/proto"
 )
@@ -17,12 +20,22 @@ type clientImpl struct {
 	n  uint64
 	f  uint64
 
-	txs map[string]*pb.Transaction
-	seq uint64
+	// txs is used to track the proposed transactions which haven't been completed
+	// res is used to collect the replies, struct: tx hash ==> result digest ==> replicas
+	mutex sync.Mutex
+	txs   map[string]*pb.Transaction
+	seq   uint64
 
-	res map[string]map[uint64]bool
+	res map[string]map[string]map[uint64]bool
 
-	selfC chan *pb.OrderedReq
+	// replicas is the replica set of current epoch, the replies from other replicas will be ignored
+	replicas map[uint64]bool
+
+	selfC     chan *pb.OrderedReq
+	replyC    chan *pb.Reply
+	completeC chan *tp.CompleteEvent
+	epochC    chan tp.EpochEvent
+	close     chan bool
 
 	tools  zcommon.Tools
 	sender network.Network
@@ -30,30 +43,84 @@ type clientImpl struct {
 }
 
 func newClientImpl(config types.Config) *clientImpl {
+	f := int(math.Floor((float64(config.N) - 1) / 4))
+	if f == 0 {
+		f = 1
+	}
+	replicas := make(map[uint64]bool)
+	for i := 1; i <= config.N; i++ {
+		replicas[uint64(i)] = true
+	}
+
 	return &clientImpl{
-		id:     config.ID,
-		txs:    make(map[string]*pb.Transaction),
-		seq:    uint64(0),
-		selfC:  config.SelfC,
-		tools:  config.Tools,
-		sender: config.Sender,
-		logger: config.Logger,
+		id:        config.ID,
+		n:         uint64(config.N),
+		f:         uint64(f),
+		txs:       make(map[string]*pb.Transaction),
+		seq:       uint64(0),
+		res:       make(map[string]map[string]map[uint64]bool),
+		replicas:  replicas,
+		selfC:     config.SelfC,
+		replyC:    config.ReplyC,
+		completeC: config.CompleteC,
+		epochC:    config.EpochC,
+		close:     make(chan bool),
+		tools:     config.Tools,
+		sender:    config.Sender,
+		logger:    config.Logger,
+	}
+}
+
+func (c *clientImpl) start() {
+	go c.listener()
+}
+
+func (c *clientImpl) stop() {
+	close(c.close)
+}
+
+func (c *clientImpl) listener() {
+	for {
+		select {
+		case <-c.close:
+			return
+
+		case reply := <-c.replyC:
+			c.processReply(reply)
+
+		case event := <-c.epochC:
+			c.reconfigure(event)
+		}
 	}
 }
 
 func (c *clientImpl) propose(txs []*pb.Transaction) {
 	hashList := make([]string, len(txs))
+	c.mutex.Lock()
 	for index, tx := range txs {
 		hash := c.tools.TransactionHash(tx)
 		hashList[index] = hash
+		c.txs[hash] = tx
 	}
-
 	c.seq++
+	seq := c.seq
+	c.mutex.Unlock()
+
+	// disseminate the payloads before ordering them, so that the other replicas could execute them without
+	// fetching the payloads in most cases
+	c.disseminate(txs)
+
 	req := &pb.OrderedReq{
 		ClientId:   c.id,
-		Sequence:   c.seq,
+		Sequence:   seq,
 		TxHashList: hashList,
 		Timestamp:  time.Now().UnixNano(),
+
+		HashAlgorithm: c.tools.HashAlgorithm(),
+	}
+	if err := zcommon.SignOrderedReq(c.tools, req); err != nil {
+		c.logger.Errorf("Client %d sign ordered request failed: %s", c.id, err)
+		return
 	}
 	c.logger.Debugf("Client %d broadcast ordered request: [seq]%d", c.id, req.Sequence)
 
@@ -69,6 +136,115 @@ func (c *clientImpl) propose(txs []*pb.Transaction) {
 	c.inform(req)
 }
 
+func (c *clientImpl) disseminate(txs []*pb.Transaction) {
+	set := &pb.TxSet{
+		ReplicaId: c.id,
+		Txs:       txs,
+	}
+	if err := zcommon.SignTxSet(c.tools, set); err != nil {
+		c.logger.Errorf("Client %d sign tx set failed: %s", c.id, err)
+		return
+	}
+	setPayload, err := proto.Marshal(set)
+	if err != nil {
+		return
+	}
+	setMsg := &pb.ConsensusMessage{
+		Type:    pb.Type_TX_SET,
+		Payload: setPayload,
+	}
+	c.sender.Broadcast(setMsg)
+}
+
 func (c *clientImpl) inform(req *pb.OrderedReq) {
 	c.selfC <- req
 }
+
+// processReply is used to collect the replies from replicas, and a transaction will be reported as
+// completed once f+1 replicas have replied the same result for it
+func (c *clientImpl) processReply(reply *pb.Reply) {
+	if reply.ClientId != c.id {
+		return
+	}
+
+	// the complete event is posted after releasing the mutex, so that a slow consumer of completeC cannot
+	// block the proposals and reconfiguration of current client
+	event := c.collectReply(reply)
+	if event == nil {
+		return
+	}
+	select {
+	case c.completeC <- event:
+	case <-c.close:
+	}
+}
+
+// collectReply is used to record the reply, it returns the complete event once the transaction is completed
+func (c *clientImpl) collectReply(reply *pb.Reply) *tp.CompleteEvent {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.replicas[reply.ReplicaId] {
+		c.logger.Debugf("Client %d ignore reply from replica %d, which is not in current epoch", c.id, reply.ReplicaId)
+		return nil
+	}
+
+	if _, ok := c.txs[reply.TxHash]; !ok {
+		c.logger.Debugf("Client %d ignore reply for tx %s from replica %d", c.id, reply.TxHash, reply.ReplicaId)
+		return nil
+	}
+
+	if err := zcommon.VerifyReply(c.tools, reply); err != nil {
+		c.logger.Warningf("Client %d received invalid reply from replica %d: %s", c.id, reply.ReplicaId, err)
+		return nil
+	}
+
+	results, ok := c.res[reply.TxHash]
+	if !ok {
+		results = make(map[string]map[uint64]bool)
+		c.res[reply.TxHash] = results
+	}
+	result := c.tools.CalculatePayloadHash(reply.Result, 0)
+	replicas, ok := results[result]
+	if !ok {
+		replicas = make(map[uint64]bool)
+		results[result] = replicas
+	}
+	replicas[reply.ReplicaId] = true
+	if uint64(len(replicas)) < c.f+1 {
+		return nil
+	}
+
+	c.logger.Infof("Client %d tx %s has been completed", c.id, reply.TxHash)
+	delete(c.txs, reply.TxHash)
+	delete(c.res, reply.TxHash)
+	return &tp.CompleteEvent{TxHash: reply.TxHash, Result: reply.Result}
+}
+
+// reconfigure is used to switch to the replicas of new epoch, the replies collected from the removed replicas
+// will be dropped
+func (c *clientImpl) reconfigure(event tp.EpochEvent) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	f := int(math.Floor((float64(len(event.Replicas)) - 1) / 4))
+	if f == 0 {
+		f = 1
+	}
+	c.n = uint64(len(event.Replicas))
+	c.f = uint64(f)
+	c.replicas = make(map[uint64]bool)
+	for _, id := range event.Replicas {
+		c.replicas[uint64(id)] = true
+	}
+	for _, results := range c.res {
+		for _, replicas := range results {
+			for id := range replicas {
+				if !c.replicas[id] {
+					delete(replicas, id)
+				}
+			}
+		}
+	}
+	c.logger.Infof("Client %d switch to epoch %d, replicas %v", c.id, event.Epoch, event.Replicas)
+}
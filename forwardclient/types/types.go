@@ -5,13 +5,22 @@ import (
 	"github.com/Grivn/libfalanx/network"
 	"github.com/Grivn/libfalanx/zcommon"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
 )
 
+// Config is used to initiate the forward client
+// ReplyC:    receive the replies from replicas
+// CompleteC: notify the application of the transactions which have been replied by f+1 replicas
+// EpochC:    receive the replicas of new epoch
 type Config struct {
-	ID     uint64
-	Hash   string
-	SelfC  chan *pb.OrderedReq
-	Tools  zcommon.Tools
-	Sender network.Network
-	Logger logger.Logger
+	ID        uint64
+	N         int
+	Hash      string
+	SelfC     chan *pb.OrderedReq
+	ReplyC    chan *pb.Reply
+	CompleteC chan *tp.CompleteEvent
+	EpochC    chan tp.EpochEvent
+	Tools     zcommon.Tools
+	Sender    network.Network
+	Logger    logger.Logger
 }
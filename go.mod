@@ -5,4 +5,5 @@ go 1.14
 require (
 	github.com/gogo/protobuf v1.3.2
 	github.com/golang/protobuf v1.5.2
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871
 )
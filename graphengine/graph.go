@@ -1,7 +1,9 @@
 package graphengine
 
-func NewGraphEngine(graphEngine chan interface{}) *graphEngineImpl {
-	return newGraphEngineImpl(graphEngine)
+import "github.com/Grivn/libfalanx/graphengine/types"
+
+func NewGraphEngine(c types.Config) *graphEngineImpl {
+	return newGraphEngineImpl(c)
 }
 
 func (g *graphEngineImpl) Start() {
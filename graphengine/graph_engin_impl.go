@@ -1,8 +1,11 @@
 package graphengine
 
 import (
+	dagType "github.com/Grivn/libfalanx/dagmanager/types"
 	"github.com/Grivn/libfalanx/graphengine/types"
+	"github.com/Grivn/libfalanx/graphengine/utils"
 	"github.com/Grivn/libfalanx/logger"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
 )
 
 type graphEngineImpl struct {
@@ -13,26 +16,38 @@ type graphEngineImpl struct {
 	// here, we will assign a sequence number for every tx, and initiate the relation of txs
 	// according to the filter.vpRecorder
 	rawG types.Graph
-	rawV map[string]types.V
+	rawV map[uint64]*types.V
 
 	seqNo uint64
 	txMap map[string]uint64
 	idMap map[uint64]string
 
-	dfn   uint64
-	cVertex map[uint64]types.V
+	// tarjan
+	// dfn:        the depth-first search counter
+	// stack:      the tarjan stack
+	// components: the strongly connected components found by tarjan
+	// cMap:       the index of component which contains the vertex
+	dfn        uint64
+	stack      utils.TarjanStack
+	components []types.Component
+	cMap       map[uint64]int
 
-	graphEngine chan interface{}
+	// graphEngine: receive the relation graph from filter
+	// dagC:        post the linear order of batch to DAG manager
+	graphEngine chan tp.GraphEvent
+	dagC        chan *dagType.DAGValue
 	close  chan bool
 
 	logger logger.Logger
 }
 
-func newGraphEngineImpl(graphEngine chan interface{}) *graphEngineImpl {
+func newGraphEngineImpl(c types.Config) *graphEngineImpl {
 	return &graphEngineImpl{
 		graphSize:   types.DefaultGraphSize,
-		graphEngine: graphEngine,
+		graphEngine: c.GraphC,
+		dagC:        c.DAGC,
 		close:       make(chan bool),
+		logger:      c.Logger,
 	}
 }
 
@@ -51,20 +66,28 @@ func (g *graphEngineImpl) listenEvent() {
 			return
 
 		case event := <-g.graphEngine:
-			switch e := event.(type) {
-			case map[string][]string:
-				g.printGraph(e)
-			}
+			g.processGraph(event)
 		}
 	}
 }
 
-func (g *graphEngineImpl) printGraph(graph map[string][]string) {
-	for from := range graph {
-		toList := graph[from]
-		g.logger.Infof("%s out degree is %d", from, len(toList))
-		for to := range toList {
-			g.logger.Infof("    ===> %s", to)
-		}
+func (g *graphEngineImpl) processGraph(event tp.GraphEvent) {
+	g.logger.Infof("[ENGINE] receive graph of batch %d, vertices %d, edges %d", event.Seq, len(event.Vertices), len(event.Edges))
+	g.printGraph(event)
+
+	order := g.linearize(event)
+	g.logger.Infof("[ENGINE] linear order of batch %d: %v", event.Seq, order)
+
+	g.dagC <- &dagType.DAGValue{
+		Seq:      event.Seq,
+		Order:    order,
+		External: event.External,
+		Pending:  event.Pending,
+	}
+}
+
+func (g *graphEngineImpl) printGraph(event tp.GraphEvent) {
+	for _, edge := range event.Edges {
+		g.logger.Debugf("%s ===> %s, votes %d", edge.From, edge.To, edge.Votes)
 	}
 }
@@ -0,0 +1,207 @@
+package graphengine
+
+import (
+	"sort"
+
+	"github.com/Grivn/libfalanx/graphengine/types"
+	"github.com/Grivn/libfalanx/graphengine/utils"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// linearize is used to generate a linear order for the transactions in relation graph.
+// an edge from -> to indicates the transaction 'from' has a higher priority than 'to'.
+// the transactions which have no relation with others will also be ordered as single components.
+//
+//  1. tarjan algorithm is used to find the strongly connected components, the transactions in one
+//     component have constructed a condorcet cycle and they cannot be ordered by the relations.
+//  2. every component is collapsed into a vertex, and we will get a DAG of components, which could
+//     be ordered by topological sorting. if there are several components could be selected, we will
+//     choose the one whose minimum transaction hash is smaller.
+//  3. the transactions in one component are ordered by the amount of transactions they have precedence
+//     over inside the component, then the amount of votes for these precedences, and the smaller hash
+//     will be selected if both of them are equal.
+func (g *graphEngineImpl) linearize(event tp.GraphEvent) []string {
+	g.generateRawGraph(event)
+
+	for id := uint64(1); id <= g.seqNo; id++ {
+		if g.rawV[id].DFN == 0 {
+			g.tarjan(id)
+		}
+	}
+
+	var order []string
+	for _, index := range g.sortComponents() {
+		for _, id := range g.sortVertices(g.components[index]) {
+			order = append(order, g.idMap[id])
+		}
+	}
+	return order
+}
+
+// generateRawGraph is used to initiate the status of engine for a new graph, the sequence numbers
+// are assigned according to the order of hash so that every replica will generate the same result
+func (g *graphEngineImpl) generateRawGraph(event tp.GraphEvent) {
+	vertices := make(map[string]bool)
+	for _, hash := range event.Vertices {
+		vertices[hash] = true
+	}
+	g.rawG = make(types.Graph)
+	for _, edge := range event.Edges {
+		vertices[edge.From] = true
+		vertices[edge.To] = true
+		if g.rawG[edge.From] == nil {
+			g.rawG[edge.From] = make(map[string]int)
+		}
+		g.rawG[edge.From][edge.To] = edge.Votes
+	}
+
+	var hashes []string
+	for hash := range vertices {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	g.seqNo = 0
+	g.txMap = make(map[string]uint64)
+	g.idMap = make(map[uint64]string)
+	g.rawV = make(map[uint64]*types.V)
+	for _, hash := range hashes {
+		g.seqNo++
+		g.txMap[hash] = g.seqNo
+		g.idMap[g.seqNo] = hash
+		g.rawV[g.seqNo] = &types.V{}
+	}
+
+	g.dfn = 0
+	g.stack = utils.NewTarjanStack()
+	g.components = nil
+	g.cMap = make(map[uint64]int)
+}
+
+func (g *graphEngineImpl) tarjan(id uint64) {
+	g.dfn++
+	v := g.rawV[id]
+	v.DFN = g.dfn
+	v.Low = g.dfn
+	v.Pushed = true
+	g.stack.Push(id)
+
+	for _, next := range g.successors(id) {
+		w := g.rawV[next]
+		if w.DFN == 0 {
+			g.tarjan(next)
+			if w.Low < v.Low {
+				v.Low = w.Low
+			}
+		} else if w.Pushed && w.DFN < v.Low {
+			v.Low = w.DFN
+		}
+	}
+
+	if v.DFN != v.Low {
+		return
+	}
+
+	// current vertex is the root of a strongly connected component
+	var component types.Component
+	for {
+		top := g.stack.Pop()
+		g.rawV[top].Pushed = false
+		g.cMap[top] = len(g.components)
+		component = append(component, top)
+		if top == id {
+			break
+		}
+	}
+	g.components = append(g.components, component)
+}
+
+// successors returns the vertices which could be reached from id directly, in the order of sequence number
+func (g *graphEngineImpl) successors(id uint64) []uint64 {
+	var list []uint64
+	for to := range g.rawG[g.idMap[id]] {
+		list = append(list, g.txMap[to])
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// sortComponents returns the topological order of the components
+func (g *graphEngineImpl) sortComponents() []int {
+	inDegree := make([]int, len(g.components))
+	edges := make([]map[int]bool, len(g.components))
+	for index := range g.components {
+		edges[index] = make(map[int]bool)
+	}
+	for from, toSet := range g.rawG {
+		cFrom := g.cMap[g.txMap[from]]
+		for to := range toSet {
+			cTo := g.cMap[g.txMap[to]]
+			if cFrom == cTo || edges[cFrom][cTo] {
+				continue
+			}
+			edges[cFrom][cTo] = true
+			inDegree[cTo]++
+		}
+	}
+
+	// the minimum sequence number indicates the minimum hash in component
+	minID := make([]uint64, len(g.components))
+	for index, component := range g.components {
+		minID[index] = component[0]
+		for _, id := range component {
+			if id < minID[index] {
+				minID[index] = id
+			}
+		}
+	}
+
+	var ready []int
+	for index := range g.components {
+		if inDegree[index] == 0 {
+			ready = append(ready, index)
+		}
+	}
+
+	var order []int
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return minID[ready[i]] < minID[ready[j]] })
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+		for next := range edges[current] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+	return order
+}
+
+// sortVertices returns the order of vertices in a strongly connected component
+func (g *graphEngineImpl) sortVertices(component types.Component) []uint64 {
+	wins := make(map[uint64]int)
+	votes := make(map[uint64]int)
+	for _, id := range component {
+		for _, next := range g.successors(id) {
+			if g.cMap[next] == g.cMap[id] {
+				wins[id]++
+				votes[id] += g.rawG[g.idMap[id]][g.idMap[next]]
+			}
+		}
+	}
+
+	vertices := make([]uint64, len(component))
+	copy(vertices, component)
+	sort.Slice(vertices, func(i, j int) bool {
+		if wins[vertices[i]] != wins[vertices[j]] {
+			return wins[vertices[i]] > wins[vertices[j]]
+		}
+		if votes[vertices[i]] != votes[vertices[j]] {
+			return votes[vertices[i]] > votes[vertices[j]]
+		}
+		return vertices[i] < vertices[j]
+	})
+	return vertices
+}
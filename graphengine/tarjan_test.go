@@ -0,0 +1,103 @@
+package graphengine
+
+import (
+	"fmt"
+	"testing"
+
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+func edge(from, to string, votes int) tp.Edge {
+	return tp.Edge{From: from, To: to, Votes: votes}
+}
+
+func TestLinearize(t *testing.T) {
+	tests := []struct {
+		name     string
+		vertices []string
+		edges    []tp.Edge
+		expect   []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "isolated vertices are ordered by hash",
+			vertices: []string{"c", "a", "b"},
+			expect:   []string{"a", "b", "c"},
+		},
+		{
+			name:   "chain",
+			edges:  []tp.Edge{edge("c", "b", 3), edge("b", "a", 3)},
+			expect: []string{"c", "b", "a"},
+		},
+		{
+			name:   "cycle with equal wins and votes is ordered by hash",
+			edges:  []tp.Edge{edge("a", "b", 3), edge("b", "c", 3), edge("c", "a", 3)},
+			expect: []string{"a", "b", "c"},
+		},
+		{
+			name:   "cycle is ordered by wins",
+			edges:  []tp.Edge{edge("z", "x", 3), edge("x", "y", 3), edge("y", "z", 3), edge("z", "w", 3), edge("w", "x", 3)},
+			expect: []string{"z", "w", "x", "y"},
+		},
+		{
+			name:   "cycle with equal wins is ordered by votes",
+			edges:  []tp.Edge{edge("a", "b", 2), edge("b", "c", 3), edge("c", "a", 2)},
+			expect: []string{"b", "a", "c"},
+		},
+		{
+			name: "nested cycles are condensed into one component",
+			edges: []tp.Edge{
+				edge("x", "a", 3),
+				edge("a", "b", 3), edge("b", "c", 3), edge("c", "a", 3),
+				edge("c", "d", 3), edge("d", "e", 3), edge("e", "c", 3),
+				edge("e", "y", 3),
+			},
+			expect: []string{"x", "c", "a", "b", "d", "e", "y"},
+		},
+		{
+			name:   "disconnected components prefer the smaller hash",
+			edges:  []tp.Edge{edge("c", "a", 3), edge("d", "b", 3)},
+			expect: []string{"c", "a", "d", "b"},
+		},
+		{
+			name: "disconnected cycles",
+			edges: []tp.Edge{
+				edge("b", "d", 3), edge("d", "f", 3), edge("f", "b", 3),
+				edge("a", "e", 3), edge("e", "c", 3), edge("c", "a", 3),
+			},
+			expect: []string{"a", "c", "e", "b", "d", "f"},
+		},
+		{
+			name:     "isolated vertices among components",
+			vertices: []string{"a", "b", "c", "d"},
+			edges:    []tp.Edge{edge("d", "c", 3)},
+			expect:   []string{"a", "b", "d", "c"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := &graphEngineImpl{}
+			order := g.linearize(tp.GraphEvent{Vertices: test.vertices, Edges: test.edges})
+			if fmt.Sprint(order) != fmt.Sprint(test.expect) {
+				t.Fatalf("linear order %v, expect %v", order, test.expect)
+			}
+
+			// the order should not depend on the order the vertices and edges are delivered in
+			vertices := make([]string, len(test.vertices))
+			for index, hash := range test.vertices {
+				vertices[len(vertices)-1-index] = hash
+			}
+			edges := make([]tp.Edge, len(test.edges))
+			for index, e := range test.edges {
+				edges[len(edges)-1-index] = e
+			}
+			order = g.linearize(tp.GraphEvent{Vertices: vertices, Edges: edges})
+			if fmt.Sprint(order) != fmt.Sprint(test.expect) {
+				t.Fatalf("linear order %v with reversed input, expect %v", order, test.expect)
+			}
+		})
+	}
+}
@@ -1,5 +1,17 @@
 package types
 
+import (
+	dagType "github.com/Grivn/libfalanx/dagmanager/types"
+	"github.com/Grivn/libfalanx/logger"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+type Config struct {
+	GraphC chan tp.GraphEvent
+	DAGC   chan *dagType.DAGValue
+	Logger logger.Logger
+}
+
 type TxSet map[string]bool
 
 type TxInfo struct {
@@ -9,16 +21,25 @@ type TxInfo struct {
 
 type Finality bool
 
-type Graph map[string]map[string]bool
+// Graph is the relation graph, from ==> to ==> votes
+type Graph map[string]map[string]int
 
 type IDMap map[uint64]string
 
+// V is the vertex status used in tarjan algorithm
+// DFN:    the order of current vertex in depth-first search
+// Low:    the lowest DFN which could be reached from current vertex
+// Pushed: whether current vertex is in tarjan stack
 type V struct {
 	DFN    uint64
 	Low    uint64
 	Pushed bool
 }
 
+// Component is a strongly connected component in raw graph, which means the transactions in it
+// have constructed a condorcet cycle
+type Component []uint64
+
 const (
 	DefaultGraphSize = 50
 )
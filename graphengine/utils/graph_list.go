@@ -49,6 +49,8 @@ func (s *tarjanStack) len() int {
 	return s.length
 }
 func (s *tarjanStack) peek() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 	if s.length == 0 {
 		return 0
 	}
@@ -69,7 +71,8 @@ func (s *tarjanStack) push(id uint64) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	n := &node{
-		id: id,
+		id:   id,
+		prev: s.top,
 	}
 	s.top = n
 	s.length++
@@ -1,5 +1,303 @@
 package localba
 
-type BAProcessor struct {
+import (
+	"math"
+	"sort"
+	"time"
 
+	"github.com/Grivn/libfalanx/localba/types"
+	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+type baProcessor struct {
+	id uint64
+
+	ba SimpleBA
+
+	// channel =====================================================================
+	// eventC:     receive the local events from filter
+	// recvC:      receive the votes from other replicas
+	// whitelistC: post the replica removed from whitelist to filter
+	// suspectC:   receive the suspect messages from other replicas
+	// blacklistC: post the replica suspected by f+1 replicas to filter
+	// epochC:     receive the replicas of new epoch
+	eventC     chan tp.LocalBAEvent
+	recvC      chan *pb.BaVote
+	whitelistC chan tp.ExcludeEvent
+	suspectC   chan *pb.Suspect
+	blacklistC chan tp.ExcludeEvent
+	epochC     chan tp.EpochEvent
+	close      chan bool
+
+	// suspects is used to track the suspect messages for every replica in every batch, the replica will be
+	// blacklisted from the batch for DefaultBlacklistBatches batches once f+1 distinct replicas have suspected
+	// it in it, as at least one of them must be correct. the suspect messages expire after DefaultExpiration.
+	f        int
+	replicas map[uint64]bool
+	suspects map[types.Key]*types.SuspectMalice
+
+	network network.Network
+	tools   zcommon.Tools
+	logger  logger.Logger
+}
+
+func newBAProcessor(c types.Config) *baProcessor {
+	n := len(c.Replicas)
+	f := int(math.Floor((float64(n) - 1) / 4))
+	if f == 0 {
+		f = 1
+	}
+
+	replicas := make(map[uint64]bool)
+	for _, id := range c.Replicas {
+		replicas[uint64(id)] = true
+	}
+
+	return &baProcessor{
+		id:         c.ID,
+		ba:         newSimpleBAImpl(c.Replicas, n, f),
+		eventC:     c.EventC,
+		recvC:      c.RecvC,
+		whitelistC: c.WhitelistC,
+		suspectC:   c.SuspectC,
+		blacklistC: c.BlacklistC,
+		epochC:     c.EpochC,
+		close:      make(chan bool),
+		f:          f,
+		replicas:   replicas,
+		suspects:   make(map[types.Key]*types.SuspectMalice),
+		network:    c.Network,
+		tools:      c.Tools,
+		logger:     c.Logger,
+	}
+}
+
+func (bp *baProcessor) start() {
+	go bp.listener()
+}
+
+func (bp *baProcessor) stop() {
+	close(bp.close)
+}
+
+func (bp *baProcessor) listener() {
+	for {
+		select {
+		case <-bp.close:
+			return
+
+		case event := <-bp.eventC:
+			bp.processLocalEvent(event)
+
+		case vote := <-bp.recvC:
+			bp.processVote(vote)
+
+		case suspect := <-bp.suspectC:
+			bp.processSuspect(suspect)
+
+		case event := <-bp.epochC:
+			bp.reconfigure(event)
+		}
+	}
+}
+
+// processLocalEvent is used to vote for the replicas which haven't sent the logs in time
+func (bp *baProcessor) processLocalEvent(event tp.LocalBAEvent) {
+	if len(event.MissingReplicas) == 0 {
+		return
+	}
+
+	vote := &pb.BaVote{
+		ReplicaId:       bp.id,
+		TxHash:          event.TxHash,
+		MissingReplicas: event.MissingReplicas,
+		Seq:             event.Seq,
+	}
+	if err := zcommon.SignBaVote(bp.tools, vote); err != nil {
+		bp.logger.Errorf("[BA] sign vote failed: %s", err)
+		return
+	}
+	payload, err := proto.Marshal(vote)
+	if err != nil {
+		bp.logger.Errorf("[BA] marshal vote failed: %s", err)
+		return
+	}
+	msg := &pb.ConsensusMessage{
+		Type:    pb.Type_BA_VOTE,
+		Payload: payload,
+	}
+	bp.network.Broadcast(msg)
+	bp.logger.Infof("[BA] replica %d vote to remove %v in batch %d, tx %s", bp.id, event.MissingReplicas, event.Seq, event.TxHash)
+
+	bp.processVote(vote)
+
+	if event.Suspect {
+		for _, id := range event.MissingReplicas {
+			bp.suspect(id, event.Seq)
+		}
+	}
+}
+
+// suspect is used to broadcast the suspect message for the malice replica
+func (bp *baProcessor) suspect(maliceID uint64, seq uint64) {
+	suspect := &pb.Suspect{
+		ReplicaId: bp.id,
+		MaliceId:  maliceID,
+		Seq:       seq,
+	}
+	if err := zcommon.SignSuspect(bp.tools, suspect); err != nil {
+		bp.logger.Errorf("[BA] sign suspect failed: %s", err)
+		return
+	}
+	payload, err := proto.Marshal(suspect)
+	if err != nil {
+		bp.logger.Errorf("[BA] marshal suspect failed: %s", err)
+		return
+	}
+	msg := &pb.ConsensusMessage{
+		Type:    pb.Type_SUSPECT,
+		Payload: payload,
+	}
+	bp.network.Broadcast(msg)
+	bp.logger.Infof("[BA] replica %d suspect replica %d in batch %d", bp.id, maliceID, seq)
+
+	bp.processSuspect(suspect)
+}
+
+func (bp *baProcessor) processSuspect(suspect *pb.Suspect) {
+	if !bp.replicas[suspect.ReplicaId] || !bp.replicas[suspect.MaliceId] {
+		bp.logger.Warningf("[BA] invalid suspect from replica %d for replica %d", suspect.ReplicaId, suspect.MaliceId)
+		return
+	}
+	bp.expire()
+
+	key := types.Key{ID: suspect.MaliceId, Seq: suspect.Seq}
+	malice, ok := bp.suspects[key]
+	if !ok {
+		malice = &types.SuspectMalice{
+			MaliceID: suspect.MaliceId,
+			Seq:      suspect.Seq,
+			Suspects: make(map[uint64]*pb.Suspect),
+			Arrived:  make(map[uint64]time.Time),
+		}
+		bp.suspects[key] = malice
+	}
+	if malice.Blacklisted {
+		return
+	}
+
+	malice.Suspects[suspect.ReplicaId] = suspect
+	malice.Arrived[suspect.ReplicaId] = time.Now()
+	if len(malice.Suspects) < bp.f+1 {
+		return
+	}
+
+	malice.Blacklisted = true
+	bp.logger.Infof("[BA] replica %d has been suspected by %d replicas in batch %d, blacklist it", malice.MaliceID, len(malice.Suspects), malice.Seq)
+
+	// forward the suspect messages, so that the other replicas would blacklist it from the same batch even if
+	// some of the messages haven't been sent to them
+	for _, id := range sortedIDs(malice.Suspects) {
+		bp.forward(pb.Type_SUSPECT, malice.Suspects[id])
+	}
+	bp.blacklistC <- tp.ExcludeEvent{ID: malice.MaliceID, Seq: malice.Seq}
+}
+
+func (bp *baProcessor) processVote(vote *pb.BaVote) {
+	bp.expire()
+
+	agreed := bp.ba.Update(vote)
+	if len(agreed) == 0 {
+		return
+	}
+
+	for _, id := range agreed {
+		certificate := bp.ba.Certificate(id, vote.Seq)
+		if err := bp.ba.RemoveCandidate(id); err != nil {
+			bp.logger.Warningf("[BA] cannot remove replica %d: %s", id, err)
+			continue
+		}
+		bp.logger.Infof("[BA] replica %d has been removed from whitelist in batch %d", id, vote.Seq)
+
+		// forward the votes as the certificate of the agreement, see processSuspect
+		for _, cert := range certificate {
+			bp.forward(pb.Type_BA_VOTE, cert)
+		}
+		bp.whitelistC <- tp.ExcludeEvent{ID: id, Seq: vote.Seq}
+	}
+}
+
+// forward is used to broadcast the message signed by another replica
+func (bp *baProcessor) forward(typ pb.Type, m proto.Message) {
+	payload, err := proto.Marshal(m)
+	if err != nil {
+		bp.logger.Errorf("[BA] marshal forwarded message failed: %s", err)
+		return
+	}
+	bp.network.Broadcast(&pb.ConsensusMessage{Type: typ, Payload: payload})
+}
+
+// expire is used to drop the votes and suspect messages which have been kept for DefaultExpiration, the ones
+// which have reached agreement expire as well, and the exclusion posted once again will be ignored by filter.
+func (bp *baProcessor) expire() {
+	deadline := time.Now().Add(-types.DefaultExpiration)
+	bp.ba.Expire(deadline)
+	for key, malice := range bp.suspects {
+		for id, arrived := range malice.Arrived {
+			if arrived.Before(deadline) {
+				delete(malice.Suspects, id)
+				delete(malice.Arrived, id)
+			}
+		}
+		if len(malice.Suspects) == 0 {
+			delete(bp.suspects, key)
+		}
+	}
+}
+
+func sortedIDs(suspects map[uint64]*pb.Suspect) []uint64 {
+	var ids []uint64
+	for id := range suspects {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// reconfigure is used to switch to the replicas of new epoch, the votes to remove replicas from whitelist are
+// dropped as the whitelist is reset to the new replicas, while the suspects for the remaining replicas are kept
+func (bp *baProcessor) reconfigure(event tp.EpochEvent) {
+	n := len(event.Replicas)
+	f := int(math.Floor((float64(n) - 1) / 4))
+	if f == 0 {
+		f = 1
+	}
+
+	replicas := make(map[uint64]bool)
+	for _, id := range event.Replicas {
+		replicas[uint64(id)] = true
+	}
+	for key, malice := range bp.suspects {
+		if !replicas[key.ID] {
+			delete(bp.suspects, key)
+			continue
+		}
+		for suspect := range malice.Suspects {
+			if !replicas[suspect] {
+				delete(malice.Suspects, suspect)
+				delete(malice.Arrived, suspect)
+			}
+		}
+	}
+
+	bp.ba = newSimpleBAImpl(event.Replicas, n, f)
+	bp.f = f
+	bp.replicas = replicas
+	bp.logger.Infof("[BA] switch to epoch %d, replicas %v", event.Epoch, event.Replicas)
 }
@@ -0,0 +1,119 @@
+package localba
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Grivn/libfalanx/localba/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// testLogger drops the logs, the failures are reported by the tests themselves
+type testLogger struct{}
+
+func (testLogger) Debug(v ...interface{})                   {}
+func (testLogger) Debugf(format string, v ...interface{})   {}
+func (testLogger) Info(v ...interface{})                    {}
+func (testLogger) Infof(format string, v ...interface{})    {}
+func (testLogger) Warning(v ...interface{})                 {}
+func (testLogger) Warningf(format string, v ...interface{}) {}
+func (testLogger) Error(v ...interface{})                   {}
+func (testLogger) Errorf(format string, v ...interface{})   {}
+
+// recordNetwork records the types of the messages broadcast by the processor
+type recordNetwork struct {
+	types []pb.Type
+}
+
+func (n *recordNetwork) Broadcast(msg *pb.ConsensusMessage)          { n.types = append(n.types, msg.Type) }
+func (n *recordNetwork) Unicast(to uint64, msg *pb.ConsensusMessage) {}
+
+func newTestProcessor() (*baProcessor, *recordNetwork) {
+	network := &recordNetwork{}
+	return newBAProcessor(types.Config{
+		ID:         1,
+		Replicas:   []int{1, 2, 3, 4},
+		WhitelistC: make(chan tp.ExcludeEvent, 10),
+		BlacklistC: make(chan tp.ExcludeEvent, 10),
+		Network:    network,
+		Logger:     testLogger{},
+	}), network
+}
+
+// TestVoteBatches collects the votes to remove replica 4 in different batches, the replica should only be
+// removed once a quorum has voted in the same batch, and the votes should be forwarded as the certificate
+func TestVoteBatches(t *testing.T) {
+	bp, network := newTestProcessor()
+
+	bp.processVote(&pb.BaVote{ReplicaId: 2, MissingReplicas: []uint64{4}, Seq: 5})
+	bp.processVote(&pb.BaVote{ReplicaId: 3, MissingReplicas: []uint64{4}, Seq: 5})
+	bp.processVote(&pb.BaVote{ReplicaId: 1, MissingReplicas: []uint64{4}, Seq: 6})
+	if len(bp.whitelistC) != 0 {
+		t.Fatalf("replica 4 is removed with the votes in different batches")
+	}
+
+	bp.processVote(&pb.BaVote{ReplicaId: 1, MissingReplicas: []uint64{4}, Seq: 5})
+	if len(bp.whitelistC) != 1 {
+		t.Fatalf("replica 4 is not removed with a quorum of votes")
+	}
+	if event := <-bp.whitelistC; event.ID != 4 || event.Seq != 5 {
+		t.Fatalf("remove replica %d in batch %d, expect replica 4 in batch 5", event.ID, event.Seq)
+	}
+	if len(network.types) != 3 || network.types[0] != pb.Type_BA_VOTE {
+		t.Fatalf("forward %v, expect 3 votes", network.types)
+	}
+}
+
+// TestSuspectBatches suspects replica 4 in different batches, the replica should only be blacklisted once
+// f+1 replicas have suspected it in the same batch, and the suspect messages should be forwarded
+func TestSuspectBatches(t *testing.T) {
+	bp, network := newTestProcessor()
+
+	bp.processSuspect(&pb.Suspect{ReplicaId: 2, MaliceId: 4, Seq: 5})
+	bp.processSuspect(&pb.Suspect{ReplicaId: 3, MaliceId: 4, Seq: 6})
+	if len(bp.blacklistC) != 0 {
+		t.Fatalf("replica 4 is blacklisted with the suspect messages in different batches")
+	}
+
+	bp.processSuspect(&pb.Suspect{ReplicaId: 2, MaliceId: 4, Seq: 6})
+	if event := <-bp.blacklistC; event.ID != 4 || event.Seq != 6 {
+		t.Fatalf("blacklist replica %d in batch %d, expect replica 4 in batch 6", event.ID, event.Seq)
+	}
+	if len(network.types) != 2 || network.types[0] != pb.Type_SUSPECT {
+		t.Fatalf("forward %v, expect 2 suspect messages", network.types)
+	}
+}
+
+// TestSuspectExpire keeps suspecting replica 4, the suspect messages kept for DefaultExpiration should be
+// dropped, and the replica could be blacklisted once again in a later batch
+func TestSuspectExpire(t *testing.T) {
+	bp, network := newTestProcessor()
+
+	bp.processSuspect(&pb.Suspect{ReplicaId: 2, MaliceId: 4, Seq: 5})
+	malice := bp.suspects[types.Key{ID: 4, Seq: 5}]
+	malice.Arrived[2] = time.Now().Add(-types.DefaultExpiration - time.Second)
+
+	bp.processSuspect(&pb.Suspect{ReplicaId: 3, MaliceId: 4, Seq: 5})
+	if len(bp.blacklistC) != 0 || len(bp.suspects[types.Key{ID: 4, Seq: 5}].Suspects) != 1 {
+		t.Fatalf("replica 4 is blacklisted with the expired suspect message")
+	}
+
+	bp.processSuspect(&pb.Suspect{ReplicaId: 2, MaliceId: 4, Seq: 5})
+	if event := <-bp.blacklistC; event.ID != 4 || event.Seq != 5 {
+		t.Fatalf("blacklist replica %d in batch %d, expect replica 4 in batch 5", event.ID, event.Seq)
+	}
+	if len(network.types) != 2 || network.types[0] != pb.Type_SUSPECT {
+		t.Fatalf("forward %v, expect 2 suspect messages", network.types)
+	}
+
+	bp.processSuspect(&pb.Suspect{ReplicaId: 3, MaliceId: 4, Seq: 5})
+	bp.processSuspect(&pb.Suspect{ReplicaId: 2, MaliceId: 4, Seq: 80})
+	bp.processSuspect(&pb.Suspect{ReplicaId: 3, MaliceId: 4, Seq: 80})
+	if len(bp.blacklistC) != 1 {
+		t.Fatalf("post %d exclusions, expect replica 4 to be blacklisted once again", len(bp.blacklistC))
+	}
+	if event := <-bp.blacklistC; event.Seq != 80 {
+		t.Fatalf("blacklist replica 4 in batch %d, expect batch 80", event.Seq)
+	}
+}
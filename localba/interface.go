@@ -1,12 +1,25 @@
 package localba
 
+import (
+	"time"
+
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
 type SimpleBA interface {
-	// Update
-	Update(txHash string)
+	// Update is used to record the vote from replica, and it returns the replicas which have been agreed
+	// to be removed by efficient replicas in the batch of the vote
+	Update(vote *pb.BaVote) []uint64
+
+	// Certificate returns the votes which have agreed to remove particular replica in particular batch
+	Certificate(id uint64, seq uint64) []*pb.BaVote
+
+	// Expire is used to drop the votes which have arrived before deadline without reaching agreement
+	Expire(deadline time.Time)
 
 	// ElectCandidates is used to find a list of nodes to make finalization
-	ElectCandidates() []string
+	ElectCandidates() []int
 
 	// RemoveCandidate is used to remove the node whose network might be abnormal
-	RemoveCandidate() error
+	RemoveCandidate(id uint64) error
 }
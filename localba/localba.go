@@ -0,0 +1,15 @@
+package localba
+
+import "github.com/Grivn/libfalanx/localba/types"
+
+func NewLocalBA(c types.Config) *baProcessor {
+	return newBAProcessor(c)
+}
+
+func (bp *baProcessor) Start() {
+	bp.start()
+}
+
+func (bp *baProcessor) Stop() {
+	bp.stop()
+}
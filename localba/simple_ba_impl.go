@@ -1,5 +1,153 @@
 package localba
 
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Grivn/libfalanx/localba/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
 type simpleBAImpl struct {
-	
+	n int
+	f int
+
+	// whitelist is the replicas which could be selected as candidates
+	whitelist []int
+
+	// votes is used to record the replicas which have voted to remove particular replica in particular batch,
+	// the votes for different batches are not counted together, as the removal should take effect from the
+	// same batch on every replica
+	votes map[types.Key]*types.VoteRecord
+}
+
+func newSimpleBAImpl(replicas []int, n, f int) *simpleBAImpl {
+	whitelist := make([]int, len(replicas))
+	copy(whitelist, replicas)
+	sort.Ints(whitelist)
+	return &simpleBAImpl{
+		n:         n,
+		f:         f,
+		whitelist: whitelist,
+		votes:     make(map[types.Key]*types.VoteRecord),
+	}
+}
+
+func (ba *simpleBAImpl) Update(vote *pb.BaVote) []uint64 {
+	return ba.update(vote)
+}
+
+func (ba *simpleBAImpl) Certificate(id uint64, seq uint64) []*pb.BaVote {
+	return ba.certificate(id, seq)
+}
+
+func (ba *simpleBAImpl) Expire(deadline time.Time) {
+	ba.expire(deadline)
+}
+
+func (ba *simpleBAImpl) ElectCandidates() []int {
+	return ba.electCandidates()
+}
+
+func (ba *simpleBAImpl) RemoveCandidate(id uint64) error {
+	return ba.removeCandidate(id)
+}
+
+func (ba *simpleBAImpl) update(vote *pb.BaVote) []uint64 {
+	if !ba.contains(vote.ReplicaId) {
+		return nil
+	}
+
+	var agreed []uint64
+	for _, id := range vote.MissingReplicas {
+		if id == vote.ReplicaId || !ba.contains(id) {
+			continue
+		}
+		key := types.Key{ID: id, Seq: vote.Seq}
+		record, ok := ba.votes[key]
+		if !ok {
+			record = &types.VoteRecord{
+				Votes:   make(map[uint64]*pb.BaVote),
+				Arrived: make(map[uint64]time.Time),
+			}
+			ba.votes[key] = record
+		}
+		record.Votes[vote.ReplicaId] = vote
+		record.Arrived[vote.ReplicaId] = time.Now()
+
+		if len(record.Votes) >= ba.allQuorumReplicas() {
+			agreed = append(agreed, id)
+		}
+	}
+	return agreed
+}
+
+func (ba *simpleBAImpl) certificate(id uint64, seq uint64) []*pb.BaVote {
+	record, ok := ba.votes[types.Key{ID: id, Seq: seq}]
+	if !ok {
+		return nil
+	}
+	var votes []*pb.BaVote
+	for _, vote := range record.Votes {
+		votes = append(votes, vote)
+	}
+	sort.Slice(votes, func(i, j int) bool { return votes[i].ReplicaId < votes[j].ReplicaId })
+	return votes
+}
+
+func (ba *simpleBAImpl) expire(deadline time.Time) {
+	for key, record := range ba.votes {
+		for voter, arrived := range record.Arrived {
+			if arrived.Before(deadline) {
+				delete(record.Votes, voter)
+				delete(record.Arrived, voter)
+			}
+		}
+		if len(record.Votes) == 0 {
+			delete(ba.votes, key)
+		}
+	}
+}
+
+func (ba *simpleBAImpl) electCandidates() []int {
+	candidates := make([]int, len(ba.whitelist))
+	copy(candidates, ba.whitelist)
+	return candidates
+}
+
+func (ba *simpleBAImpl) removeCandidate(id uint64) error {
+	if !ba.contains(id) {
+		return errors.New("non-existed candidate")
+	}
+	if len(ba.whitelist) <= ba.allQuorumReplicas() {
+		return errors.New("not enough candidates")
+	}
+
+	var whitelist []int
+	for _, replica := range ba.whitelist {
+		if uint64(replica) != id {
+			whitelist = append(whitelist, replica)
+		}
+	}
+	ba.whitelist = whitelist
+	for key := range ba.votes {
+		if key.ID == id {
+			delete(ba.votes, key)
+		}
+	}
+	return nil
+}
+
+func (ba *simpleBAImpl) contains(id uint64) bool {
+	for _, replica := range ba.whitelist {
+		if uint64(replica) == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (ba *simpleBAImpl) allQuorumReplicas() int {
+	return ba.n - ba.f
 }
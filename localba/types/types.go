@@ -1,5 +1,67 @@
 package types
 
-type SuspectMalice struct {
+import (
+	"time"
+
+	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	tp "github.com/Grivn/libfalanx/zcommon/types"
+)
+
+// Config is used to initiate the local byzantine agreement instance
+// EventC:     receive the local events which report the replicas missing logs
+// RecvC:      receive the votes from other replicas
+// WhitelistC: post the replica removed from whitelist, and the batch to remove it
+// SuspectC:   receive the suspect messages from other replicas
+// BlacklistC: post the replica which has been suspected by f+1 replicas, and the batch to blacklist it
+// EpochC:     receive the replicas of new epoch, it could be nil if the replica set is fixed
+// Tools:      sign the votes and suspect messages of current replica, so that they cannot be forged by others
+type Config struct {
+	ID         uint64
+	Replicas   []int
+	EventC     chan tp.LocalBAEvent
+	RecvC      chan *pb.BaVote
+	WhitelistC chan tp.ExcludeEvent
+	SuspectC   chan *pb.Suspect
+	BlacklistC chan tp.ExcludeEvent
+	EpochC     chan tp.EpochEvent
+	Network    network.Network
+	Tools      zcommon.Tools
+	Logger     logger.Logger
+}
+
+// DefaultExpiration is the duration to keep the votes and suspect messages which haven't reached agreement,
+// the replicas blocked by the missing ones will vote once again every time their timers expire, so that
+// only the stale ones for the batches which have been finalized are dropped.
+const DefaultExpiration = time.Minute
 
+// Key is used to identify the agreement to exclude particular replica from particular batch
+type Key struct {
+	ID  uint64
+	Seq uint64
+}
+
+// VoteRecord is used to collect the votes to remove particular replica from whitelist in particular batch
+// Votes:   the votes from distinct replicas, they are forwarded as the certificate once a quorum is reached
+// Arrived: the time the vote from every replica has arrived
+type VoteRecord struct {
+	Votes   map[uint64]*pb.BaVote
+	Arrived map[uint64]time.Time
+}
+
+// SuspectMalice is used to collect the suspect messages for particular replica in particular batch
+// MaliceID:    the replica which has been suspected
+// Seq:         the batch which the suspect messages have been generated for
+// Suspects:    the suspect messages from distinct replicas, they are forwarded as the certificate once f+1
+//              ones have been collected, so that the other replicas will blacklist the malice one as well
+// Arrived:     the time the suspect message from every replica has arrived
+// Blacklisted: whether the malice one has been suspected by f+1 replicas
+type SuspectMalice struct {
+	MaliceID    uint64
+	Seq         uint64
+	Suspects    map[uint64]*pb.Suspect
+	Arrived     map[uint64]time.Time
+	Blacklisted bool
 }
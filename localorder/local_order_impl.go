@@ -5,41 +5,101 @@ import (
 	"time"
 
 	"github.com/Grivn/libfalanx/localorder/types"
+	"github.com/Grivn/libfalanx/localorder/utils"
 	"github.com/Grivn/libfalanx/logger"
 	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/zcommon"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
 )
 
 type localOrderImpl struct {
-	id uint64
-	seqNo uint64
-	recvC chan string
-	selfC chan *pb.OrderedLog
-	close chan bool
-	network network.Network
-	logger logger.Logger
+	id       uint64
+	seqNo    uint64
+	lastTs   int64
+	walPath  string
+	wal      utils.WAL
+	replayed []*pb.OrderedLog
+	recvC    chan string
+	selfC    chan *pb.OrderedLog
+	compactC chan uint64
+	close    chan bool
+	network  network.Network
+	tools    zcommon.Tools
+	logger   logger.Logger
 }
 
 func newLocalOrderImpl(c types.Config) *localOrderImpl {
 	return &localOrderImpl{
-		id:      c.ID,
-		seqNo:   uint64(0),
-		recvC:   c.RecvC,
-		selfC:   c.SelfC,
-		network: c.Network,
-		logger:  c.Logger,
+		id:       c.ID,
+		seqNo:    uint64(0),
+		walPath:  c.WALPath,
+		recvC:    c.RecvC,
+		selfC:    c.SelfC,
+		compactC: c.CompactC,
+		close:    make(chan bool),
+		network:  c.Network,
+		tools:    c.Tools,
+		logger:   c.Logger,
 	}
 }
 
 func (local *localOrderImpl) start() {
+	local.recover()
 	go local.listenTxHash()
 }
 
 func (local *localOrderImpl) stop() {
 	close(local.close)
+	if local.wal != nil {
+		if err := local.wal.Close(); err != nil {
+			local.logger.Errorf("Replica %d close wal failed: %s", local.id, err)
+		}
+	}
+}
+
+// recover is used to open the write-ahead log and restore the sequence number and timestamp from the logs
+// generated before restart, it panics if the wal cannot be read, as the replica would re-issue conflicting logs
+func (local *localOrderImpl) recover() {
+	if local.walPath == "" {
+		return
+	}
+	wal, err := utils.NewWAL(local.walPath)
+	if err != nil {
+		panic(err)
+	}
+	logs, err := wal.Replay()
+	if err != nil {
+		panic(err)
+	}
+	local.wal = wal
+	local.replayed = logs
+	if len(logs) == 0 {
+		return
+	}
+	last := logs[len(logs)-1]
+	local.seqNo = last.Sequence
+	local.lastTs = last.Timestamp
+	local.logger.Infof("Replica %d recovered %d local order logs from wal, seq %d", local.id, len(logs), local.seqNo)
+}
+
+// replay is used to re-deliver the recovered logs to self, and re-broadcast the last one, as the replica
+// might have crashed after persisting it but before broadcasting it, the peers will ignore the duplicated one
+func (local *localOrderImpl) replay() {
+	if len(local.replayed) == 0 {
+		return
+	}
+	for _, log := range local.replayed {
+		local.inform(log)
+	}
+	last := local.replayed[len(local.replayed)-1]
+	local.replayed = nil
+	if logPayload, err := proto.Marshal(last); err == nil {
+		local.network.Broadcast(&pb.ConsensusMessage{Type: pb.Type_ORDERED_LOG, Payload: logPayload})
+	}
 }
 
 func (local *localOrderImpl) listenTxHash() {
+	local.replay()
 	for {
 		select {
 		case <-local.close:
@@ -47,18 +107,63 @@ func (local *localOrderImpl) listenTxHash() {
 
 		case txHash := <-local.recvC:
 			local.order(txHash)
+
+		case seq := <-local.compactC:
+			local.compact(seq)
 		}
 	}
 }
 
+// compact is used to drop the logs which won't be replayed after restart, as the replica will be resumed
+// from the persisted snapshot covering them
+func (local *localOrderImpl) compact(seq uint64) {
+	if local.wal == nil {
+		return
+	}
+	if err := local.wal.Compact(seq); err != nil {
+		local.logger.Errorf("Replica %d compact wal up to seq %d failed: %s", local.id, seq, err)
+		return
+	}
+	local.logger.Debugf("Replica %d compacted wal up to seq %d", local.id, seq)
+}
+
 func (local *localOrderImpl) order(txHash string) {
-	local.seqNo++
+	// the timestamp should be monotonic even if the clock of current replica has gone backwards after restart
+	timestamp := time.Now().UnixNano()
+	if timestamp <= local.lastTs {
+		timestamp = local.lastTs + 1
+	}
+
 	log := &pb.OrderedLog{
 		ReplicaId: local.id,
-		Sequence:  local.seqNo,
+		Sequence:  local.seqNo + 1,
 		TxHash:    txHash,
-		Timestamp: time.Now().UnixNano(),
+		Timestamp: timestamp,
+
+		HashAlgorithm: local.tools.HashAlgorithm(),
 	}
+	if err := zcommon.SignOrderedLog(local.tools, log); err != nil {
+		local.logger.Errorf("Replica %d sign local order failed: %s", local.id, err)
+		return
+	}
+
+	// persist the log before broadcasting it, or the replica would re-issue the sequence number after restart.
+	// the tx hash cannot be dropped either, so that we will keep retrying until it has been persisted
+	for local.wal != nil {
+		err := local.wal.Append(log)
+		if err == nil {
+			break
+		}
+		local.logger.Errorf("Replica %d persist local order failed, retry in %s: %s", local.id, types.RetryInterval, err)
+		select {
+		case <-local.close:
+			return
+		case <-time.After(types.RetryInterval):
+		}
+	}
+	local.seqNo = log.Sequence
+	local.lastTs = log.Timestamp
+
 	logPayload, err := proto.Marshal(log)
 	if err != nil {
 		return
@@ -1,15 +1,29 @@
 package types
 
 import (
+	"time"
+
 	"github.com/Grivn/libfalanx/logger"
 	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/zcommon"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
 )
 
+// Config is used to initiate the local order
+// WALPath:  the file of write-ahead log which persists the generated logs, it is disabled if it's empty
+// CompactC: receive the sequence number of the latest log of current replica which has been covered by a
+//           persisted snapshot of stable batch, the logs up to it will be dropped from the write-ahead log,
+//           it could be nil if the write-ahead log is disabled
 type Config struct {
-	ID      uint64
-	RecvC   chan string
-	SelfC   chan *pb.OrderedLog
-	Network network.Network
-	Logger  logger.Logger
+	ID       uint64
+	WALPath  string
+	RecvC    chan string
+	SelfC    chan *pb.OrderedLog
+	CompactC chan uint64
+	Network  network.Network
+	Tools    zcommon.Tools
+	Logger   logger.Logger
 }
+
+// RetryInterval is the duration to wait before persisting a log once again, after the write-ahead log failed
+const RetryInterval = 100 * time.Millisecond
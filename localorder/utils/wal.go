@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// WAL is the write-ahead log of the ordered logs generated by current replica, every log should be
+// persisted before it is broadcast, so that the sequence numbers and timestamps remain monotonic after
+// the replica restarts.
+// Compact is used to drop the logs whose sequence number is not larger than seq, the last log is always kept
+// so that the sequence number and timestamp could be restored from it.
+type WAL interface {
+	Append(log *pb.OrderedLog) error
+	Replay() ([]*pb.OrderedLog, error)
+	Compact(seq uint64) error
+	Close() error
+}
+
+func NewWAL(path string) (*walImpl, error) {
+	return newWALImpl(path)
+}
+
+func (w *walImpl) Append(log *pb.OrderedLog) error {
+	return w.append(log)
+}
+
+func (w *walImpl) Replay() ([]*pb.OrderedLog, error) {
+	return w.replay()
+}
+
+func (w *walImpl) Compact(seq uint64) error {
+	return w.compact(seq)
+}
+
+func (w *walImpl) Close() error {
+	return w.close()
+}
+
+// walImpl stores the logs in one file, every record is framed as:
+// |length(4 bytes)|crc32(4 bytes)|payload(length bytes)|
+// a torn record at the tail of file, which is caused by a crash during writing, will be truncated on replay,
+// and a corrupted record in the middle of file fails the replay.
+type walImpl struct {
+	mutex sync.Mutex
+	path  string
+	file  *os.File
+}
+
+const walHeaderSize = 8
+
+func newWALImpl(path string) (*walImpl, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &walImpl{path: path, file: file}, nil
+}
+
+func (w *walImpl) append(log *pb.OrderedLog) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	record, err := encodeRecord(log)
+	if err != nil {
+		return err
+	}
+
+	offset, err := w.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err = w.file.Write(record); err == nil {
+		err = w.file.Sync()
+	}
+	if err != nil {
+		// drop the partially written record, or the records appended by the retries would be hidden behind it
+		_ = w.file.Truncate(offset)
+		return err
+	}
+	return nil
+}
+
+func (w *walImpl) replay() ([]*pb.OrderedLog, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.read()
+}
+
+// compact is used to rewrite the wal with the logs above seq, the new file is written aside and renamed to
+// replace the old one, so that a crash during compaction leaves either of them intact
+func (w *walImpl) compact(seq uint64) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	logs, err := w.read()
+	if err != nil {
+		return err
+	}
+	index := 0
+	for index < len(logs)-1 && logs[index].Sequence <= seq {
+		index++
+	}
+	if index == 0 {
+		return nil
+	}
+
+	tmpPath := w.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	for _, log := range logs[index:] {
+		record, err := encodeRecord(log)
+		if err == nil {
+			_, err = tmp.Write(record)
+		}
+		if err != nil {
+			_ = tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := os.Rename(tmpPath, w.path); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if dir, err := os.Open(filepath.Dir(w.path)); err == nil {
+		_ = dir.Sync()
+		_ = dir.Close()
+	}
+
+	_ = w.file.Close()
+	w.file = tmp
+	return nil
+}
+
+// read is used to load the logs from the beginning of file. only the torn record at the tail, which is caused
+// by a crash during writing, will be truncated, while the corrupted record followed by others is reported as
+// an error, as the logs after it cannot be dropped silently
+func (w *walImpl) read() ([]*pb.OrderedLog, error) {
+	info, err := w.file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var logs []*pb.OrderedLog
+	offset := int64(0)
+	header := make([]byte, walHeaderSize)
+	for offset < size {
+		if size-offset < walHeaderSize {
+			// the header has been torn
+			break
+		}
+		if _, err := io.ReadFull(w.file, header); err != nil {
+			return nil, err
+		}
+		length := binary.BigEndian.Uint32(header[0:4])
+		checksum := binary.BigEndian.Uint32(header[4:8])
+
+		end := offset + walHeaderSize + int64(length)
+		if end > size {
+			// the payload has been torn
+			break
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(w.file, payload); err != nil {
+			return nil, err
+		}
+		if crc32.ChecksumIEEE(payload) != checksum {
+			if end == size {
+				// the last record has been torn
+				break
+			}
+			return nil, fmt.Errorf("corrupted record at offset %d in wal", offset)
+		}
+
+		log := &pb.OrderedLog{}
+		if err := log.Unmarshal(payload); err != nil {
+			return nil, fmt.Errorf("invalid record at offset %d in wal: %s", offset, err)
+		}
+		if len(logs) > 0 && log.Sequence != logs[len(logs)-1].Sequence+1 {
+			return nil, errors.New("non-consecutive sequence number in wal")
+		}
+		logs = append(logs, log)
+		offset = end
+	}
+	if offset == size {
+		return logs, nil
+	}
+
+	// drop the torn record
+	if err := w.file.Truncate(offset); err != nil {
+		return nil, err
+	}
+	if err := w.file.Sync(); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func encodeRecord(log *pb.OrderedLog) ([]byte, error) {
+	payload, err := log.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	record := make([]byte, walHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[walHeaderSize:], payload)
+	return record, nil
+}
+
+func (w *walImpl) close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.file.Close()
+}
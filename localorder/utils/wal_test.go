@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+func newTestWAL(t *testing.T) (*walImpl, string) {
+	dir, err := ioutil.TempDir("", "falanx-wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "localorder.wal")
+	wal, err := newWALImpl(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wal, path
+}
+
+func testLog(seq uint64) *pb.OrderedLog {
+	return &pb.OrderedLog{ReplicaId: 1, Sequence: seq, TxHash: "tx", Timestamp: int64(seq)}
+}
+
+// appendLogs is used to append the logs from seq 'from' to 'to', and returns the size of wal file
+func appendLogs(t *testing.T, wal *walImpl, path string, from, to uint64) int64 {
+	for seq := from; seq <= to; seq++ {
+		if err := wal.Append(testLog(seq)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+// reopen is used to simulate a restart, the wal is closed and opened once again
+func reopen(t *testing.T, wal *walImpl, path string) *walImpl {
+	if err := wal.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wal, err := newWALImpl(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wal
+}
+
+func expectLogs(t *testing.T, wal *walImpl, from, to uint64) {
+	logs, err := wal.Replay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uint64(len(logs)) != to-from+1 {
+		t.Fatalf("replay %d logs, expect [%d, %d]", len(logs), from, to)
+	}
+	for index, log := range logs {
+		if log.Sequence != from+uint64(index) {
+			t.Fatalf("replay log %d at %d, expect %d", log.Sequence, index, from+uint64(index))
+		}
+	}
+}
+
+// writeRaw is used to append the bytes to wal file bypassing the wal
+func writeRaw(t *testing.T, path string, data []byte, offset int64) {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteAt(data, offset); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	wal, path := newTestWAL(t)
+	appendLogs(t, wal, path, 1, 5)
+	wal = reopen(t, wal, path)
+	defer wal.Close()
+
+	expectLogs(t, wal, 1, 5)
+}
+
+// TestTornTail writes parts of a record at the tail of wal, as if the replica crashed during writing, and
+// the torn record should be truncated so that the logs appended later could be replayed
+func TestTornTail(t *testing.T) {
+	record, err := encodeRecord(testLog(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	torn := map[string][]byte{
+		"header":   record[:walHeaderSize-2],
+		"payload":  record[:len(record)-3],
+		"checksum": append(append([]byte(nil), record[:len(record)-1]...), record[len(record)-1]^0xff),
+	}
+
+	for name, data := range torn {
+		t.Run(name, func(t *testing.T) {
+			wal, path := newTestWAL(t)
+			size := appendLogs(t, wal, path, 1, 3)
+			writeRaw(t, path, data, size)
+			wal = reopen(t, wal, path)
+			defer wal.Close()
+
+			expectLogs(t, wal, 1, 3)
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size() != size {
+				t.Fatalf("wal size %d after truncating torn record, expect %d", info.Size(), size)
+			}
+
+			appendLogs(t, wal, path, 4, 5)
+			expectLogs(t, wal, 1, 5)
+		})
+	}
+}
+
+// TestCorruptedRecord corrupts a record followed by others, the replay should fail rather than truncating
+// the logs after it
+func TestCorruptedRecord(t *testing.T) {
+	wal, path := newTestWAL(t)
+	first := appendLogs(t, wal, path, 1, 1)
+	size := appendLogs(t, wal, path, 2, 3)
+	writeRaw(t, path, []byte{0xff}, first+walHeaderSize+1)
+	wal = reopen(t, wal, path)
+	defer wal.Close()
+
+	if logs, err := wal.Replay(); err == nil {
+		t.Fatalf("replay %d logs from corrupted wal", len(logs))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != size {
+		t.Fatalf("wal size %d after failed replay, expect %d", info.Size(), size)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	wal, path := newTestWAL(t)
+	appendLogs(t, wal, path, 1, 5)
+
+	if err := wal.Compact(3); err != nil {
+		t.Fatal(err)
+	}
+	expectLogs(t, wal, 4, 5)
+
+	// the last log is always kept to restore the sequence number
+	if err := wal.Compact(10); err != nil {
+		t.Fatal(err)
+	}
+	expectLogs(t, wal, 5, 5)
+
+	appendLogs(t, wal, path, 6, 7)
+	wal = reopen(t, wal, path)
+	defer wal.Close()
+	expectLogs(t, wal, 5, 7)
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temporary file is left after compaction: %v", err)
+	}
+}
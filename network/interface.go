@@ -5,5 +5,9 @@ import (
 )
 
 type Network interface {
+	// Broadcast is used to send the message to all the other replicas
 	Broadcast(msg *pb.ConsensusMessage)
+
+	// Unicast is used to send the message to particular replica
+	Unicast(to uint64, msg *pb.ConsensusMessage)
 }
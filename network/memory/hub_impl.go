@@ -0,0 +1,273 @@
+package memory
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/network/memory/types"
+	netType "github.com/Grivn/libfalanx/network/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+type hubImpl struct {
+	mutex sync.RWMutex
+
+	// peers is used to track the registered replicas, struct: replica id ==> peer
+	peers map[uint64]*peer
+
+	// link ========================================================================
+	// defaultLink: the behaviour of the links which haven't been overridden
+	// links:       the overridden links, struct: from ==> to ==> link
+	// groups:      the partition group of every replica, nil if there isn't any partition
+	defaultLink types.LinkConfig
+	links       map[uint64]map[uint64]types.LinkConfig
+	groups      map[uint64]int
+
+	randMutex sync.Mutex
+	rand      *rand.Rand
+
+	close chan bool
+
+	logger logger.Logger
+}
+
+// peer is the endpoint of a replica, the messages will be delivered into the receiver of it
+type peer struct {
+	id       uint64
+	hub      *hubImpl
+	receiver netType.NetworkReceiver
+
+	// mailbox is used to queue the messages which haven't been received by the replica in the order they
+	// arrived, the pump blocks until the receiver accepts them so that no message is dropped when the
+	// receiver is full, and the senders are never blocked by a slow receiver
+	mutex   sync.Mutex
+	mailbox []*netType.Message
+	notifyC chan bool
+	close   chan bool
+}
+
+func newHubImpl(c types.Config) *hubImpl {
+	return &hubImpl{
+		peers:       make(map[uint64]*peer),
+		defaultLink: c.Link,
+		links:       make(map[uint64]map[uint64]types.LinkConfig),
+		rand:        rand.New(rand.NewSource(c.Seed)),
+		close:       make(chan bool),
+		logger:      c.Logger,
+	}
+}
+
+func (h *hubImpl) stop() {
+	close(h.close)
+}
+
+func (h *hubImpl) register(id uint64, receiver netType.NetworkReceiver) network.Network {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if old, ok := h.peers[id]; ok {
+		close(old.close)
+	}
+	p := &peer{
+		id:       id,
+		hub:      h,
+		receiver: receiver,
+		notifyC:  make(chan bool, 1),
+		close:    make(chan bool),
+	}
+	h.peers[id] = p
+	go p.pump()
+	return p
+}
+
+func (h *hubImpl) setLink(from, to uint64, link types.LinkConfig) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.links[from] == nil {
+		h.links[from] = make(map[uint64]types.LinkConfig)
+	}
+	h.links[from][to] = link
+}
+
+func (h *hubImpl) partition(groups [][]uint64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.groups = make(map[uint64]int)
+	for index, group := range groups {
+		for _, id := range group {
+			h.groups[id] = index + 1
+		}
+	}
+	h.logger.Infof("[MEMNET] partition %v", groups)
+}
+
+func (h *hubImpl) heal() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.groups = nil
+	h.logger.Infof("[MEMNET] heal partition")
+}
+
+// broadcast is used to send the message to every replica except the sender
+func (h *hubImpl) broadcast(from uint64, msg *pb.ConsensusMessage) {
+	payload, err := msg.Marshal()
+	if err != nil {
+		h.logger.Errorf("[MEMNET] marshal message failed: %s", err)
+		return
+	}
+
+	h.mutex.RLock()
+	var ids []uint64
+	for id := range h.peers {
+		if id != from {
+			ids = append(ids, id)
+		}
+	}
+	h.mutex.RUnlock()
+
+	// the targets are sorted so that the random source will be used in the same order with the same seed
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, to := range ids {
+		h.send(from, to, payload)
+	}
+}
+
+// unicast is used to send the message to particular replica, the message to sender itself will be delivered
+// without the behaviour of links
+func (h *hubImpl) unicast(from, to uint64, msg *pb.ConsensusMessage) {
+	payload, err := msg.Marshal()
+	if err != nil {
+		h.logger.Errorf("[MEMNET] marshal message failed: %s", err)
+		return
+	}
+	h.send(from, to, payload)
+}
+
+// send is used to deliver the payload to the target with the behaviour of the link between them
+func (h *hubImpl) send(from, to uint64, payload []byte) {
+	h.mutex.RLock()
+	target, ok := h.peers[to]
+	link := h.getLink(from, to)
+	reachable := h.reachable(from, to)
+	h.mutex.RUnlock()
+
+	if !ok || !reachable {
+		return
+	}
+	if from == to {
+		target.deliver(from, payload)
+		return
+	}
+
+	drop, delay := h.sample(link)
+	if drop {
+		h.logger.Debugf("[MEMNET] drop message from %d to %d", from, to)
+		return
+	}
+
+	if delay <= 0 {
+		target.deliver(from, payload)
+		return
+	}
+	time.AfterFunc(delay, func() {
+		target.deliver(from, payload)
+	})
+}
+
+func (h *hubImpl) getLink(from, to uint64) types.LinkConfig {
+	if links, ok := h.links[from]; ok {
+		if link, ok := links[to]; ok {
+			return link
+		}
+	}
+	return h.defaultLink
+}
+
+func (h *hubImpl) reachable(from, to uint64) bool {
+	if h.groups == nil {
+		return true
+	}
+	groupFrom, okFrom := h.groups[from]
+	groupTo, okTo := h.groups[to]
+	return okFrom && okTo && groupFrom == groupTo
+}
+
+func (h *hubImpl) sample(link types.LinkConfig) (bool, time.Duration) {
+	h.randMutex.Lock()
+	defer h.randMutex.Unlock()
+
+	if link.DropRate > 0 && h.rand.Float64() < link.DropRate {
+		return true, 0
+	}
+	delay := link.Latency
+	if link.Jitter > 0 {
+		delay += time.Duration(h.rand.Int63n(int64(link.Jitter)))
+	}
+	return false, delay
+}
+
+func (p *peer) Broadcast(msg *pb.ConsensusMessage) {
+	p.hub.broadcast(p.id, msg)
+}
+
+func (p *peer) Unicast(to uint64, msg *pb.ConsensusMessage) {
+	p.hub.unicast(p.id, to, msg)
+}
+
+func (p *peer) deliver(from uint64, payload []byte) {
+	msg := &pb.ConsensusMessage{}
+	if err := msg.Unmarshal(payload); err != nil {
+		p.hub.logger.Errorf("[MEMNET] unmarshal message failed: %s", err)
+		return
+	}
+
+	p.mutex.Lock()
+	p.mailbox = append(p.mailbox, &netType.Message{From: from, Msg: msg})
+	p.mutex.Unlock()
+
+	select {
+	case p.notifyC <- true:
+	default:
+		// the pump has been notified
+	}
+}
+
+// pump is used to move the messages in mailbox into the receiver one by one
+func (p *peer) pump() {
+	for {
+		select {
+		case <-p.hub.close:
+			return
+		case <-p.close:
+			return
+		case <-p.notifyC:
+		}
+
+		for {
+			p.mutex.Lock()
+			if len(p.mailbox) == 0 {
+				p.mailbox = nil
+				p.mutex.Unlock()
+				break
+			}
+			message := p.mailbox[0]
+			p.mailbox = p.mailbox[1:]
+			p.mutex.Unlock()
+
+			select {
+			case p.receiver.RecvC <- message:
+			case <-p.hub.close:
+				return
+			case <-p.close:
+				return
+			}
+		}
+	}
+}
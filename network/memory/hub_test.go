@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/network/memory/types"
+	netType "github.com/Grivn/libfalanx/network/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+type testLogger struct{}
+
+func (testLogger) Debug(v ...interface{})                   {}
+func (testLogger) Debugf(format string, v ...interface{})   {}
+func (testLogger) Info(v ...interface{})                    {}
+func (testLogger) Infof(format string, v ...interface{})    {}
+func (testLogger) Warning(v ...interface{})                 {}
+func (testLogger) Warningf(format string, v ...interface{}) {}
+func (testLogger) Error(v ...interface{})                   {}
+func (testLogger) Errorf(format string, v ...interface{})   {}
+
+// TestDeliverToFullReceiver checks that the messages are kept in order rather than dropped when the receiver
+// is full, and the sender isn't blocked by it
+func TestDeliverToFullReceiver(t *testing.T) {
+	hub := NewHub(types.Config{Seed: 1, Logger: testLogger{}})
+	defer hub.Stop()
+
+	receiver := netType.NetworkReceiver{RecvC: make(chan *netType.Message, 1)}
+	sender := hub.Register(1, netType.NetworkReceiver{RecvC: make(chan *netType.Message, 1)})
+	hub.Register(2, receiver)
+
+	count := 100
+	sent := make(chan bool)
+	go func() {
+		for index := 0; index < count; index++ {
+			sender.Unicast(2, &pb.ConsensusMessage{Type: pb.Type_TX_SET, Payload: []byte{byte(index)}})
+		}
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sender is blocked by the full receiver")
+	}
+
+	for index := 0; index < count; index++ {
+		select {
+		case message := <-receiver.RecvC:
+			if message.From != 1 || message.Msg.Payload[0] != byte(index) {
+				t.Fatalf("expect message %d from replica 1, got %d from replica %d", index, message.Msg.Payload[0], message.From)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message %d has been dropped", index)
+		}
+	}
+}
+
+// TestPartition checks that the messages between different groups are dropped until the partition is healed
+func TestPartition(t *testing.T) {
+	hub := NewHub(types.Config{Seed: 1, Logger: testLogger{}})
+	defer hub.Stop()
+
+	receivers := make(map[uint64]netType.NetworkReceiver)
+	senders := make(map[uint64]network.Network)
+	for id := uint64(1); id <= 4; id++ {
+		receivers[id] = netType.NetworkReceiver{RecvC: make(chan *netType.Message, 10)}
+		senders[id] = hub.Register(id, receivers[id])
+	}
+
+	hub.Partition([]uint64{1, 2}, []uint64{3, 4})
+	senders[1].Unicast(3, &pb.ConsensusMessage{Payload: []byte("partitioned")})
+	senders[1].Unicast(2, &pb.ConsensusMessage{Payload: []byte("same group")})
+	expectMessage(t, receivers[2], "same group")
+	expectNothing(t, receivers[3])
+
+	hub.Heal()
+	senders[1].Unicast(3, &pb.ConsensusMessage{Payload: []byte("healed")})
+	expectMessage(t, receivers[3], "healed")
+}
+
+func expectMessage(t *testing.T, receiver netType.NetworkReceiver, payload string) {
+	select {
+	case message := <-receiver.RecvC:
+		if string(message.Msg.Payload) != payload {
+			t.Fatalf("expect %q, got %q", payload, message.Msg.Payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expect %q, got nothing", payload)
+	}
+}
+
+func expectNothing(t *testing.T, receiver netType.NetworkReceiver) {
+	select {
+	case message := <-receiver.RecvC:
+		t.Fatalf("expect nothing, got %q", message.Msg.Payload)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
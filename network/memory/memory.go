@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"github.com/Grivn/libfalanx/network"
+	"github.com/Grivn/libfalanx/network/memory/types"
+	netType "github.com/Grivn/libfalanx/network/types"
+)
+
+// NewHub is used to initiate an in-memory network which routes the messages between replicas in one process
+func NewHub(c types.Config) *hubImpl {
+	return newHubImpl(c)
+}
+
+func (h *hubImpl) Stop() {
+	h.stop()
+}
+
+// Register is used to attach a replica to the hub, the messages sent to it will be delivered into receiver,
+// and the returned network is used by the replica to send messages.
+func (h *hubImpl) Register(id uint64, receiver netType.NetworkReceiver) network.Network {
+	return h.register(id, receiver)
+}
+
+// SetLink is used to override the behaviour of the link from one replica to another
+func (h *hubImpl) SetLink(from, to uint64, link types.LinkConfig) {
+	h.setLink(from, to, link)
+}
+
+// Partition is used to split the replicas into groups, and the messages between different groups will be
+// dropped, the replicas not mentioned in any group are isolated from others
+func (h *hubImpl) Partition(groups ...[]uint64) {
+	h.partition(groups)
+}
+
+// Heal is used to remove the partitions
+func (h *hubImpl) Heal() {
+	h.heal()
+}
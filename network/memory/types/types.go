@@ -0,0 +1,26 @@
+package types
+
+import (
+	"time"
+
+	"github.com/Grivn/libfalanx/logger"
+)
+
+// LinkConfig is used to describe the behaviour of the link from one replica to another
+// Latency:  the basic delay of every message on the link
+// Jitter:   the random extra delay in [0, Jitter), messages sent one by one might be reordered with it
+// DropRate: the probability of dropping a message, in [0, 1]
+type LinkConfig struct {
+	Latency  time.Duration
+	Jitter   time.Duration
+	DropRate float64
+}
+
+// Config is used to initiate the in-memory network
+// Seed: the seed of random source, which makes the drop and jitter reproducible
+// Link: the default behaviour of every link, it could be overridden by SetLink
+type Config struct {
+	Seed   int64
+	Link   LinkConfig
+	Logger logger.Logger
+}
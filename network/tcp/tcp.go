@@ -0,0 +1,30 @@
+package tcp
+
+import (
+	"github.com/Grivn/libfalanx/network/tcp/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// NewTransport is used to initiate a tcp transport, the messages are framed with a 4-byte big-endian length
+// prefix. the accepting replica sends a random challenge on every connection, and the first frame from the
+// dialer carries its identifier and the signature for the challenge, so that the identifier is authenticated.
+func NewTransport(c types.Config) *transportImpl {
+	return newTransportImpl(c)
+}
+
+// Start is used to listen on the address of current replica and connect to the other replicas
+func (t *transportImpl) Start() error {
+	return t.start()
+}
+
+func (t *transportImpl) Stop() {
+	t.stop()
+}
+
+func (t *transportImpl) Broadcast(msg *pb.ConsensusMessage) {
+	t.broadcast(msg)
+}
+
+func (t *transportImpl) Unicast(to uint64, msg *pb.ConsensusMessage) {
+	t.unicast(to, msg)
+}
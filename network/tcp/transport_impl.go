@@ -0,0 +1,460 @@
+package tcp
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network/tcp/types"
+	netType "github.com/Grivn/libfalanx/network/types"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+type transportImpl struct {
+	id uint64
+
+	// peers is used to send messages to other replicas, struct: replica id ==> peer
+	// addr is the address to listen on, which is empty for a client, as it only dials the replicas
+	addr  string
+	peers map[uint64]*peer
+
+	listener net.Listener
+	receiver netType.NetworkReceiver
+
+	// conns is used to track the inbound connections, so that they could be closed on stop
+	// clients is used to send messages to the clients which have dialed current replica, the messages are
+	// written back on their connections, struct: client id ==> peer
+	mutex   sync.Mutex
+	conns   map[net.Conn]bool
+	clients map[uint64]*peer
+
+	reconnectDelay time.Duration
+	queueLen       int
+	close          chan bool
+	wg             sync.WaitGroup
+
+	signer zcommon.Signer
+	logger logger.Logger
+}
+
+// peer is the outbound endpoint of another replica, which keeps re-dialing until the transport stops, or the
+// endpoint of a client on the connection it has dialed
+type peer struct {
+	id    uint64
+	addr  string
+	sendC chan []byte
+}
+
+func newTransportImpl(c types.Config) *transportImpl {
+	reconnectDelay := c.ReconnectDelay
+	if reconnectDelay <= 0 {
+		reconnectDelay = types.DefaultReconnectDelay
+	}
+	queueLen := c.SendQueueLen
+	if queueLen <= 0 {
+		queueLen = types.DefaultSendQueueLen
+	}
+
+	peers := make(map[uint64]*peer)
+	for id, addr := range c.Peers {
+		if id == c.ID {
+			continue
+		}
+		peers[id] = &peer{
+			id:    id,
+			addr:  addr,
+			sendC: make(chan []byte, queueLen),
+		}
+	}
+
+	return &transportImpl{
+		id:             c.ID,
+		addr:           c.Peers[c.ID],
+		peers:          peers,
+		receiver:       c.Receiver,
+		conns:          make(map[net.Conn]bool),
+		clients:        make(map[uint64]*peer),
+		queueLen:       queueLen,
+		reconnectDelay: reconnectDelay,
+		close:          make(chan bool),
+		signer:         c.Signer,
+		logger:         c.Logger,
+	}
+}
+
+func (t *transportImpl) start() error {
+	if t.signer == nil {
+		return errors.New("nil signer")
+	}
+	if t.addr != "" {
+		listener, err := net.Listen("tcp", t.addr)
+		if err != nil {
+			return err
+		}
+		t.listener = listener
+		t.logger.Infof("[TCP] replica %d listen on %s", t.id, listener.Addr())
+
+		t.wg.Add(1)
+		go t.accept()
+	}
+
+	for _, p := range t.peers {
+		t.wg.Add(1)
+		go t.dial(p)
+	}
+	return nil
+}
+
+func (t *transportImpl) stop() {
+	close(t.close)
+	if t.listener != nil {
+		_ = t.listener.Close()
+	}
+
+	t.mutex.Lock()
+	for conn := range t.conns {
+		_ = conn.Close()
+	}
+	t.mutex.Unlock()
+
+	t.wg.Wait()
+}
+
+// broadcast is used to send the message to every replica except current one
+func (t *transportImpl) broadcast(msg *pb.ConsensusMessage) {
+	payload, err := msg.Marshal()
+	if err != nil {
+		t.logger.Errorf("[TCP] marshal message failed: %s", err)
+		return
+	}
+	for _, p := range t.peers {
+		t.send(p, payload)
+	}
+}
+
+// unicast is used to send the message to particular replica or client, the message to current replica will be
+// delivered into the receiver directly, and the one to a client which hasn't connected to current replica will
+// be dropped
+func (t *transportImpl) unicast(to uint64, msg *pb.ConsensusMessage) {
+	if to == t.id {
+		select {
+		case t.receiver.RecvC <- &netType.Message{From: t.id, Msg: msg}:
+		default:
+			t.logger.Warningf("[TCP] receiver of replica %d is full, drop message", t.id)
+		}
+		return
+	}
+
+	p, ok := t.peers[to]
+	if !ok {
+		t.mutex.Lock()
+		p, ok = t.clients[to]
+		t.mutex.Unlock()
+	}
+	if !ok {
+		t.logger.Warningf("[TCP] replica %d is not connected to replica or client %d", t.id, to)
+		return
+	}
+	payload, err := msg.Marshal()
+	if err != nil {
+		t.logger.Errorf("[TCP] marshal message failed: %s", err)
+		return
+	}
+	t.send(p, payload)
+}
+
+func (t *transportImpl) send(p *peer, payload []byte) {
+	select {
+	case p.sendC <- payload:
+	default:
+		t.logger.Warningf("[TCP] sending queue to %d is full, drop message", p.id)
+	}
+}
+
+// ============================================ outbound ============================================
+
+// dial is used to maintain the connection to the peer, it will re-dial the peer once the connection fails
+func (t *transportImpl) dial(p *peer) {
+	defer t.wg.Done()
+
+	for {
+		conn, err := net.DialTimeout("tcp", p.addr, t.reconnectDelay)
+		if err == nil {
+			t.logger.Infof("[TCP] replica %d connected to replica %d", t.id, p.id)
+			err = t.write(conn, p)
+			_ = conn.Close()
+			if err == nil {
+				// the transport has been stopped
+				return
+			}
+		}
+		t.logger.Debugf("[TCP] replica %d lost connection to replica %d: %s", t.id, p.id, err)
+
+		select {
+		case <-t.close:
+			return
+		case <-time.After(t.reconnectDelay):
+		}
+	}
+}
+
+// write is used to send the handshake and the queued messages on the connection, it returns nil only when
+// the transport has been stopped. the messages written back by the peer are received as well, which are the
+// ones sent to a client by the replica it has dialed
+func (t *transportImpl) write(conn net.Conn, p *peer) error {
+	if err := t.answer(conn, p.id); err != nil {
+		return err
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		t.receive(bufio.NewReader(conn), p.id)
+	}()
+
+	for {
+		select {
+		case <-t.close:
+			return nil
+
+		case payload := <-p.sendC:
+			if err := writeFrame(conn, payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ============================================ inbound ============================================
+
+func (t *transportImpl) accept() {
+	defer t.wg.Done()
+
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			select {
+			case <-t.close:
+				return
+			default:
+			}
+			t.logger.Warningf("[TCP] accept connection failed: %s", err)
+			continue
+		}
+
+		t.mutex.Lock()
+		t.conns[conn] = true
+		t.mutex.Unlock()
+
+		t.wg.Add(1)
+		go t.read(conn)
+	}
+}
+
+func (t *transportImpl) read(conn net.Conn) {
+	defer t.wg.Done()
+	defer func() {
+		_ = conn.Close()
+		t.mutex.Lock()
+		delete(t.conns, conn)
+		t.mutex.Unlock()
+	}()
+
+	reader := bufio.NewReader(conn)
+	from, err := t.challenge(conn, reader)
+	if err != nil {
+		t.logger.Warningf("[TCP] invalid handshake from %s: %s", conn.RemoteAddr(), err)
+		return
+	}
+
+	// the replicas receive our messages on the connections dialed by ourselves, while the clients never
+	// listen, so that the messages to a client are written back on its connection
+	if _, ok := t.peers[from]; !ok {
+		done := make(chan bool)
+		defer close(done)
+		p := t.register(from)
+		defer t.unregister(p)
+
+		t.wg.Add(1)
+		go t.serve(conn, p, done)
+	}
+
+	t.receive(reader, from)
+}
+
+// receive is used to deliver the messages read from the connection until it fails or the transport stops
+func (t *transportImpl) receive(reader io.Reader, from uint64) {
+	for {
+		payload, err := readFrame(reader)
+		if err != nil {
+			if err != io.EOF {
+				t.logger.Debugf("[TCP] read from %d failed: %s", from, err)
+			}
+			return
+		}
+
+		msg := &pb.ConsensusMessage{}
+		if err := msg.Unmarshal(payload); err != nil {
+			t.logger.Warningf("[TCP] unmarshal message from %d failed: %s", from, err)
+			continue
+		}
+
+		select {
+		case t.receiver.RecvC <- &netType.Message{From: from, Msg: msg}:
+		case <-t.close:
+			return
+		}
+	}
+}
+
+// register is used to record the endpoint of a client on its latest connection
+func (t *transportImpl) register(id uint64) *peer {
+	p := &peer{id: id, sendC: make(chan []byte, t.queueLen)}
+	t.mutex.Lock()
+	t.clients[id] = p
+	t.mutex.Unlock()
+	t.logger.Infof("[TCP] client %d connected to replica %d", id, t.id)
+	return p
+}
+
+// unregister is used to drop the endpoint of a client once its connection fails, unless it has connected again
+func (t *transportImpl) unregister(p *peer) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.clients[p.id] == p {
+		delete(t.clients, p.id)
+	}
+}
+
+// serve is used to write the queued messages to the client on its connection
+func (t *transportImpl) serve(conn net.Conn, p *peer, done chan bool) {
+	defer t.wg.Done()
+
+	for {
+		select {
+		case <-t.close:
+			return
+		case <-done:
+			return
+
+		case payload := <-p.sendC:
+			if err := writeFrame(conn, payload); err != nil {
+				t.logger.Debugf("[TCP] write to client %d failed: %s", p.id, err)
+				_ = conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// ============================================ handshake ============================================
+
+// challenge is used to authenticate the dialer of an inbound connection, a random challenge is sent to the
+// dialer and it should reply its identifier with the signature for the challenge
+func (t *transportImpl) challenge(conn net.Conn, reader io.Reader) (uint64, error) {
+	if err := conn.SetDeadline(time.Now().Add(types.HandshakeTimeout)); err != nil {
+		return 0, err
+	}
+
+	nonce := make([]byte, types.ChallengeSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return 0, err
+	}
+	if _, err := conn.Write(nonce); err != nil {
+		return 0, err
+	}
+
+	handshake, err := readFrameLimited(reader, types.MaxHandshakeSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(handshake) < 8 {
+		return 0, errors.New("handshake too short")
+	}
+	from := binary.BigEndian.Uint64(handshake[:8])
+	if from == t.id {
+		return 0, errors.New("dialed by itself")
+	}
+	// the identifiers unknown by signer, neither replicas nor clients, are rejected by the verification
+	if err := t.signer.Verify(from, handshakeDigest(nonce, from, t.id), handshake[8:]); err != nil {
+		return 0, err
+	}
+
+	return from, conn.SetDeadline(time.Time{})
+}
+
+// answer is used to reply the challenge of the replica we have dialed with the signed identifier of current
+// replica
+func (t *transportImpl) answer(conn net.Conn, to uint64) error {
+	if err := conn.SetDeadline(time.Now().Add(types.HandshakeTimeout)); err != nil {
+		return err
+	}
+
+	nonce := make([]byte, types.ChallengeSize)
+	if _, err := io.ReadFull(conn, nonce); err != nil {
+		return err
+	}
+	signature, err := t.signer.Sign(handshakeDigest(nonce, t.id, to))
+	if err != nil {
+		return err
+	}
+	handshake := make([]byte, 8, 8+len(signature))
+	binary.BigEndian.PutUint64(handshake, t.id)
+	handshake = append(handshake, signature...)
+	if err := writeFrame(conn, handshake); err != nil {
+		return err
+	}
+
+	return conn.SetDeadline(time.Time{})
+}
+
+// handshakeDigest is the content signed by the dialer, the identifiers are included so that the signature
+// cannot be used on the connections between other replicas
+func handshakeDigest(nonce []byte, from, to uint64) []byte {
+	digest := make([]byte, len(nonce)+16)
+	copy(digest, nonce)
+	binary.BigEndian.PutUint64(digest[len(nonce):], from)
+	binary.BigEndian.PutUint64(digest[len(nonce)+8:], to)
+	return digest
+}
+
+// ============================================ framing ============================================
+
+func writeFrame(w io.Writer, payload []byte) error {
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, uint32(len(payload)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	return readFrameLimited(r, types.MaxFrameSize)
+}
+
+// readFrameLimited is used to read a frame whose length should not exceed the limit, so that the memory
+// allocated for it is bounded
+func readFrameLimited(r io.Reader, limit uint32) ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size > limit {
+		return nil, errors.New("frame too large")
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
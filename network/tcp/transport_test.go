@@ -0,0 +1,211 @@
+package tcp
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Grivn/libfalanx/network/tcp/types"
+	netType "github.com/Grivn/libfalanx/network/types"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+type testLogger struct{}
+
+func (testLogger) Debug(v ...interface{})                   {}
+func (testLogger) Debugf(format string, v ...interface{})   {}
+func (testLogger) Info(v ...interface{})                    {}
+func (testLogger) Infof(format string, v ...interface{})    {}
+func (testLogger) Warning(v ...interface{})                 {}
+func (testLogger) Warningf(format string, v ...interface{}) {}
+func (testLogger) Error(v ...interface{})                   {}
+func (testLogger) Errorf(format string, v ...interface{})   {}
+
+// freeAddr is used to find an unused loopback address
+func freeAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+	return addr
+}
+
+type testKeys struct {
+	publics  map[uint64]ed25519.PublicKey
+	privates map[uint64]ed25519.PrivateKey
+}
+
+func newTestKeys(t *testing.T, n int) *testKeys {
+	keys := &testKeys{
+		publics:  make(map[uint64]ed25519.PublicKey),
+		privates: make(map[uint64]ed25519.PrivateKey),
+	}
+	for id := uint64(1); id <= uint64(n); id++ {
+		public, private, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys.publics[id] = public
+		keys.privates[id] = private
+	}
+	return keys
+}
+
+func (keys *testKeys) signer(id uint64) zcommon.Signer {
+	return zcommon.NewEd25519Signer(keys.privates[id], keys.publics)
+}
+
+func newTestTransport(t *testing.T, id uint64, peers map[uint64]string, keys *testKeys) (*transportImpl, netType.NetworkReceiver) {
+	receiver := netType.NetworkReceiver{RecvC: make(chan *netType.Message, 10)}
+	transport := NewTransport(types.Config{
+		ID:             id,
+		Peers:          peers,
+		Receiver:       receiver,
+		ReconnectDelay: 50 * time.Millisecond,
+		Signer:         keys.signer(id),
+		Logger:         testLogger{},
+	})
+	if err := transport.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return transport, receiver
+}
+
+func TestLoopback(t *testing.T) {
+	keys := newTestKeys(t, 2)
+	peers := map[uint64]string{1: freeAddr(t), 2: freeAddr(t)}
+	transport1, receiver1 := newTestTransport(t, 1, peers, keys)
+	defer transport1.Stop()
+	transport2, receiver2 := newTestTransport(t, 2, peers, keys)
+	defer transport2.Stop()
+
+	transport1.Unicast(2, &pb.ConsensusMessage{Type: pb.Type_TX_SET, Payload: []byte("from 1")})
+	expectMessage(t, receiver2, 1, "from 1")
+
+	transport2.Broadcast(&pb.ConsensusMessage{Type: pb.Type_TX_SET, Payload: []byte("from 2")})
+	expectMessage(t, receiver1, 2, "from 2")
+
+	transport1.Unicast(1, &pb.ConsensusMessage{Type: pb.Type_TX_SET, Payload: []byte("self")})
+	expectMessage(t, receiver1, 1, "self")
+}
+
+// TestClient checks that the client which never listens could receive the messages from the replicas it has
+// dialed, and the messages to a client which hasn't connected are dropped
+func TestClient(t *testing.T) {
+	keys := newTestKeys(t, 3)
+	peers := map[uint64]string{1: freeAddr(t), 2: freeAddr(t)}
+	transport1, receiver1 := newTestTransport(t, 1, peers, keys)
+	defer transport1.Stop()
+	transport2, receiver2 := newTestTransport(t, 2, peers, keys)
+	defer transport2.Stop()
+
+	transport1.Unicast(3, &pb.ConsensusMessage{Type: pb.Type_REPLY, Payload: []byte("dropped")})
+
+	client, clientReceiver := newTestTransport(t, 3, peers, keys)
+	defer client.Stop()
+	client.Broadcast(&pb.ConsensusMessage{Type: pb.Type_TX_SET, Payload: []byte("from client")})
+	expectMessage(t, receiver1, 3, "from client")
+	expectMessage(t, receiver2, 3, "from client")
+
+	transport1.Unicast(3, &pb.ConsensusMessage{Type: pb.Type_REPLY, Payload: []byte("reply 1")})
+	expectMessage(t, clientReceiver, 1, "reply 1")
+	transport2.Unicast(3, &pb.ConsensusMessage{Type: pb.Type_REPLY, Payload: []byte("reply 2")})
+	expectMessage(t, clientReceiver, 2, "reply 2")
+
+	// the replicas still receive the messages from each other on their own connections
+	transport1.Unicast(2, &pb.ConsensusMessage{Type: pb.Type_TX_SET, Payload: []byte("from 1")})
+	expectMessage(t, receiver2, 1, "from 1")
+}
+
+// TestForgedHandshake checks that a dialer cannot claim the identifier of another replica without its key
+func TestForgedHandshake(t *testing.T) {
+	keys := newTestKeys(t, 3)
+	peers := map[uint64]string{1: freeAddr(t), 2: freeAddr(t), 3: freeAddr(t)}
+	transport, receiver := newTestTransport(t, 1, peers, keys)
+	defer transport.Stop()
+
+	// replica 3 signs the challenge with its own key but claims to be replica 2
+	conn := dialTestConn(t, peers[1])
+	defer conn.Close()
+	nonce := make([]byte, types.ChallengeSize)
+	if _, err := io.ReadFull(conn, nonce); err != nil {
+		t.Fatal(err)
+	}
+	signature, err := keys.signer(3).Sign(handshakeDigest(nonce, 2, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handshake := make([]byte, 8)
+	binary.BigEndian.PutUint64(handshake, 2)
+	if err := writeFrame(conn, append(handshake, signature...)); err != nil {
+		t.Fatal(err)
+	}
+	payload, _ := (&pb.ConsensusMessage{Payload: []byte("forged")}).Marshal()
+	_ = writeFrame(conn, payload)
+
+	expectClosed(t, conn)
+	select {
+	case message := <-receiver.RecvC:
+		t.Fatalf("unexpected message %q from replica %d", message.Msg.Payload, message.From)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestOversizedHandshake checks that the handshake is rejected before allocating a large buffer for it
+func TestOversizedHandshake(t *testing.T) {
+	keys := newTestKeys(t, 2)
+	peers := map[uint64]string{1: freeAddr(t), 2: freeAddr(t)}
+	transport, _ := newTestTransport(t, 1, peers, keys)
+	defer transport.Stop()
+
+	conn := dialTestConn(t, peers[1])
+	defer conn.Close()
+	nonce := make([]byte, types.ChallengeSize)
+	if _, err := io.ReadFull(conn, nonce); err != nil {
+		t.Fatal(err)
+	}
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, types.MaxFrameSize)
+	if _, err := conn.Write(header); err != nil {
+		t.Fatal(err)
+	}
+
+	expectClosed(t, conn)
+}
+
+func dialTestConn(t *testing.T, addr string) net.Conn {
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func expectClosed(t *testing.T, conn net.Conn) {
+	if _, err := bufio.NewReader(conn).ReadByte(); err == nil {
+		t.Fatal("connection has not been closed")
+	} else if e, ok := err.(net.Error); ok && e.Timeout() {
+		t.Fatal("connection has not been closed")
+	}
+}
+
+func expectMessage(t *testing.T, receiver netType.NetworkReceiver, from uint64, payload string) {
+	select {
+	case message := <-receiver.RecvC:
+		if message.From != from || string(message.Msg.Payload) != payload {
+			t.Fatalf("expect %q from replica %d, got %q from replica %d", payload, from, message.Msg.Payload, message.From)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expect %q from replica %d, got nothing", payload, from)
+	}
+}
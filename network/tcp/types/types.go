@@ -0,0 +1,46 @@
+package types
+
+import (
+	"time"
+
+	"github.com/Grivn/libfalanx/logger"
+	netType "github.com/Grivn/libfalanx/network/types"
+	"github.com/Grivn/libfalanx/zcommon"
+)
+
+// Config is used to initiate the tcp transport
+// ID:             the identifier of current replica or client
+// Peers:          the addresses of all the replicas, including current one, struct: replica id ==> address. a
+//                 client is absent from it, and it only dials the replicas, which send the messages to it back
+//                 on its connections
+// Receiver:       deliver the messages from other replicas
+// ReconnectDelay: the interval to re-dial a peer after the connection fails
+// SendQueueLen:   the capacity of sending queue for every peer, the messages will be dropped when it is full
+// Signer:         sign the handshake of current replica and verify the ones from others, it is required
+type Config struct {
+	ID             uint64
+	Peers          map[uint64]string
+	Receiver       netType.NetworkReceiver
+	ReconnectDelay time.Duration
+	SendQueueLen   int
+	Signer         zcommon.Signer
+	Logger         logger.Logger
+}
+
+const (
+	DefaultReconnectDelay = 500 * time.Millisecond
+	DefaultSendQueueLen   = 10000
+
+	// MaxFrameSize is the maximum length of a message on the wire
+	MaxFrameSize = 64 * 1024 * 1024
+
+	// ChallengeSize is the length of the random challenge sent by the accepting replica
+	ChallengeSize = 32
+
+	// MaxHandshakeSize is the maximum length of the handshake, which contains the identifier of the dialer
+	// and its signature for the challenge
+	MaxHandshakeSize = 1024
+
+	// HandshakeTimeout is the maximum duration to finish the handshake on a new connection
+	HandshakeTimeout = 5 * time.Second
+)
@@ -1,6 +1,16 @@
 package types
 
+import pb "github.com/Grivn/libfalanx/zcommon/protos"
+
+// Message is a consensus message received from network
+// From: the replica which has sent the message, 0 if it is unknown
+type Message struct {
+	From uint64
+	Msg  *pb.ConsensusMessage
+}
+
+// NetworkReceiver is used to deliver the messages from network to falanx, the transports push the received
+// messages into RecvC and falanx processes them one by one
 type NetworkReceiver struct {
-	SequenceReq chan []byte
-	SequenceLog chan []byte
+	RecvC chan *Message
 }
@@ -0,0 +1,113 @@
+package replicasorder
+
+import (
+	"time"
+
+	"github.com/Grivn/libfalanx/replicasorder/types"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+	"github.com/gogo/protobuf/proto"
+)
+
+// checkGap is used to arm the fetch timer when the cached logs are ahead of the counter, which means some
+// logs from the replica might have been lost
+func (r *replicaOrderImpl) checkGap() {
+	if r.id == r.self || r.network == nil {
+		// the logs of current replica are delivered locally and recovered from write-ahead log
+		return
+	}
+	top := r.cache.Top()
+	if top == nil || top.Sequence <= r.recorder.Counter()+1 {
+		return
+	}
+	r.startFetchTimer()
+}
+
+func (r *replicaOrderImpl) startFetchTimer() {
+	if r.fetching {
+		return
+	}
+	r.fetching = true
+
+	counter := r.recorder.Counter()
+	go func() {
+		select {
+		case <-r.close:
+		case <-time.After(types.DefaultFetchTimeout):
+			select {
+			case <-r.close:
+			case r.timeoutC <- counter:
+			}
+		}
+	}()
+}
+
+// fetchTimeout is used to request the missing logs between the counter and the cached logs, the timer
+// will be re-armed until the gap has been filled
+func (r *replicaOrderImpl) fetchTimeout(counter uint64) {
+	r.fetching = false
+
+	top := r.cache.Top()
+	if top == nil || top.Sequence <= r.recorder.Counter()+1 {
+		return
+	}
+	if counter != r.recorder.Counter() {
+		// some logs have been ordered since the timer was armed, wait for the rest of them once again
+		r.startFetchTimer()
+		return
+	}
+
+	fetch := &pb.LogFetch{
+		ReplicaId: r.self,
+		Origin:    r.id,
+		FromSeq:   counter + 1,
+		ToSeq:     top.Sequence - 1,
+	}
+	payload, err := proto.Marshal(fetch)
+	if err != nil {
+		r.logger.Errorf("Marshal log fetch error: %s", err)
+		return
+	}
+	msg := &pb.ConsensusMessage{
+		Type:    pb.Type_LOG_FETCH,
+		Payload: payload,
+	}
+
+	if r.attempts == 0 {
+		r.logger.Warningf("[R-Fetch] fetch logs from replica %d, seq [%d, %d]", r.id, fetch.FromSeq, fetch.ToSeq)
+		r.network.Unicast(r.id, msg)
+	} else {
+		r.logger.Warningf("[R-Fetch] fetch logs of replica %d from others, seq [%d, %d], attempt %d", r.id, fetch.FromSeq, fetch.ToSeq, r.attempts+1)
+		r.network.Broadcast(msg)
+	}
+	r.attempts++
+	r.startFetchTimer()
+}
+
+// serveFetch is used to reply the stored logs to the replica which has missed them, the logs have been
+// signed by the origin replica so that they could be relayed by anyone
+func (r *replicaOrderImpl) serveFetch(fetch *pb.LogFetch) {
+	if fetch.Origin != r.id || fetch.ReplicaId == r.self || r.network == nil {
+		return
+	}
+
+	logs := r.history.GetRange(fetch.FromSeq, fetch.ToSeq, types.MaxFetchLogs)
+	if len(logs) == 0 {
+		return
+	}
+	response := &pb.LogResponse{
+		ReplicaId: r.self,
+		Origin:    r.id,
+		Logs:      logs,
+	}
+	payload, err := proto.Marshal(response)
+	if err != nil {
+		r.logger.Errorf("Marshal log response error: %s", err)
+		return
+	}
+	msg := &pb.ConsensusMessage{
+		Type:    pb.Type_LOG_RESPONSE,
+		Payload: payload,
+	}
+	r.logger.Infof("[R-Fetch] reply %d logs of replica %d to replica %d", len(logs), r.id, fetch.ReplicaId)
+	r.network.Unicast(fetch.ReplicaId, msg)
+}
@@ -2,6 +2,7 @@ package replicasorder
 
 import (
 	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
 	"github.com/Grivn/libfalanx/replicasorder/types"
 	"github.com/Grivn/libfalanx/replicasorder/utils"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
@@ -14,6 +15,21 @@ type replicaOrderImpl struct {
 	// ===========================================================================
 	cache    utils.CacheLog        // cache is used to store the logs which temporarily cannot be processed
 	recorder utils.ReplicaRecorder // recorder si used to record the counter status of particular replica
+	history  utils.LogHistory      // history is used to store the received logs to serve the fetch requests
+
+	// fetch =====================================================================
+	// self:     the identifier of current replica
+	// fetching: whether the fetch timer has been armed for the missing logs
+	// attempts: the amount of fetch requests sent for current counter, the first one will be sent to the
+	//           origin replica, and the others will be broadcast to the replicas which have relayed them
+	// timeoutC: channel used to receive the expired fetch timer
+	// fetchC:   channel used to receive the fetch requests from other replicas
+	self     uint64
+	fetching bool
+	attempts int
+	timeoutC chan uint64
+	fetchC   chan *pb.LogFetch
+	network  network.Network
 
 	// channel
 	orderC chan *pb.OrderedLog
@@ -30,7 +46,13 @@ func newReplicaOrderImpl(c types.Config) *replicaOrderImpl {
 		recvC:    c.RecvC,
 		orderC:   c.OrderC,
 		cache:    utils.NewLogCache(),
-		recorder: utils.NewReplicaRecorder(),
+		recorder: utils.NewReplicaRecorder(c.Sequence, c.Timestamp),
+		history:  utils.NewLogHistory(types.MaxHistoryLogs),
+		self:     c.Self,
+		timeoutC: make(chan uint64),
+		fetchC:   c.FetchC,
+		network:  c.Network,
+		close:    make(chan bool),
 		logger:   c.Logger,
 	}
 }
@@ -51,6 +73,12 @@ func (r *replicaOrderImpl) listenOrderedRequest() {
 
 		case log := <-r.recvC:
 			r.receiveOrderedLogs(log)
+
+		case fetch := <-r.fetchC:
+			r.serveFetch(fetch)
+
+		case counter := <-r.timeoutC:
+			r.fetchTimeout(counter)
 		}
 	}
 }
@@ -70,10 +98,19 @@ func (r *replicaOrderImpl) receiveOrderedLogs(l *pb.OrderedLog) {
 
 	// order the requests in the cache
 	r.orderCachedRequests()
+
+	// try to fetch the missing logs if there is a gap between the counter and the cached logs
+	r.checkGap()
 }
 
 // cacheRequest is used to save the requests temporarily unable to process because of its sequence number
 func (r *replicaOrderImpl) cacheRequest(l *pb.OrderedLog) {
+	r.history.Add(l)
+	if l.Sequence <= r.recorder.Counter() {
+		// the stale log would block the cache, as it could never be posted
+		r.logger.Debugf("Stale log-sequence %d from replica %d, current %d", l.Sequence, l.ReplicaId, r.recorder.Counter())
+		return
+	}
 	if r.cache.Has(l.Sequence) {
 		r.logger.Warningf("Duplicated log-sequence %d from replica", l.Sequence)
 		return
@@ -90,6 +127,7 @@ func (r *replicaOrderImpl) orderCachedRequests() uint64 {
 	for r.recorder.Check(r.cache.Top()) {
 		l := r.cache.Pop()
 		r.recorder.Update(l)
+		r.attempts = 0
 
 		r.logger.Infof("[R-Order] post log from replica %d, seq %d", l.ReplicaId, l.Sequence)
 		r.postOrderedLogs(l)
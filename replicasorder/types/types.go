@@ -1,13 +1,39 @@
 package types
 
 import (
+	"time"
+
 	"github.com/Grivn/libfalanx/logger"
+	"github.com/Grivn/libfalanx/network"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
 )
 
+// Config is used to initiate the replica order
+// Self:      the identifier of current replica, which is used to fetch the missing logs from others
+// FetchC:    channel used to receive the requests to fetch the logs of current replica order's replica
+// Network:   used to send the fetch requests and responses
+// Sequence:  the sequence number of the latest log which has been processed before restart
+// Timestamp: the timestamp of the latest log which has been processed before restart
 type Config struct {
-	ID     uint64
-	RecvC  chan *pb.OrderedLog
-	OrderC chan *pb.OrderedLog
-	Logger logger.Logger
+	ID        uint64
+	Self      uint64
+	RecvC     chan *pb.OrderedLog
+	OrderC    chan *pb.OrderedLog
+	FetchC    chan *pb.LogFetch
+	Network   network.Network
+	Sequence  uint64
+	Timestamp int64
+	Logger    logger.Logger
 }
+
+const (
+	// DefaultFetchTimeout is the duration to wait for the missing logs before fetching them
+	DefaultFetchTimeout = time.Second
+
+	// MaxFetchLogs is the maximum amount of logs in one fetch response
+	MaxFetchLogs = 100
+
+	// MaxHistoryLogs is the amount of latest logs kept to serve the fetch requests, the older logs cannot be
+	// fetched from current replica any more
+	MaxHistoryLogs = 10000
+)
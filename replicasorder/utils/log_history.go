@@ -0,0 +1,101 @@
+package utils
+
+import (
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// ================ LogHistory Interfaces ==================
+// LogHistory is used to store the latest logs received from a replica, so that current replica could serve
+// the fetch requests from the ones which have missed them
+type LogHistory interface {
+	Len() int
+	Add(l *pb.OrderedLog)
+	Get(seq uint64) *pb.OrderedLog
+	GetRange(from, to uint64, max int) []*pb.OrderedLog
+}
+
+func NewLogHistory(limit uint64) *logHistoryImpl {
+	return newLogHistoryImpl(limit)
+}
+
+func (h *logHistoryImpl) Len() int {
+	return len(h.logs)
+}
+
+func (h *logHistoryImpl) Add(l *pb.OrderedLog) {
+	h.add(l)
+}
+
+func (h *logHistoryImpl) Get(seq uint64) *pb.OrderedLog {
+	return h.logs[seq]
+}
+
+func (h *logHistoryImpl) GetRange(from, to uint64, max int) []*pb.OrderedLog {
+	return h.getRange(from, to, max)
+}
+
+type logHistoryImpl struct {
+	// logs is used to store the logs, sequence number ==> log
+	logs map[uint64]*pb.OrderedLog
+
+	// max is the largest sequence number of the received logs
+	max uint64
+
+	// floor is the smallest sequence number which could be stored, only the latest limit sequence numbers
+	// are kept so that the history won't grow with the throughput
+	floor uint64
+	limit uint64
+}
+
+func newLogHistoryImpl(limit uint64) *logHistoryImpl {
+	return &logHistoryImpl{logs: make(map[uint64]*pb.OrderedLog), limit: limit}
+}
+
+func (h *logHistoryImpl) add(l *pb.OrderedLog) {
+	if l.Sequence < h.floor {
+		return
+	}
+	if _, ok := h.logs[l.Sequence]; ok {
+		return
+	}
+	h.logs[l.Sequence] = l
+	if l.Sequence > h.max {
+		h.max = l.Sequence
+	}
+	if h.max-h.floor >= h.limit {
+		h.forget(h.max - h.limit + 1)
+	}
+}
+
+// forget is used to drop the logs before floor
+func (h *logHistoryImpl) forget(floor uint64) {
+	if floor-h.floor > uint64(len(h.logs)) {
+		for seq := range h.logs {
+			if seq < floor {
+				delete(h.logs, seq)
+			}
+		}
+	} else {
+		for seq := h.floor; seq < floor; seq++ {
+			delete(h.logs, seq)
+		}
+	}
+	h.floor = floor
+}
+
+// getRange returns the stored logs whose sequence number is in [from, to], at most max logs will be returned
+func (h *logHistoryImpl) getRange(from, to uint64, max int) []*pb.OrderedLog {
+	if from < h.floor {
+		from = h.floor
+	}
+	if to > h.max {
+		to = h.max
+	}
+	var logs []*pb.OrderedLog
+	for seq := from; seq <= to && len(logs) < max; seq++ {
+		if l, ok := h.logs[seq]; ok {
+			logs = append(logs, l)
+		}
+	}
+	return logs
+}
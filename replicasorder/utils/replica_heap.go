@@ -60,7 +60,7 @@ func (c *cacheLogImpl) Push(r *pb.OrderedLog) {
 		return
 	}
 
-	c.heap.Push(r)
+	heap.Push(c.heap, r)
 	c.presence[seq] = r
 }
 
@@ -68,7 +68,7 @@ func (c *cacheLogImpl) Pop() *pb.OrderedLog {
 	if c.heap.Len() == 0 {
 		return nil
 	}
-	r, ok := c.heap.Pop().(*pb.OrderedLog)
+	r, ok := heap.Pop(c.heap).(*pb.OrderedLog)
 	if !ok {
 		return nil
 	}
@@ -113,8 +113,5 @@ func (h *LogHeap) top() interface{} {
 	if h.Len() == 0 {
 		return nil
 	}
-	old := *h
-	n := h.Len()
-	x := old[n-1]
-	return x
+	return (*h)[0]
 }
@@ -10,8 +10,8 @@ type ReplicaRecorder interface {
 	Update(r *pb.OrderedLog)
 }
 
-func NewReplicaRecorder() *replicaRecorderImpl {
-	return newReplicaRecorderImpl()
+func NewReplicaRecorder(counter uint64, timestamp int64) *replicaRecorderImpl {
+	return newReplicaRecorderImpl(counter, timestamp)
 }
 
 func (rr *replicaRecorderImpl) Counter() uint64 {
@@ -34,8 +34,8 @@ type replicaRecorderImpl struct {
 	timestamp int64
 }
 
-func newReplicaRecorderImpl() *replicaRecorderImpl {
-	return &replicaRecorderImpl{}
+func newReplicaRecorderImpl(counter uint64, timestamp int64) *replicaRecorderImpl {
+	return &replicaRecorderImpl{counter: counter, timestamp: timestamp}
 }
 
 func (rr *replicaRecorderImpl) check(r *pb.OrderedLog) bool {
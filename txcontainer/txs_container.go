@@ -6,14 +6,10 @@ import (
 )
 
 func NewTxContainer(config types.Config) *containerImpl {
-	return &containerImpl{
-		pendingTxs: make(map[string]*pb.Transaction),
-		tools:      config.Tools,
-		logger:     config.Logger,
-	}
+	return newContainerImpl(config)
 }
-func (c *containerImpl) Add(tx *pb.Transaction) {
-	c.add(tx)
+func (c *containerImpl) Add(client uint64, tx *pb.Transaction) error {
+	return c.add(client, tx)
 }
 func (c *containerImpl) Get(txHash string) *pb.Transaction {
 	return c.get(txHash)
@@ -21,3 +17,12 @@ func (c *containerImpl) Get(txHash string) *pb.Transaction {
 func (c *containerImpl) Remove(txHash string) error {
 	return c.remove(txHash)
 }
+func (c *containerImpl) Pin(txHash string) {
+	c.pin(txHash)
+}
+func (c *containerImpl) Unpin(txHash string) {
+	c.unpin(txHash)
+}
+func (c *containerImpl) Metrics() types.Metrics {
+	return c.metrics()
+}
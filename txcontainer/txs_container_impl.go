@@ -1,7 +1,12 @@
 package txcontainer
 
 import (
+	"container/list"
 	"errors"
+	"sync"
+	"time"
+
+	"github.com/Grivn/libfalanx/txcontainer/types"
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
 
 	"github.com/Grivn/libfalanx/logger"
@@ -11,20 +16,102 @@ import (
 // Implementation ==================================================
 type containerImpl struct {
 	// pendingTxs means the transactions which have not been executed
-	pendingTxs map[string]*pb.Transaction
+	pendingTxs map[string]*txEntry
+
+	// queue contains the hash of the transactions which haven't been pinned in the order of arrival, so
+	// that the expired ones could be found from the front of it
+	queue *list.List
+
+	// pinned indicates the transactions referenced by paved batches, which should never be evicted until
+	// they have been executed. a transaction might be pinned before its payload arrives
+	pinned map[string]bool
+
+	// clients records the amount of transactions from every client, to limit them with quota
+	clients map[uint64]int
+
+	capacity int
+	quota    int
+	ttl      time.Duration
+	stats    types.Metrics
+
+	// lock is used to protect pendingTxs, the container is shared by the receiver and executor
+	lock sync.RWMutex
 
 	// essential external tools for txPool
 	tools  zcommon.Tools
 	logger logger.Logger
 }
 
-func (c *containerImpl) add(tx *pb.Transaction) {
+type txEntry struct {
+	tx      *pb.Transaction
+	client  uint64
+	arrival time.Time
+	element *list.Element
+}
+
+func newContainerImpl(config types.Config) *containerImpl {
+	capacity := config.Capacity
+	if capacity <= 0 {
+		capacity = types.DefaultCapacity
+	}
+	quota := config.ClientQuota
+	if quota <= 0 {
+		quota = types.DefaultClientQuota
+	}
+	ttl := config.TTL
+	if ttl <= 0 {
+		ttl = types.DefaultTTL
+	}
+	return &containerImpl{
+		pendingTxs: make(map[string]*txEntry),
+		queue:      list.New(),
+		pinned:     make(map[string]bool),
+		clients:    make(map[uint64]int),
+		capacity:   capacity,
+		quota:      quota,
+		ttl:        ttl,
+		tools:      config.Tools,
+		logger:     config.Logger,
+	}
+}
+
+// add is used to store the transaction from particular client, it will be rejected if the container is
+// full or the client has exceeded its quota, unless it has been pinned
+func (c *containerImpl) add(client uint64, tx *pb.Transaction) error {
 	if tx == nil {
 		c.logger.Warning("container received a nil transaction")
-		return
+		return errors.New("nil transaction")
 	}
 	txHash := c.tools.TransactionHash(tx)
-	c.pendingTxs[txHash] = tx
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.has(txHash) {
+		return nil
+	}
+	c.evictExpired(time.Now())
+
+	pinned := c.pinned[txHash]
+	if !pinned && len(c.pendingTxs) >= c.capacity {
+		c.stats.Rejected++
+		c.logger.Debugf("container is full, reject transaction %s from client %d", txHash, client)
+		return errors.New("container is full")
+	}
+	if !pinned && c.clients[client] >= c.quota {
+		c.stats.OverQuota++
+		c.logger.Debugf("client %d has exceeded the quota, reject transaction %s", client, txHash)
+		return errors.New("client has exceeded the quota")
+	}
+
+	entry := &txEntry{tx: tx, client: client, arrival: time.Now()}
+	if !pinned {
+		entry.element = c.queue.PushBack(txHash)
+	}
+	c.pendingTxs[txHash] = entry
+	c.clients[client]++
+	c.stats.Added++
+	return nil
 }
 
 func (c *containerImpl) has(txHash string) bool {
@@ -33,18 +120,104 @@ func (c *containerImpl) has(txHash string) bool {
 }
 
 func (c *containerImpl) get(txHash string) *pb.Transaction {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	if !c.has(txHash) {
-		c.logger.Debugf("Replica %d cannot find such a transaction %d", txHash)
+		c.logger.Debugf("container cannot find such a transaction %s", txHash)
 		return nil
 	}
-	return c.pendingTxs[txHash]
+	return c.pendingTxs[txHash].tx
 }
 
 func (c *containerImpl) remove(txHash string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.pinned, txHash)
 	if !c.has(txHash) {
-		c.logger.Debugf("Replica %d cannot find such a transaction %d", txHash)
+		c.logger.Debugf("container cannot find such a transaction %s", txHash)
 		return errors.New("non-existed transaction")
 	}
-	delete(c.pendingTxs, txHash)
+	c.detach(txHash)
+	c.stats.Removed++
 	return nil
 }
+
+// pin is used to protect the transaction referenced by a paved batch from eviction
+func (c *containerImpl) pin(txHash string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.pinned[txHash] = true
+	entry, ok := c.pendingTxs[txHash]
+	if !ok || entry.element == nil {
+		return
+	}
+	c.queue.Remove(entry.element)
+	entry.element = nil
+}
+
+// unpin is used to drop the protection of the transaction, its payload will be evicted as usual with the
+// arrival time, and the pin for a transaction whose payload hasn't arrived is simply dropped
+func (c *containerImpl) unpin(txHash string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.pinned[txHash] {
+		return
+	}
+	delete(c.pinned, txHash)
+	entry, ok := c.pendingTxs[txHash]
+	if !ok || entry.element != nil {
+		return
+	}
+	c.insert(txHash, entry)
+}
+
+// insert is used to put the transaction back into the queue which is ordered by arrival time
+func (c *containerImpl) insert(txHash string, entry *txEntry) {
+	for back := c.queue.Back(); back != nil; back = back.Prev() {
+		if !c.pendingTxs[back.Value.(string)].arrival.After(entry.arrival) {
+			entry.element = c.queue.InsertAfter(txHash, back)
+			return
+		}
+	}
+	entry.element = c.queue.PushFront(txHash)
+}
+
+func (c *containerImpl) metrics() types.Metrics {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.evictExpired(time.Now())
+	metrics := c.stats
+	metrics.Size = len(c.pendingTxs)
+	metrics.Pinned = len(c.pinned)
+	return metrics
+}
+
+// evictExpired is used to remove the transactions which haven't been pinned and have been kept for ttl
+func (c *containerImpl) evictExpired(now time.Time) {
+	for front := c.queue.Front(); front != nil; front = c.queue.Front() {
+		txHash := front.Value.(string)
+		if now.Sub(c.pendingTxs[txHash].arrival) < c.ttl {
+			return
+		}
+		c.logger.Debugf("container evict expired transaction %s", txHash)
+		c.detach(txHash)
+		c.stats.Expired++
+	}
+}
+
+func (c *containerImpl) detach(txHash string) {
+	entry := c.pendingTxs[txHash]
+	if entry.element != nil {
+		c.queue.Remove(entry.element)
+	}
+	c.clients[entry.client]--
+	if c.clients[entry.client] <= 0 {
+		delete(c.clients, entry.client)
+	}
+	delete(c.pendingTxs, txHash)
+}
@@ -0,0 +1,183 @@
+package txcontainer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Grivn/libfalanx/txcontainer/types"
+	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// testLogger drops the logs, the failures are reported by the tests themselves
+type testLogger struct{}
+
+func (testLogger) Debug(v ...interface{})                   {}
+func (testLogger) Debugf(format string, v ...interface{})   {}
+func (testLogger) Info(v ...interface{})                    {}
+func (testLogger) Infof(format string, v ...interface{})    {}
+func (testLogger) Warning(v ...interface{})                 {}
+func (testLogger) Warningf(format string, v ...interface{}) {}
+func (testLogger) Error(v ...interface{})                   {}
+func (testLogger) Errorf(format string, v ...interface{})   {}
+
+func newTestContainer(capacity, quota int, ttl time.Duration) *containerImpl {
+	return newContainerImpl(types.Config{
+		Capacity:    capacity,
+		ClientQuota: quota,
+		TTL:         ttl,
+		Tools:       zcommon.NewTools(pb.HashAlgorithm_HASH_UNSPECIFIED, nil),
+		Logger:      testLogger{},
+	})
+}
+
+func newTestTx(tag string, index int) *pb.Transaction {
+	return &pb.Transaction{Payload: []byte(fmt.Sprintf("%s-%d", tag, index))}
+}
+
+func TestCapacity(t *testing.T) {
+	c := newTestContainer(4, 10, time.Minute)
+	for index := 0; index < 4; index++ {
+		if err := c.Add(uint64(index+1), newTestTx("tx", index)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Add(5, newTestTx("tx", 4)); err == nil {
+		t.Fatalf("accept a tx once the container is full")
+	}
+	// the duplicated one is accepted silently
+	if err := c.Add(1, newTestTx("tx", 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Remove(c.tools.TransactionHash(newTestTx("tx", 0))); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Add(5, newTestTx("tx", 4)); err != nil {
+		t.Fatal(err)
+	}
+	if metrics := c.Metrics(); metrics.Size != 4 || metrics.Rejected != 1 || metrics.Removed != 1 {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+}
+
+func TestClientQuota(t *testing.T) {
+	c := newTestContainer(100, 2, time.Minute)
+	for index := 0; index < 2; index++ {
+		if err := c.Add(1, newTestTx("one", index)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Add(1, newTestTx("one", 2)); err == nil {
+		t.Fatalf("accept a tx once the client has exceeded its quota")
+	}
+	// the quota of one client doesn't affect the others
+	if err := c.Add(2, newTestTx("two", 0)); err != nil {
+		t.Fatal(err)
+	}
+	// the quota is released once the tx has been removed
+	if err := c.Remove(c.tools.TransactionHash(newTestTx("one", 0))); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Add(1, newTestTx("one", 2)); err != nil {
+		t.Fatal(err)
+	}
+	if metrics := c.Metrics(); metrics.OverQuota != 1 || len(c.clients) != 2 || c.clients[1] != 2 || c.clients[2] != 1 {
+		t.Fatalf("unexpected metrics %+v, clients %v", metrics, c.clients)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	c := newTestContainer(100, 100, 50*time.Millisecond)
+	if err := c.Add(1, newTestTx("old", 0)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if err := c.Add(1, newTestTx("new", 0)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	metrics := c.Metrics()
+	if metrics.Size != 1 || metrics.Expired != 1 || c.Get(c.tools.TransactionHash(newTestTx("new", 0))) == nil {
+		t.Fatalf("expect the old tx to expire, metrics %+v", metrics)
+	}
+	if len(c.clients) != 1 || c.clients[1] != 1 {
+		t.Fatalf("the quota of expired tx hasn't been released, clients %v", c.clients)
+	}
+}
+
+func TestPinning(t *testing.T) {
+	c := newTestContainer(2, 1, 50*time.Millisecond)
+	pinned := newTestTx("pinned", 0)
+	early := newTestTx("early", 0)
+	pinnedHash := c.tools.TransactionHash(pinned)
+	earlyHash := c.tools.TransactionHash(early)
+	if err := c.Add(1, pinned); err != nil {
+		t.Fatal(err)
+	}
+	c.Pin(pinnedHash)
+	if err := c.Add(2, newTestTx("other", 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the pinned tx is accepted even if the container is full and its client has exceeded the quota, the
+	// tx might be pinned before its payload arrives
+	c.Pin(earlyHash)
+	if err := c.Add(1, early); err != nil {
+		t.Fatal(err)
+	}
+
+	// the pinned ones never expire
+	time.Sleep(60 * time.Millisecond)
+	if metrics := c.Metrics(); metrics.Size != 2 || metrics.Pinned != 2 || metrics.Expired != 1 {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+
+	// the pin is dropped once the tx has been removed, and the unpinned one expires as usual
+	if err := c.Remove(pinnedHash); err != nil {
+		t.Fatal(err)
+	}
+	c.Unpin(earlyHash)
+	if metrics := c.Metrics(); metrics.Size != 0 || metrics.Pinned != 0 || metrics.Expired != 2 {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+	if len(c.clients) != 0 {
+		t.Fatalf("keep quota %v after all the txs have left", c.clients)
+	}
+
+	// the pin for a tx whose payload hasn't arrived is dropped as well
+	c.Pin(earlyHash)
+	c.Unpin(earlyHash)
+	if metrics := c.Metrics(); metrics.Pinned != 0 {
+		t.Fatalf("keep %d pins", metrics.Pinned)
+	}
+}
+
+// TestUnpinOrder checks that the unpinned tx is put back by arrival time, so that the expired ones are still
+// found from the front of the queue
+func TestUnpinOrder(t *testing.T) {
+	c := newTestContainer(100, 100, 80*time.Millisecond)
+	var hashes []string
+	for index := 0; index < 3; index++ {
+		tx := newTestTx("tx", index)
+		if err := c.Add(1, tx); err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, c.tools.TransactionHash(tx))
+		time.Sleep(30 * time.Millisecond)
+	}
+	c.Pin(hashes[0])
+	c.Unpin(hashes[0])
+
+	var order []string
+	for element := c.queue.Front(); element != nil; element = element.Next() {
+		order = append(order, element.Value.(string))
+	}
+	if fmt.Sprint(order) != fmt.Sprint(hashes) {
+		t.Fatalf("queue %v, expect %v", order, hashes)
+	}
+	if metrics := c.Metrics(); metrics.Expired != 1 || metrics.Size != 2 {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+}
@@ -1,12 +1,46 @@
 package types
 
 import (
+	"time"
+
 	"github.com/Grivn/libfalanx/logger"
 	"github.com/Grivn/libfalanx/zcommon"
 )
 
 // Config is used to initiate the instance in tx container
+// Capacity:    the maximum amount of transactions in container, DefaultCapacity will be used if it is not positive
+// ClientQuota: the maximum amount of transactions from one client, DefaultClientQuota will be used if it is
+//              not positive
+// TTL:         the transactions which haven't been pinned will be evicted once they have been kept for TTL,
+//              DefaultTTL will be used if it is not positive
 type Config struct {
-	Tools  zcommon.Tools
-	Logger logger.Logger
+	Capacity    int
+	ClientQuota int
+	TTL         time.Duration
+	Tools       zcommon.Tools
+	Logger      logger.Logger
+}
+
+const (
+	DefaultCapacity    = 100000
+	DefaultClientQuota = 10000
+	DefaultTTL         = 5 * time.Minute
+)
+
+// Metrics is the statistics of tx container
+// Size:      the amount of transactions in container
+// Pinned:    the amount of pinned transactions, which are referenced by paved batches and will never be evicted
+// Added:     the amount of transactions which have been accepted
+// Rejected:  the amount of transactions which have been rejected because the container is full
+// OverQuota: the amount of transactions which have been rejected because their client has exceeded the quota
+// Expired:   the amount of transactions which have been evicted because of TTL
+// Removed:   the amount of transactions which have been removed after execution
+type Metrics struct {
+	Size      int
+	Pinned    int
+	Added     uint64
+	Rejected  uint64
+	OverQuota uint64
+	Expired   uint64
+	Removed   uint64
 }
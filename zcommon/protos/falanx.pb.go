@@ -8,6 +8,7 @@ import (
 	proto "github.com/gogo/protobuf/proto"
 	io "io"
 	math "math"
+	math_bits "math/bits"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -19,26 +20,65 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type Type int32
 
 const (
-	Type_REQUEST_SET Type = 0
-	Type_ORDERED_REQ Type = 1
-	Type_ORDERED_LOG Type = 2
+	Type_REQUEST_SET    Type = 0
+	Type_ORDERED_REQ    Type = 1
+	Type_ORDERED_LOG    Type = 2
+	Type_BA_VOTE        Type = 3
+	Type_SUSPECT        Type = 4
+	Type_REPLY          Type = 5
+	Type_LOG_FETCH      Type = 6
+	Type_LOG_RESPONSE   Type = 7
+	Type_REQ_FETCH      Type = 8
+	Type_REQ_RESPONSE   Type = 9
+	Type_TX_SET         Type = 10
+	Type_TX_FETCH       Type = 11
+	Type_TX_RESPONSE    Type = 12
+	Type_CHECKPOINT     Type = 13
+	Type_STATE_FETCH    Type = 14
+	Type_STATE_RESPONSE Type = 15
 )
 
 var Type_name = map[int32]string{
-	0: "REQUEST_SET",
-	1: "ORDERED_REQ",
-	2: "ORDERED_LOG",
+	0:  "REQUEST_SET",
+	1:  "ORDERED_REQ",
+	2:  "ORDERED_LOG",
+	3:  "BA_VOTE",
+	4:  "SUSPECT",
+	5:  "REPLY",
+	6:  "LOG_FETCH",
+	7:  "LOG_RESPONSE",
+	8:  "REQ_FETCH",
+	9:  "REQ_RESPONSE",
+	10: "TX_SET",
+	11: "TX_FETCH",
+	12: "TX_RESPONSE",
+	13: "CHECKPOINT",
+	14: "STATE_FETCH",
+	15: "STATE_RESPONSE",
 }
 
 var Type_value = map[string]int32{
-	"REQUEST_SET": 0,
-	"ORDERED_REQ": 1,
-	"ORDERED_LOG": 2,
+	"REQUEST_SET":    0,
+	"ORDERED_REQ":    1,
+	"ORDERED_LOG":    2,
+	"BA_VOTE":        3,
+	"SUSPECT":        4,
+	"REPLY":          5,
+	"LOG_FETCH":      6,
+	"LOG_RESPONSE":   7,
+	"REQ_FETCH":      8,
+	"REQ_RESPONSE":   9,
+	"TX_SET":         10,
+	"TX_FETCH":       11,
+	"TX_RESPONSE":    12,
+	"CHECKPOINT":     13,
+	"STATE_FETCH":    14,
+	"STATE_RESPONSE": 15,
 }
 
 func (x Type) String() string {
@@ -49,6 +89,37 @@ func (Type) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_52f9c01338bf5dac, []int{0}
 }
 
+type HashAlgorithm int32
+
+const (
+	HashAlgorithm_HASH_UNSPECIFIED HashAlgorithm = 0
+	HashAlgorithm_SHA256           HashAlgorithm = 1
+	HashAlgorithm_BLAKE2B          HashAlgorithm = 2
+	HashAlgorithm_SHA3             HashAlgorithm = 3
+)
+
+var HashAlgorithm_name = map[int32]string{
+	0: "HASH_UNSPECIFIED",
+	1: "SHA256",
+	2: "BLAKE2B",
+	3: "SHA3",
+}
+
+var HashAlgorithm_value = map[string]int32{
+	"HASH_UNSPECIFIED": 0,
+	"SHA256":           1,
+	"BLAKE2B":          2,
+	"SHA3":             3,
+}
+
+func (x HashAlgorithm) String() string {
+	return proto.EnumName(HashAlgorithm_name, int32(x))
+}
+
+func (HashAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{1}
+}
+
 type ConsensusMessage struct {
 	Type    Type   `protobuf:"varint,1,opt,name=type,proto3,enum=falanxpb.Type" json:"type,omitempty"`
 	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
@@ -68,7 +139,7 @@ func (m *ConsensusMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return xxx_messageInfo_ConsensusMessage.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -102,7 +173,8 @@ func (m *ConsensusMessage) GetPayload() []byte {
 }
 
 type Transaction struct {
-	Payload []byte `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	Payload  []byte    `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	Reconfig *Reconfig `protobuf:"bytes,2,opt,name=reconfig,proto3" json:"reconfig,omitempty"`
 }
 
 func (m *Transaction) Reset()         { *m = Transaction{} }
@@ -119,7 +191,7 @@ func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -145,6 +217,81 @@ func (m *Transaction) GetPayload() []byte {
 	return nil
 }
 
+func (m *Transaction) GetReconfig() *Reconfig {
+	if m != nil {
+		return m.Reconfig
+	}
+	return nil
+}
+
+type Reconfig struct {
+	Epoch     uint64   `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Replicas  []uint64 `protobuf:"varint,2,rep,packed,name=replicas,proto3" json:"replicas,omitempty"`
+	ReplicaId uint64   `protobuf:"varint,3,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	Signature []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *Reconfig) Reset()         { *m = Reconfig{} }
+func (m *Reconfig) String() string { return proto.CompactTextString(m) }
+func (*Reconfig) ProtoMessage()    {}
+func (*Reconfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{2}
+}
+func (m *Reconfig) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Reconfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Reconfig.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Reconfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Reconfig.Merge(m, src)
+}
+func (m *Reconfig) XXX_Size() int {
+	return m.Size()
+}
+func (m *Reconfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_Reconfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Reconfig proto.InternalMessageInfo
+
+func (m *Reconfig) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *Reconfig) GetReplicas() []uint64 {
+	if m != nil {
+		return m.Replicas
+	}
+	return nil
+}
+
+func (m *Reconfig) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
+	}
+	return 0
+}
+
+func (m *Reconfig) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 type RequestSet struct {
 	Requests []*Transaction `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
 }
@@ -153,7 +300,7 @@ func (m *RequestSet) Reset()         { *m = RequestSet{} }
 func (m *RequestSet) String() string { return proto.CompactTextString(m) }
 func (*RequestSet) ProtoMessage()    {}
 func (*RequestSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_52f9c01338bf5dac, []int{2}
+	return fileDescriptor_52f9c01338bf5dac, []int{3}
 }
 func (m *RequestSet) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -163,7 +310,7 @@ func (m *RequestSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return xxx_messageInfo_RequestSet.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -190,17 +337,19 @@ func (m *RequestSet) GetRequests() []*Transaction {
 }
 
 type OrderedLog struct {
-	ReplicaId uint64 `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
-	Sequence  uint64 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
-	TxHash    string `protobuf:"bytes,3,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
-	Timestamp int64  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ReplicaId     uint64        `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	Sequence      uint64        `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	TxHash        string        `protobuf:"bytes,3,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Timestamp     int64         `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature     []byte        `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	HashAlgorithm HashAlgorithm `protobuf:"varint,6,opt,name=hash_algorithm,json=hashAlgorithm,proto3,enum=falanxpb.HashAlgorithm" json:"hash_algorithm,omitempty"`
 }
 
 func (m *OrderedLog) Reset()         { *m = OrderedLog{} }
 func (m *OrderedLog) String() string { return proto.CompactTextString(m) }
 func (*OrderedLog) ProtoMessage()    {}
 func (*OrderedLog) Descriptor() ([]byte, []int) {
-	return fileDescriptor_52f9c01338bf5dac, []int{3}
+	return fileDescriptor_52f9c01338bf5dac, []int{4}
 }
 func (m *OrderedLog) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -210,7 +359,7 @@ func (m *OrderedLog) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return xxx_messageInfo_OrderedLog.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -257,18 +406,34 @@ func (m *OrderedLog) GetTimestamp() int64 {
 	return 0
 }
 
+func (m *OrderedLog) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *OrderedLog) GetHashAlgorithm() HashAlgorithm {
+	if m != nil {
+		return m.HashAlgorithm
+	}
+	return HashAlgorithm_HASH_UNSPECIFIED
+}
+
 type OrderedReq struct {
-	ClientId   uint64   `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
-	Sequence   uint64   `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
-	TxHashList []string `protobuf:"bytes,3,rep,name=tx_hash_list,json=txHashList,proto3" json:"tx_hash_list,omitempty"`
-	Timestamp  int64    `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ClientId      uint64        `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Sequence      uint64        `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	TxHashList    []string      `protobuf:"bytes,3,rep,name=tx_hash_list,json=txHashList,proto3" json:"tx_hash_list,omitempty"`
+	Timestamp     int64         `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature     []byte        `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	HashAlgorithm HashAlgorithm `protobuf:"varint,6,opt,name=hash_algorithm,json=hashAlgorithm,proto3,enum=falanxpb.HashAlgorithm" json:"hash_algorithm,omitempty"`
 }
 
 func (m *OrderedReq) Reset()         { *m = OrderedReq{} }
 func (m *OrderedReq) String() string { return proto.CompactTextString(m) }
 func (*OrderedReq) ProtoMessage()    {}
 func (*OrderedReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_52f9c01338bf5dac, []int{4}
+	return fileDescriptor_52f9c01338bf5dac, []int{5}
 }
 func (m *OrderedReq) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -278,7 +443,7 @@ func (m *OrderedReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return xxx_messageInfo_OrderedReq.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -325,16 +490,108 @@ func (m *OrderedReq) GetTimestamp() int64 {
 	return 0
 }
 
+func (m *OrderedReq) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *OrderedReq) GetHashAlgorithm() HashAlgorithm {
+	if m != nil {
+		return m.HashAlgorithm
+	}
+	return HashAlgorithm_HASH_UNSPECIFIED
+}
+
+type BaVote struct {
+	ReplicaId       uint64   `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	TxHash          string   `protobuf:"bytes,2,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	MissingReplicas []uint64 `protobuf:"varint,3,rep,packed,name=missing_replicas,json=missingReplicas,proto3" json:"missing_replicas,omitempty"`
+	Signature       []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	Seq             uint64   `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`
+}
+
+func (m *BaVote) Reset()         { *m = BaVote{} }
+func (m *BaVote) String() string { return proto.CompactTextString(m) }
+func (*BaVote) ProtoMessage()    {}
+func (*BaVote) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{6}
+}
+func (m *BaVote) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BaVote) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BaVote.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BaVote) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BaVote.Merge(m, src)
+}
+func (m *BaVote) XXX_Size() int {
+	return m.Size()
+}
+func (m *BaVote) XXX_DiscardUnknown() {
+	xxx_messageInfo_BaVote.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BaVote proto.InternalMessageInfo
+
+func (m *BaVote) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
+	}
+	return 0
+}
+
+func (m *BaVote) GetTxHash() string {
+	if m != nil {
+		return m.TxHash
+	}
+	return ""
+}
+
+func (m *BaVote) GetMissingReplicas() []uint64 {
+	if m != nil {
+		return m.MissingReplicas
+	}
+	return nil
+}
+
+func (m *BaVote) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *BaVote) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
 type Suspect struct {
 	ReplicaId uint64 `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
 	MaliceId  uint64 `protobuf:"varint,2,opt,name=malice_id,json=maliceId,proto3" json:"malice_id,omitempty"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	Seq       uint64 `protobuf:"varint,4,opt,name=seq,proto3" json:"seq,omitempty"`
 }
 
 func (m *Suspect) Reset()         { *m = Suspect{} }
 func (m *Suspect) String() string { return proto.CompactTextString(m) }
 func (*Suspect) ProtoMessage()    {}
 func (*Suspect) Descriptor() ([]byte, []int) {
-	return fileDescriptor_52f9c01338bf5dac, []int{5}
+	return fileDescriptor_52f9c01338bf5dac, []int{7}
 }
 func (m *Suspect) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -344,7 +601,7 @@ func (m *Suspect) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return xxx_messageInfo_Suspect.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -377,18 +634,33 @@ func (m *Suspect) GetMaliceId() uint64 {
 	return 0
 }
 
+func (m *Suspect) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Suspect) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
 type Reply struct {
 	ReplicaId uint64 `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
 	ClientId  uint64 `protobuf:"varint,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	TxHash    string `protobuf:"bytes,3,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
 	Result    []byte `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
 func (m *Reply) Reset()         { *m = Reply{} }
 func (m *Reply) String() string { return proto.CompactTextString(m) }
 func (*Reply) ProtoMessage()    {}
 func (*Reply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_52f9c01338bf5dac, []int{6}
+	return fileDescriptor_52f9c01338bf5dac, []int{8}
 }
 func (m *Reply) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -398,7 +670,7 @@ func (m *Reply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return xxx_messageInfo_Reply.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -445,440 +717,5347 @@ func (m *Reply) GetResult() []byte {
 	return nil
 }
 
-func init() {
-	proto.RegisterEnum("falanxpb.Type", Type_name, Type_value)
-	proto.RegisterType((*ConsensusMessage)(nil), "falanxpb.consensus_message")
-	proto.RegisterType((*Transaction)(nil), "falanxpb.Transaction")
-	proto.RegisterType((*RequestSet)(nil), "falanxpb.request_set")
-	proto.RegisterType((*OrderedLog)(nil), "falanxpb.ordered_log")
-	proto.RegisterType((*OrderedReq)(nil), "falanxpb.ordered_req")
-	proto.RegisterType((*Suspect)(nil), "falanxpb.suspect")
-	proto.RegisterType((*Reply)(nil), "falanxpb.reply")
+func (m *Reply) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
 }
 
-func init() { proto.RegisterFile("falanx.proto", fileDescriptor_52f9c01338bf5dac) }
-
-var fileDescriptor_52f9c01338bf5dac = []byte{
-	// 433 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0x4f, 0x6f, 0xd3, 0x40,
-	0x10, 0xc5, 0xb3, 0xb1, 0x49, 0xe2, 0x71, 0x54, 0xc2, 0x4a, 0x80, 0x45, 0xc1, 0xb2, 0x7c, 0xc1,
-	0xe2, 0x10, 0x89, 0x72, 0xe2, 0x86, 0x50, 0x2d, 0xa8, 0x54, 0xa9, 0xca, 0x36, 0x9c, 0xad, 0xad,
-	0x3d, 0x34, 0x96, 0x1c, 0xdb, 0xdd, 0xd9, 0x48, 0xc9, 0x89, 0x1b, 0x67, 0x3e, 0x16, 0xc7, 0x1e,
-	0x39, 0xa2, 0xe4, 0x8b, 0x20, 0xff, 0x69, 0x1d, 0x90, 0x50, 0x7a, 0x7c, 0x6f, 0xdf, 0xee, 0xfb,
-	0xed, 0x68, 0x60, 0xfc, 0x55, 0x66, 0x32, 0x5f, 0x4f, 0x4b, 0x55, 0xe8, 0x82, 0x8f, 0x1a, 0x55,
-	0x5e, 0xf9, 0x33, 0x78, 0x12, 0x17, 0x39, 0x61, 0x4e, 0x2b, 0x8a, 0x96, 0x48, 0x24, 0xaf, 0x91,
-	0xfb, 0x60, 0xea, 0x4d, 0x89, 0x0e, 0xf3, 0x58, 0x70, 0x74, 0x72, 0x34, 0xbd, 0x4b, 0x4f, 0xe7,
-	0x9b, 0x12, 0x45, 0x7d, 0xc6, 0x1d, 0x18, 0x96, 0x72, 0x93, 0x15, 0x32, 0x71, 0xfa, 0x1e, 0x0b,
-	0xc6, 0xe2, 0x4e, 0xfa, 0xaf, 0xc1, 0x9e, 0x2b, 0x99, 0x93, 0x8c, 0x75, 0x5a, 0xe4, 0xfb, 0x41,
-	0xf6, 0x77, 0xf0, 0x03, 0xd8, 0x0a, 0x6f, 0x56, 0x48, 0x3a, 0x22, 0xd4, 0xfc, 0x2d, 0x8c, 0x5a,
-	0x49, 0x0e, 0xf3, 0x8c, 0xc0, 0x3e, 0x79, 0xba, 0xd7, 0xdc, 0xbd, 0x28, 0xee, 0x63, 0xfe, 0x37,
-	0xb0, 0x0b, 0x95, 0xa0, 0xc2, 0x24, 0xca, 0x8a, 0x6b, 0xfe, 0x0a, 0x40, 0x61, 0x99, 0xa5, 0xb1,
-	0x8c, 0xd2, 0xa6, 0xcd, 0x14, 0x56, 0xeb, 0x9c, 0x25, 0xfc, 0x05, 0x8c, 0xa8, 0xba, 0x99, 0xc7,
-	0x58, 0x33, 0x9b, 0xe2, 0x5e, 0xf3, 0xe7, 0x30, 0xd4, 0xeb, 0x68, 0x21, 0x69, 0xe1, 0x18, 0x1e,
-	0x0b, 0x2c, 0x31, 0xd0, 0xeb, 0xcf, 0x92, 0x16, 0xfc, 0x25, 0x58, 0x3a, 0x5d, 0x22, 0x69, 0xb9,
-	0x2c, 0x1d, 0xd3, 0x63, 0x81, 0x21, 0x3a, 0xc3, 0xff, 0xce, 0x3a, 0x02, 0x85, 0x37, 0xfc, 0x18,
-	0xac, 0x38, 0x4b, 0x31, 0xd7, 0x1d, 0xc0, 0xa8, 0x31, 0x0e, 0xf4, 0x7b, 0x30, 0x6e, 0xfb, 0xa3,
-	0x2c, 0x25, 0xed, 0x18, 0x9e, 0x11, 0x58, 0x02, 0x1a, 0x88, 0xf3, 0x94, 0xf4, 0x01, 0x90, 0x10,
-	0x86, 0xb4, 0xa2, 0x12, 0x63, 0x7d, 0x68, 0x0a, 0xc7, 0x60, 0x2d, 0x65, 0x96, 0xc6, 0x58, 0x9d,
-	0xb6, 0x18, 0x8d, 0x71, 0x96, 0xf8, 0x1a, 0x1e, 0x55, 0xc9, 0xcd, 0x03, 0x1e, 0xe9, 0xfe, 0xd9,
-	0xff, 0xe7, 0x9f, 0xff, 0x9d, 0xe5, 0x33, 0x18, 0x28, 0xa4, 0x55, 0xa6, 0x6b, 0xfe, 0xb1, 0x68,
-	0xd5, 0x9b, 0xf7, 0x60, 0x56, 0x9b, 0xc5, 0x1f, 0x83, 0x2d, 0xc2, 0xd9, 0x97, 0xf0, 0x72, 0x1e,
-	0x5d, 0x86, 0xf3, 0x49, 0xaf, 0x32, 0x2e, 0xc4, 0x69, 0x28, 0xc2, 0xd3, 0x48, 0x84, 0xb3, 0x09,
-	0xdb, 0x37, 0xce, 0x2f, 0x3e, 0x4d, 0xfa, 0x1f, 0x9d, 0x9f, 0x5b, 0x97, 0xdd, 0x6e, 0x5d, 0xf6,
-	0x7b, 0xeb, 0xb2, 0x1f, 0x3b, 0xb7, 0x77, 0xbb, 0x73, 0x7b, 0xbf, 0x76, 0x6e, 0xef, 0x6a, 0x50,
-	0xaf, 0xfa, 0xbb, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x0b, 0xe8, 0x11, 0xbf, 0xfa, 0x02, 0x00,
-	0x00,
+type LogFetch struct {
+	ReplicaId uint64 `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	Origin    uint64 `protobuf:"varint,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	FromSeq   uint64 `protobuf:"varint,3,opt,name=from_seq,json=fromSeq,proto3" json:"from_seq,omitempty"`
+	ToSeq     uint64 `protobuf:"varint,4,opt,name=to_seq,json=toSeq,proto3" json:"to_seq,omitempty"`
 }
 
-func (m *ConsensusMessage) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *LogFetch) Reset()         { *m = LogFetch{} }
+func (m *LogFetch) String() string { return proto.CompactTextString(m) }
+func (*LogFetch) ProtoMessage()    {}
+func (*LogFetch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{9}
+}
+func (m *LogFetch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LogFetch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LogFetch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return dAtA[:n], nil
+}
+func (m *LogFetch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogFetch.Merge(m, src)
+}
+func (m *LogFetch) XXX_Size() int {
+	return m.Size()
+}
+func (m *LogFetch) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogFetch.DiscardUnknown(m)
 }
 
-func (m *ConsensusMessage) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Type != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.Type))
-	}
-	if len(m.Payload) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Payload)))
-		i += copy(dAtA[i:], m.Payload)
+var xxx_messageInfo_LogFetch proto.InternalMessageInfo
+
+func (m *LogFetch) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
 	}
-	return i, nil
+	return 0
 }
 
-func (m *Transaction) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *LogFetch) GetOrigin() uint64 {
+	if m != nil {
+		return m.Origin
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Transaction) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Payload) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Payload)))
-		i += copy(dAtA[i:], m.Payload)
+func (m *LogFetch) GetFromSeq() uint64 {
+	if m != nil {
+		return m.FromSeq
 	}
-	return i, nil
+	return 0
 }
 
-func (m *RequestSet) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *LogFetch) GetToSeq() uint64 {
+	if m != nil {
+		return m.ToSeq
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *RequestSet) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Requests) > 0 {
-		for _, msg := range m.Requests {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintFalanx(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
-	}
-	return i, nil
+type LogResponse struct {
+	ReplicaId uint64        `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	Origin    uint64        `protobuf:"varint,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	Logs      []*OrderedLog `protobuf:"bytes,3,rep,name=logs,proto3" json:"logs,omitempty"`
 }
 
-func (m *OrderedLog) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *LogResponse) Reset()         { *m = LogResponse{} }
+func (m *LogResponse) String() string { return proto.CompactTextString(m) }
+func (*LogResponse) ProtoMessage()    {}
+func (*LogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{10}
+}
+func (m *LogResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LogResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return dAtA[:n], nil
+}
+func (m *LogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogResponse.Merge(m, src)
+}
+func (m *LogResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *LogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogResponse.DiscardUnknown(m)
 }
 
-func (m *OrderedLog) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.ReplicaId != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
-	}
-	if m.Sequence != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.Sequence))
-	}
-	if len(m.TxHash) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(len(m.TxHash)))
-		i += copy(dAtA[i:], m.TxHash)
-	}
-	if m.Timestamp != 0 {
-		dAtA[i] = 0x20
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.Timestamp))
+var xxx_messageInfo_LogResponse proto.InternalMessageInfo
+
+func (m *LogResponse) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
 	}
-	return i, nil
+	return 0
 }
 
-func (m *OrderedReq) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *LogResponse) GetOrigin() uint64 {
+	if m != nil {
+		return m.Origin
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *OrderedReq) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.ClientId != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.ClientId))
-	}
-	if m.Sequence != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.Sequence))
+func (m *LogResponse) GetLogs() []*OrderedLog {
+	if m != nil {
+		return m.Logs
 	}
-	if len(m.TxHashList) > 0 {
-		for _, s := range m.TxHashList {
-			dAtA[i] = 0x1a
-			i++
-			l = len(s)
-			for l >= 1<<7 {
-				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
-				l >>= 7
-				i++
-			}
-			dAtA[i] = uint8(l)
-			i++
-			i += copy(dAtA[i:], s)
+	return nil
+}
+
+type ReqFetch struct {
+	ReplicaId uint64 `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	ClientId  uint64 `protobuf:"varint,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	FromSeq   uint64 `protobuf:"varint,3,opt,name=from_seq,json=fromSeq,proto3" json:"from_seq,omitempty"`
+	ToSeq     uint64 `protobuf:"varint,4,opt,name=to_seq,json=toSeq,proto3" json:"to_seq,omitempty"`
+}
+
+func (m *ReqFetch) Reset()         { *m = ReqFetch{} }
+func (m *ReqFetch) String() string { return proto.CompactTextString(m) }
+func (*ReqFetch) ProtoMessage()    {}
+func (*ReqFetch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{11}
+}
+func (m *ReqFetch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReqFetch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReqFetch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if m.Timestamp != 0 {
-		dAtA[i] = 0x20
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.Timestamp))
-	}
-	return i, nil
+}
+func (m *ReqFetch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqFetch.Merge(m, src)
+}
+func (m *ReqFetch) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReqFetch) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqFetch.DiscardUnknown(m)
 }
 
-func (m *Suspect) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ReqFetch proto.InternalMessageInfo
+
+func (m *ReqFetch) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Suspect) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.ReplicaId != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
-	}
-	if m.MaliceId != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.MaliceId))
+func (m *ReqFetch) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
 	}
-	return i, nil
+	return 0
 }
 
-func (m *Reply) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *ReqFetch) GetFromSeq() uint64 {
+	if m != nil {
+		return m.FromSeq
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Reply) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.ReplicaId != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
-	}
-	if m.ClientId != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(m.ClientId))
-	}
-	if len(m.TxHash) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(len(m.TxHash)))
-		i += copy(dAtA[i:], m.TxHash)
-	}
-	if len(m.Result) > 0 {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Result)))
-		i += copy(dAtA[i:], m.Result)
+func (m *ReqFetch) GetToSeq() uint64 {
+	if m != nil {
+		return m.ToSeq
 	}
-	return i, nil
+	return 0
 }
 
-func encodeVarintFalanx(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+type ReqResponse struct {
+	ReplicaId uint64        `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	ClientId  uint64        `protobuf:"varint,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Reqs      []*OrderedReq `protobuf:"bytes,3,rep,name=reqs,proto3" json:"reqs,omitempty"`
+	Absent    []uint64      `protobuf:"varint,4,rep,packed,name=absent,proto3" json:"absent,omitempty"`
+	Signature []byte        `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
 }
-func (m *ConsensusMessage) Size() (n int) {
-	if m == nil {
-		return 0
+
+func (m *ReqResponse) Reset()         { *m = ReqResponse{} }
+func (m *ReqResponse) String() string { return proto.CompactTextString(m) }
+func (*ReqResponse) ProtoMessage()    {}
+func (*ReqResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{12}
+}
+func (m *ReqResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReqResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReqResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	var l int
-	_ = l
-	if m.Type != 0 {
-		n += 1 + sovFalanx(uint64(m.Type))
+}
+func (m *ReqResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqResponse.Merge(m, src)
+}
+func (m *ReqResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReqResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqResponse proto.InternalMessageInfo
+
+func (m *ReqResponse) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
 	}
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovFalanx(uint64(l))
+	return 0
+}
+
+func (m *ReqResponse) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
 	}
-	return n
+	return 0
 }
 
-func (m *Transaction) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ReqResponse) GetReqs() []*OrderedReq {
+	if m != nil {
+		return m.Reqs
 	}
-	var l int
-	_ = l
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovFalanx(uint64(l))
+	return nil
+}
+
+func (m *ReqResponse) GetAbsent() []uint64 {
+	if m != nil {
+		return m.Absent
 	}
-	return n
+	return nil
 }
 
-func (m *RequestSet) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ReqResponse) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
 	}
-	var l int
-	_ = l
-	if len(m.Requests) > 0 {
-		for _, e := range m.Requests {
-			l = e.Size()
-			n += 1 + l + sovFalanx(uint64(l))
+	return nil
+}
+
+type TxSet struct {
+	ReplicaId uint64         `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	Txs       []*Transaction `protobuf:"bytes,2,rep,name=txs,proto3" json:"txs,omitempty"`
+	Signature []byte         `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *TxSet) Reset()         { *m = TxSet{} }
+func (m *TxSet) String() string { return proto.CompactTextString(m) }
+func (*TxSet) ProtoMessage()    {}
+func (*TxSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{13}
+}
+func (m *TxSet) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TxSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TxSet.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *TxSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxSet.Merge(m, src)
+}
+func (m *TxSet) XXX_Size() int {
+	return m.Size()
+}
+func (m *TxSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxSet.DiscardUnknown(m)
 }
 
-func (m *OrderedLog) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.ReplicaId != 0 {
-		n += 1 + sovFalanx(uint64(m.ReplicaId))
-	}
-	if m.Sequence != 0 {
-		n += 1 + sovFalanx(uint64(m.Sequence))
+var xxx_messageInfo_TxSet proto.InternalMessageInfo
+
+func (m *TxSet) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
 	}
-	l = len(m.TxHash)
-	if l > 0 {
-		n += 1 + l + sovFalanx(uint64(l))
+	return 0
+}
+
+func (m *TxSet) GetTxs() []*Transaction {
+	if m != nil {
+		return m.Txs
 	}
-	if m.Timestamp != 0 {
-		n += 1 + sovFalanx(uint64(m.Timestamp))
+	return nil
+}
+
+func (m *TxSet) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
 	}
-	return n
+	return nil
 }
 
-func (m *OrderedReq) Size() (n int) {
-	if m == nil {
-		return 0
+type TxFetch struct {
+	ReplicaId uint64   `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	TxHashes  []string `protobuf:"bytes,2,rep,name=tx_hashes,json=txHashes,proto3" json:"tx_hashes,omitempty"`
+}
+
+func (m *TxFetch) Reset()         { *m = TxFetch{} }
+func (m *TxFetch) String() string { return proto.CompactTextString(m) }
+func (*TxFetch) ProtoMessage()    {}
+func (*TxFetch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{14}
+}
+func (m *TxFetch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TxFetch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TxFetch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	var l int
+}
+func (m *TxFetch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxFetch.Merge(m, src)
+}
+func (m *TxFetch) XXX_Size() int {
+	return m.Size()
+}
+func (m *TxFetch) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxFetch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxFetch proto.InternalMessageInfo
+
+func (m *TxFetch) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
+	}
+	return 0
+}
+
+func (m *TxFetch) GetTxHashes() []string {
+	if m != nil {
+		return m.TxHashes
+	}
+	return nil
+}
+
+type PendingPair struct {
+	Former string `protobuf:"bytes,1,opt,name=former,proto3" json:"former,omitempty"`
+	Latter string `protobuf:"bytes,2,opt,name=latter,proto3" json:"latter,omitempty"`
+}
+
+func (m *PendingPair) Reset()         { *m = PendingPair{} }
+func (m *PendingPair) String() string { return proto.CompactTextString(m) }
+func (*PendingPair) ProtoMessage()    {}
+func (*PendingPair) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{15}
+}
+func (m *PendingPair) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PendingPair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PendingPair.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PendingPair) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingPair.Merge(m, src)
+}
+func (m *PendingPair) XXX_Size() int {
+	return m.Size()
+}
+func (m *PendingPair) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingPair.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingPair proto.InternalMessageInfo
+
+func (m *PendingPair) GetFormer() string {
+	if m != nil {
+		return m.Former
+	}
+	return ""
+}
+
+func (m *PendingPair) GetLatter() string {
+	if m != nil {
+		return m.Latter
+	}
+	return ""
+}
+
+type Blacklisted struct {
+	ReplicaId  uint64 `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	ReadmitSeq uint64 `protobuf:"varint,2,opt,name=readmit_seq,json=readmitSeq,proto3" json:"readmit_seq,omitempty"`
+}
+
+func (m *Blacklisted) Reset()         { *m = Blacklisted{} }
+func (m *Blacklisted) String() string { return proto.CompactTextString(m) }
+func (*Blacklisted) ProtoMessage()    {}
+func (*Blacklisted) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{16}
+}
+func (m *Blacklisted) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Blacklisted) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Blacklisted.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Blacklisted) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Blacklisted.Merge(m, src)
+}
+func (m *Blacklisted) XXX_Size() int {
+	return m.Size()
+}
+func (m *Blacklisted) XXX_DiscardUnknown() {
+	xxx_messageInfo_Blacklisted.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Blacklisted proto.InternalMessageInfo
+
+func (m *Blacklisted) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
+	}
+	return 0
+}
+
+func (m *Blacklisted) GetReadmitSeq() uint64 {
+	if m != nil {
+		return m.ReadmitSeq
+	}
+	return 0
+}
+
+type FilterSnapshot struct {
+	BatchSeq  uint64         `protobuf:"varint,1,opt,name=batch_seq,json=batchSeq,proto3" json:"batch_seq,omitempty"`
+	Executed  []string       `protobuf:"bytes,2,rep,name=executed,proto3" json:"executed,omitempty"`
+	Verified  []string       `protobuf:"bytes,3,rep,name=verified,proto3" json:"verified,omitempty"`
+	Blacklist []*Blacklisted `protobuf:"bytes,4,rep,name=blacklist,proto3" json:"blacklist,omitempty"`
+	Logs      []*OrderedLog  `protobuf:"bytes,5,rep,name=logs,proto3" json:"logs,omitempty"`
+	Pending   []*PendingPair `protobuf:"bytes,6,rep,name=pending,proto3" json:"pending,omitempty"`
+	Progress  []*OrderedLog  `protobuf:"bytes,7,rep,name=progress,proto3" json:"progress,omitempty"`
+	StableSeq uint64         `protobuf:"varint,8,opt,name=stable_seq,json=stableSeq,proto3" json:"stable_seq,omitempty"`
+}
+
+func (m *FilterSnapshot) Reset()         { *m = FilterSnapshot{} }
+func (m *FilterSnapshot) String() string { return proto.CompactTextString(m) }
+func (*FilterSnapshot) ProtoMessage()    {}
+func (*FilterSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{17}
+}
+func (m *FilterSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FilterSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FilterSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FilterSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilterSnapshot.Merge(m, src)
+}
+func (m *FilterSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *FilterSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilterSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilterSnapshot proto.InternalMessageInfo
+
+func (m *FilterSnapshot) GetBatchSeq() uint64 {
+	if m != nil {
+		return m.BatchSeq
+	}
+	return 0
+}
+
+func (m *FilterSnapshot) GetExecuted() []string {
+	if m != nil {
+		return m.Executed
+	}
+	return nil
+}
+
+func (m *FilterSnapshot) GetVerified() []string {
+	if m != nil {
+		return m.Verified
+	}
+	return nil
+}
+
+func (m *FilterSnapshot) GetBlacklist() []*Blacklisted {
+	if m != nil {
+		return m.Blacklist
+	}
+	return nil
+}
+
+func (m *FilterSnapshot) GetLogs() []*OrderedLog {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+func (m *FilterSnapshot) GetPending() []*PendingPair {
+	if m != nil {
+		return m.Pending
+	}
+	return nil
+}
+
+func (m *FilterSnapshot) GetProgress() []*OrderedLog {
+	if m != nil {
+		return m.Progress
+	}
+	return nil
+}
+
+func (m *FilterSnapshot) GetStableSeq() uint64 {
+	if m != nil {
+		return m.StableSeq
+	}
+	return 0
+}
+
+type Checkpoint struct {
+	ReplicaId uint64 `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	Seq       uint64 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	Digest    string `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *Checkpoint) Reset()         { *m = Checkpoint{} }
+func (m *Checkpoint) String() string { return proto.CompactTextString(m) }
+func (*Checkpoint) ProtoMessage()    {}
+func (*Checkpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{18}
+}
+func (m *Checkpoint) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Checkpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Checkpoint.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Checkpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Checkpoint.Merge(m, src)
+}
+func (m *Checkpoint) XXX_Size() int {
+	return m.Size()
+}
+func (m *Checkpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_Checkpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Checkpoint proto.InternalMessageInfo
+
+func (m *Checkpoint) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
+	}
+	return 0
+}
+
+func (m *Checkpoint) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *Checkpoint) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+func (m *Checkpoint) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type StableCheckpoint struct {
+	Seq    uint64        `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Digest string        `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	Proofs []*Checkpoint `protobuf:"bytes,3,rep,name=proofs,proto3" json:"proofs,omitempty"`
+}
+
+func (m *StableCheckpoint) Reset()         { *m = StableCheckpoint{} }
+func (m *StableCheckpoint) String() string { return proto.CompactTextString(m) }
+func (*StableCheckpoint) ProtoMessage()    {}
+func (*StableCheckpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{19}
+}
+func (m *StableCheckpoint) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StableCheckpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StableCheckpoint.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StableCheckpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StableCheckpoint.Merge(m, src)
+}
+func (m *StableCheckpoint) XXX_Size() int {
+	return m.Size()
+}
+func (m *StableCheckpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_StableCheckpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StableCheckpoint proto.InternalMessageInfo
+
+func (m *StableCheckpoint) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *StableCheckpoint) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+func (m *StableCheckpoint) GetProofs() []*Checkpoint {
+	if m != nil {
+		return m.Proofs
+	}
+	return nil
+}
+
+type ExecutedBatch struct {
+	Seq      uint64   `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	TxHashes []string `protobuf:"bytes,2,rep,name=tx_hashes,json=txHashes,proto3" json:"tx_hashes,omitempty"`
+}
+
+func (m *ExecutedBatch) Reset()         { *m = ExecutedBatch{} }
+func (m *ExecutedBatch) String() string { return proto.CompactTextString(m) }
+func (*ExecutedBatch) ProtoMessage()    {}
+func (*ExecutedBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{20}
+}
+func (m *ExecutedBatch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExecutedBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExecutedBatch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExecutedBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecutedBatch.Merge(m, src)
+}
+func (m *ExecutedBatch) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExecutedBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecutedBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecutedBatch proto.InternalMessageInfo
+
+func (m *ExecutedBatch) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *ExecutedBatch) GetTxHashes() []string {
+	if m != nil {
+		return m.TxHashes
+	}
+	return nil
+}
+
+type CheckpointSnapshot struct {
+	Seq     uint64            `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Digest  string            `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	Stable  *StableCheckpoint `protobuf:"bytes,3,opt,name=stable,proto3" json:"stable,omitempty"`
+	Batches []*ExecutedBatch  `protobuf:"bytes,4,rep,name=batches,proto3" json:"batches,omitempty"`
+}
+
+func (m *CheckpointSnapshot) Reset()         { *m = CheckpointSnapshot{} }
+func (m *CheckpointSnapshot) String() string { return proto.CompactTextString(m) }
+func (*CheckpointSnapshot) ProtoMessage()    {}
+func (*CheckpointSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{21}
+}
+func (m *CheckpointSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CheckpointSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CheckpointSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CheckpointSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointSnapshot.Merge(m, src)
+}
+func (m *CheckpointSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *CheckpointSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointSnapshot proto.InternalMessageInfo
+
+func (m *CheckpointSnapshot) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *CheckpointSnapshot) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+func (m *CheckpointSnapshot) GetStable() *StableCheckpoint {
+	if m != nil {
+		return m.Stable
+	}
+	return nil
+}
+
+func (m *CheckpointSnapshot) GetBatches() []*ExecutedBatch {
+	if m != nil {
+		return m.Batches
+	}
+	return nil
+}
+
+type StateFetch struct {
+	ReplicaId uint64 `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	Seq       uint64 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+}
+
+func (m *StateFetch) Reset()         { *m = StateFetch{} }
+func (m *StateFetch) String() string { return proto.CompactTextString(m) }
+func (*StateFetch) ProtoMessage()    {}
+func (*StateFetch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{22}
+}
+func (m *StateFetch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StateFetch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StateFetch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StateFetch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateFetch.Merge(m, src)
+}
+func (m *StateFetch) XXX_Size() int {
+	return m.Size()
+}
+func (m *StateFetch) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateFetch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateFetch proto.InternalMessageInfo
+
+func (m *StateFetch) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
+	}
+	return 0
+}
+
+func (m *StateFetch) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+type StateResponse struct {
+	ReplicaId  uint64            `protobuf:"varint,1,opt,name=replica_id,json=replicaId,proto3" json:"replica_id,omitempty"`
+	Checkpoint *StableCheckpoint `protobuf:"bytes,2,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
+	Batches    []*ExecutedBatch  `protobuf:"bytes,3,rep,name=batches,proto3" json:"batches,omitempty"`
+}
+
+func (m *StateResponse) Reset()         { *m = StateResponse{} }
+func (m *StateResponse) String() string { return proto.CompactTextString(m) }
+func (*StateResponse) ProtoMessage()    {}
+func (*StateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_52f9c01338bf5dac, []int{23}
+}
+func (m *StateResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StateResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateResponse.Merge(m, src)
+}
+func (m *StateResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *StateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateResponse proto.InternalMessageInfo
+
+func (m *StateResponse) GetReplicaId() uint64 {
+	if m != nil {
+		return m.ReplicaId
+	}
+	return 0
+}
+
+func (m *StateResponse) GetCheckpoint() *StableCheckpoint {
+	if m != nil {
+		return m.Checkpoint
+	}
+	return nil
+}
+
+func (m *StateResponse) GetBatches() []*ExecutedBatch {
+	if m != nil {
+		return m.Batches
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("falanxpb.Type", Type_name, Type_value)
+	proto.RegisterEnum("falanxpb.HashAlgorithm", HashAlgorithm_name, HashAlgorithm_value)
+	proto.RegisterType((*ConsensusMessage)(nil), "falanxpb.consensus_message")
+	proto.RegisterType((*Transaction)(nil), "falanxpb.Transaction")
+	proto.RegisterType((*Reconfig)(nil), "falanxpb.reconfig")
+	proto.RegisterType((*RequestSet)(nil), "falanxpb.request_set")
+	proto.RegisterType((*OrderedLog)(nil), "falanxpb.ordered_log")
+	proto.RegisterType((*OrderedReq)(nil), "falanxpb.ordered_req")
+	proto.RegisterType((*BaVote)(nil), "falanxpb.ba_vote")
+	proto.RegisterType((*Suspect)(nil), "falanxpb.suspect")
+	proto.RegisterType((*Reply)(nil), "falanxpb.reply")
+	proto.RegisterType((*LogFetch)(nil), "falanxpb.log_fetch")
+	proto.RegisterType((*LogResponse)(nil), "falanxpb.log_response")
+	proto.RegisterType((*ReqFetch)(nil), "falanxpb.req_fetch")
+	proto.RegisterType((*ReqResponse)(nil), "falanxpb.req_response")
+	proto.RegisterType((*TxSet)(nil), "falanxpb.tx_set")
+	proto.RegisterType((*TxFetch)(nil), "falanxpb.tx_fetch")
+	proto.RegisterType((*PendingPair)(nil), "falanxpb.pending_pair")
+	proto.RegisterType((*Blacklisted)(nil), "falanxpb.blacklisted")
+	proto.RegisterType((*FilterSnapshot)(nil), "falanxpb.filter_snapshot")
+	proto.RegisterType((*Checkpoint)(nil), "falanxpb.checkpoint")
+	proto.RegisterType((*StableCheckpoint)(nil), "falanxpb.stable_checkpoint")
+	proto.RegisterType((*ExecutedBatch)(nil), "falanxpb.executed_batch")
+	proto.RegisterType((*CheckpointSnapshot)(nil), "falanxpb.checkpoint_snapshot")
+	proto.RegisterType((*StateFetch)(nil), "falanxpb.state_fetch")
+	proto.RegisterType((*StateResponse)(nil), "falanxpb.state_response")
+}
+
+func init() { proto.RegisterFile("falanx.proto", fileDescriptor_52f9c01338bf5dac) }
+
+var fileDescriptor_52f9c01338bf5dac = []byte{
+	// 1281 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0xcf, 0x6e, 0xdb, 0xc6,
+	0x13, 0x36, 0x25, 0x4a, 0x22, 0x47, 0xb2, 0xcc, 0xec, 0x2f, 0x7f, 0xf8, 0x4b, 0x5a, 0xd7, 0xe0,
+	0xa5, 0x4e, 0x50, 0x18, 0xad, 0x8c, 0xf6, 0x52, 0x20, 0xad, 0xe2, 0x30, 0x91, 0x11, 0x37, 0xb6,
+	0x57, 0x4c, 0x9b, 0x9e, 0x08, 0x8a, 0x5a, 0x49, 0x44, 0x28, 0x92, 0xe2, 0xae, 0x02, 0xf9, 0xd6,
+	0x47, 0x28, 0x7a, 0xee, 0xa1, 0xc7, 0x02, 0x7d, 0x91, 0xa2, 0xa7, 0x1c, 0x7b, 0x29, 0x50, 0x24,
+	0x7d, 0x90, 0x62, 0x97, 0x2b, 0x91, 0x72, 0x62, 0x49, 0xed, 0xa5, 0x37, 0xcd, 0xf0, 0xdb, 0x99,
+	0xf9, 0x66, 0xbe, 0x1d, 0x8a, 0xd0, 0x18, 0x78, 0xa1, 0x17, 0xcd, 0x0e, 0x92, 0x34, 0x66, 0x31,
+	0xd2, 0x32, 0x2b, 0xe9, 0x59, 0xe7, 0x70, 0xcd, 0x8f, 0x23, 0x4a, 0x22, 0x3a, 0xa5, 0xee, 0x98,
+	0x50, 0xea, 0x0d, 0x09, 0xb2, 0x40, 0x65, 0x17, 0x09, 0x31, 0x95, 0x3d, 0x65, 0xbf, 0xd9, 0x6a,
+	0x1e, 0xcc, 0xd1, 0x07, 0xce, 0x45, 0x42, 0xb0, 0x78, 0x86, 0x4c, 0xa8, 0x25, 0xde, 0x45, 0x18,
+	0x7b, 0x7d, 0xb3, 0xb4, 0xa7, 0xec, 0x37, 0xf0, 0xdc, 0xb4, 0xbe, 0x81, 0xba, 0x93, 0x7a, 0x11,
+	0xf5, 0x7c, 0x16, 0xc4, 0x51, 0x11, 0xa8, 0x2c, 0x01, 0xd1, 0x01, 0x68, 0x29, 0xf1, 0xe3, 0x68,
+	0x10, 0x0c, 0x45, 0x8c, 0x7a, 0x0b, 0xe5, 0xa9, 0xe6, 0x4f, 0xf0, 0x02, 0x63, 0x5d, 0xe4, 0x78,
+	0x74, 0x1d, 0x2a, 0x24, 0x89, 0xfd, 0x91, 0x88, 0xa9, 0xe2, 0xcc, 0x40, 0xb7, 0x39, 0x22, 0x09,
+	0x03, 0xdf, 0xa3, 0x66, 0x69, 0xaf, 0xbc, 0xaf, 0xe2, 0x85, 0x8d, 0xde, 0x07, 0x90, 0xbf, 0xdd,
+	0xa0, 0x6f, 0x96, 0xc5, 0x31, 0x5d, 0x7a, 0x8e, 0xfb, 0xe8, 0x3d, 0xd0, 0x69, 0x30, 0x8c, 0x3c,
+	0x36, 0x4d, 0x89, 0xa9, 0x8a, 0x42, 0x73, 0x87, 0xf5, 0x25, 0xd4, 0x53, 0x32, 0x99, 0x12, 0xca,
+	0x5c, 0x4a, 0x18, 0xfa, 0x04, 0x34, 0x69, 0x52, 0x53, 0xd9, 0x2b, 0xef, 0xd7, 0x5b, 0x37, 0x0a,
+	0x4d, 0xca, 0xc9, 0xe3, 0x05, 0xcc, 0xfa, 0x43, 0x81, 0x7a, 0x9c, 0xf6, 0x49, 0x4a, 0xfa, 0x6e,
+	0x18, 0x0f, 0x2f, 0x95, 0xa3, 0x5c, 0x2e, 0xe7, 0x36, 0x68, 0x94, 0x1f, 0x8d, 0x7c, 0x22, 0x7a,
+	0xa3, 0xe2, 0x85, 0x8d, 0x6e, 0x41, 0x8d, 0xcd, 0xdc, 0x91, 0x47, 0x47, 0x82, 0x86, 0x8e, 0xab,
+	0x6c, 0xd6, 0xf1, 0xe8, 0x88, 0x73, 0x60, 0xc1, 0x98, 0x50, 0xe6, 0x8d, 0x13, 0xc1, 0xa1, 0x8c,
+	0x73, 0xc7, 0x32, 0xc3, 0xca, 0x25, 0x86, 0xe8, 0x3e, 0x34, 0x79, 0x44, 0xd7, 0x0b, 0x87, 0x71,
+	0x1a, 0xb0, 0xd1, 0xd8, 0xac, 0x8a, 0xe9, 0xdf, 0xca, 0x89, 0xf1, 0x1c, 0xed, 0xf9, 0x63, 0xbc,
+	0x3d, 0x2a, 0x9a, 0xd6, 0x5f, 0x05, 0x7e, 0x29, 0x99, 0xa0, 0x3b, 0xa0, 0xfb, 0x61, 0x40, 0x22,
+	0x96, 0xd3, 0xd3, 0x32, 0xc7, 0x1a, 0x76, 0x7b, 0xd0, 0x90, 0xec, 0xdc, 0x30, 0xa0, 0xcc, 0x2c,
+	0xef, 0x95, 0xf7, 0x75, 0x0c, 0x19, 0xc5, 0x93, 0x80, 0xb2, 0xff, 0x94, 0xe6, 0x8f, 0x0a, 0xd4,
+	0x7a, 0x9e, 0xfb, 0x32, 0x66, 0x64, 0xdd, 0x08, 0x0b, 0x63, 0x2a, 0x2d, 0x8d, 0xe9, 0x2e, 0x18,
+	0xe3, 0x80, 0xd2, 0x20, 0x1a, 0xba, 0x0b, 0xb5, 0x96, 0x85, 0x5a, 0x77, 0xa4, 0x1f, 0x4b, 0xf7,
+	0x6a, 0x55, 0x22, 0x03, 0xca, 0x94, 0x4c, 0x04, 0x49, 0x15, 0xf3, 0x9f, 0xd6, 0x14, 0x6a, 0x74,
+	0x4a, 0x13, 0xe2, 0xb3, 0x75, 0xd5, 0xdd, 0x01, 0x7d, 0xec, 0x85, 0x81, 0x4f, 0xf8, 0x53, 0x39,
+	0x83, 0xcc, 0x71, 0xf9, 0x32, 0x94, 0xaf, 0x48, 0xab, 0xe6, 0x69, 0x7f, 0x50, 0xa0, 0xc2, 0x43,
+	0x5f, 0x6c, 0x90, 0x35, 0x57, 0x45, 0xe9, 0x92, 0x2a, 0xae, 0xd4, 0xf5, 0x4d, 0xa8, 0xa6, 0x84,
+	0x4e, 0x43, 0x26, 0x5b, 0x20, 0xad, 0xd5, 0xa3, 0xb6, 0x18, 0xe8, 0x61, 0x3c, 0x74, 0x07, 0x84,
+	0xf9, 0xa3, 0x75, 0x75, 0xdd, 0x84, 0x6a, 0x9c, 0x06, 0xc3, 0x20, 0x92, 0x45, 0x49, 0x0b, 0xfd,
+	0x1f, 0xb4, 0x41, 0x1a, 0x8f, 0x5d, 0xce, 0x37, 0x5b, 0x19, 0x35, 0x6e, 0x77, 0xc9, 0x04, 0xdd,
+	0x80, 0x2a, 0x8b, 0xdd, 0xbc, 0x11, 0x15, 0x16, 0x77, 0xc9, 0xc4, 0x4a, 0xa0, 0xc1, 0xb3, 0xa6,
+	0x84, 0x26, 0x7c, 0xaf, 0xfe, 0xdb, 0xc4, 0x77, 0x41, 0x0d, 0xe3, 0x61, 0xa6, 0x8b, 0xa5, 0xed,
+	0x52, 0xd8, 0x21, 0x58, 0x40, 0xac, 0x0b, 0xd0, 0x53, 0x32, 0xd9, 0x8c, 0xe7, 0xca, 0xfe, 0xff,
+	0x73, 0xb2, 0xbf, 0x28, 0xd0, 0xe0, 0xb9, 0x37, 0x65, 0xbb, 0x32, 0xfd, 0x5d, 0x50, 0x53, 0x32,
+	0x59, 0x41, 0x39, 0x25, 0x13, 0x2c, 0x20, 0xbc, 0x6b, 0x5e, 0x8f, 0x92, 0x88, 0x0b, 0x82, 0xdf,
+	0x1b, 0x69, 0xad, 0x11, 0x44, 0x04, 0x55, 0x36, 0x13, 0xfb, 0x7b, 0x4d, 0x99, 0x1f, 0x42, 0x99,
+	0xcd, 0xb2, 0x37, 0xc8, 0x95, 0x9b, 0x9d, 0x23, 0x56, 0xdf, 0x13, 0xeb, 0x11, 0x68, 0x6c, 0xb6,
+	0xf1, 0x5c, 0xa4, 0xf4, 0x49, 0x96, 0x57, 0xc7, 0x5a, 0x26, 0x7e, 0x42, 0xad, 0xfb, 0xd0, 0x48,
+	0x48, 0xd4, 0xe7, 0xfb, 0x22, 0xf1, 0x82, 0x94, 0xb3, 0x1f, 0xc4, 0xe9, 0x98, 0xa4, 0x22, 0x8e,
+	0x8e, 0xa5, 0xc5, 0xfd, 0xa1, 0xc7, 0x18, 0x49, 0xe7, 0xfb, 0x26, 0xb3, 0xac, 0xaf, 0xa0, 0xde,
+	0x0b, 0x3d, 0xff, 0x05, 0x5f, 0xa7, 0xa4, 0xbf, 0xae, 0x94, 0x0f, 0xf8, 0xab, 0xce, 0xeb, 0x8f,
+	0x03, 0x26, 0xe6, 0x9d, 0x4d, 0x09, 0xa4, 0x8b, 0x0f, 0xfd, 0xb7, 0x12, 0xec, 0x0c, 0x82, 0x90,
+	0x91, 0xd4, 0xa5, 0x91, 0x97, 0xd0, 0x51, 0xcc, 0x78, 0xfd, 0x3d, 0x8f, 0xf9, 0x23, 0x71, 0x44,
+	0x6e, 0x7b, 0xe1, 0xe0, 0xe2, 0xb9, 0x0d, 0x1a, 0x99, 0x11, 0x7f, 0xca, 0x48, 0x7f, 0xce, 0x6d,
+	0x6e, 0xf3, 0x67, 0x2f, 0x49, 0x1a, 0x0c, 0x02, 0xd2, 0x97, 0x9b, 0x7e, 0x61, 0xa3, 0x43, 0xd0,
+	0x17, 0x75, 0x9b, 0xea, 0xe5, 0x61, 0x14, 0x28, 0xe1, 0x1c, 0xb7, 0xb8, 0x38, 0x95, 0xb5, 0x17,
+	0x07, 0x7d, 0x0c, 0x35, 0xd9, 0x57, 0xb3, 0x2a, 0xd0, 0x37, 0x73, 0x74, 0xb1, 0xe1, 0x78, 0x0e,
+	0xe3, 0xef, 0xfd, 0x24, 0x8d, 0x87, 0x29, 0xa1, 0xd4, 0xac, 0xad, 0x4a, 0xb0, 0x80, 0xf1, 0x6e,
+	0x53, 0xe6, 0xf5, 0x42, 0x22, 0x5a, 0xa3, 0x65, 0xdd, 0xce, 0x3c, 0xbc, 0x99, 0x14, 0xc0, 0x1f,
+	0x11, 0xff, 0x45, 0x12, 0x07, 0xd1, 0x5a, 0x5d, 0xca, 0xc5, 0x5b, 0x5a, 0x2c, 0x5e, 0x3e, 0xf2,
+	0x7e, 0x30, 0x24, 0xe2, 0x35, 0x29, 0x46, 0x9e, 0x59, 0x6b, 0xfe, 0xcd, 0xbc, 0x80, 0x6b, 0xb2,
+	0xa6, 0x42, 0x6e, 0x19, 0x5c, 0x79, 0x57, 0xf0, 0xd2, 0x52, 0xf0, 0x8f, 0xa0, 0x9a, 0xa4, 0x71,
+	0x3c, 0x98, 0x5f, 0xd5, 0xeb, 0x79, 0x0f, 0xf2, 0x78, 0x58, 0x62, 0xac, 0x2f, 0xa0, 0x39, 0x9f,
+	0xb6, 0x2b, 0x24, 0xf1, 0x8e, 0x4c, 0x2b, 0xe5, 0xff, 0xb3, 0x02, 0xff, 0xcb, 0xe3, 0xe6, 0x9a,
+	0xdb, 0xbc, 0xe0, 0x43, 0xa8, 0x66, 0x7c, 0x45, 0x97, 0xea, 0xad, 0x3b, 0x79, 0xc1, 0x6f, 0xf5,
+	0x01, 0x4b, 0x28, 0x6a, 0xf1, 0x17, 0x3d, 0xf3, 0x79, 0x45, 0x99, 0xf6, 0xcc, 0xfc, 0xd4, 0x32,
+	0x21, 0x3c, 0x07, 0x5a, 0xf7, 0xa1, 0x4e, 0x99, 0xc7, 0xc8, 0x66, 0x97, 0xfe, 0xad, 0x71, 0x5a,
+	0x3f, 0x29, 0xd0, 0xcc, 0x02, 0x6c, 0xba, 0x51, 0x3f, 0x2f, 0xea, 0xc7, 0x2c, 0xad, 0xa7, 0x57,
+	0x94, 0x5b, 0x81, 0x62, 0x79, 0x43, 0x8a, 0xf7, 0xbe, 0x2b, 0x81, 0xca, 0x3f, 0x03, 0xd0, 0x0e,
+	0xd4, 0xb1, 0x7d, 0xfe, 0xcc, 0xee, 0x3a, 0x6e, 0xd7, 0x76, 0x8c, 0x2d, 0xee, 0x38, 0xc5, 0x0f,
+	0x6d, 0x6c, 0x3f, 0x74, 0xb1, 0x7d, 0x6e, 0x28, 0x45, 0xc7, 0xc9, 0xe9, 0x63, 0xa3, 0x84, 0xea,
+	0x50, 0x7b, 0xd0, 0x76, 0xbf, 0x3e, 0x75, 0x6c, 0xa3, 0xcc, 0x8d, 0xee, 0xb3, 0xee, 0x99, 0x7d,
+	0xe4, 0x18, 0x2a, 0xd2, 0xa1, 0x82, 0xed, 0xb3, 0x93, 0x6f, 0x8d, 0x0a, 0xda, 0x06, 0xfd, 0xe4,
+	0xf4, 0xb1, 0xfb, 0xc8, 0x76, 0x8e, 0x3a, 0x46, 0x15, 0x19, 0xd0, 0xe0, 0x26, 0xb6, 0xbb, 0x67,
+	0xa7, 0x4f, 0xbb, 0xb6, 0x51, 0xe3, 0x00, 0x6c, 0x9f, 0x4b, 0x80, 0xc6, 0x01, 0xdc, 0x5c, 0x00,
+	0x74, 0x04, 0x50, 0x75, 0x9e, 0x8b, 0xa2, 0x00, 0x35, 0x40, 0x73, 0x9e, 0x4b, 0x6c, 0x9d, 0x57,
+	0xe4, 0x3c, 0xcf, 0xa1, 0x0d, 0xd4, 0x04, 0x38, 0xea, 0xd8, 0x47, 0x4f, 0xce, 0x4e, 0x8f, 0x9f,
+	0x3a, 0xc6, 0x36, 0x07, 0x74, 0x9d, 0xb6, 0x63, 0xcb, 0x13, 0x4d, 0x84, 0xa0, 0x99, 0x39, 0x16,
+	0x87, 0x76, 0xee, 0x75, 0x60, 0x7b, 0xe9, 0x3f, 0x22, 0xba, 0x0e, 0x46, 0xa7, 0xdd, 0xed, 0xb8,
+	0xcf, 0x9e, 0x72, 0x3e, 0xc7, 0x8f, 0x8e, 0xed, 0x87, 0xc6, 0x16, 0x2f, 0xa3, 0xdb, 0x69, 0xb7,
+	0x3e, 0xfd, 0xcc, 0x50, 0x04, 0xf3, 0x93, 0xf6, 0x13, 0xbb, 0xf5, 0xc0, 0x28, 0x21, 0x0d, 0xd4,
+	0x6e, 0xa7, 0x7d, 0x68, 0x94, 0x1f, 0x98, 0xbf, 0xbe, 0xde, 0x55, 0x5e, 0xbd, 0xde, 0x55, 0xfe,
+	0x7c, 0xbd, 0xab, 0x7c, 0xff, 0x66, 0x77, 0xeb, 0xd5, 0x9b, 0xdd, 0xad, 0xdf, 0xdf, 0xec, 0x6e,
+	0xf5, 0xaa, 0xe2, 0x43, 0xed, 0xf0, 0xef, 0x01, 0x00, 0xa5, 0x60, 0xb5, 0xf9, 0xb8, 0x0d, 0x00,
+	0x00,
+}
+
+func (m *ConsensusMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConsensusMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ConsensusMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Payload) > 0 {
+		i -= len(m.Payload)
+		copy(dAtA[i:], m.Payload)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Payload)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Type != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Transaction) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Transaction) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Transaction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Reconfig != nil {
+		{
+			size, err := m.Reconfig.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFalanx(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Payload) > 0 {
+		i -= len(m.Payload)
+		copy(dAtA[i:], m.Payload)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Payload)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Reconfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Reconfig) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Reconfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Replicas) > 0 {
+		dAtA3 := make([]byte, len(m.Replicas)*10)
+		var j2 int
+		for _, num := range m.Replicas {
+			for num >= 1<<7 {
+				dAtA3[j2] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j2++
+			}
+			dAtA3[j2] = uint8(num)
+			j2++
+		}
+		i -= j2
+		copy(dAtA[i:], dAtA3[:j2])
+		i = encodeVarintFalanx(dAtA, i, uint64(j2))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Epoch != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Epoch))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RequestSet) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RequestSet) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RequestSet) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Requests) > 0 {
+		for iNdEx := len(m.Requests) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Requests[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *OrderedLog) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OrderedLog) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OrderedLog) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.HashAlgorithm != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.HashAlgorithm))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.TxHash) > 0 {
+		i -= len(m.TxHash)
+		copy(dAtA[i:], m.TxHash)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.TxHash)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Sequence != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *OrderedReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OrderedReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OrderedReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.HashAlgorithm != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.HashAlgorithm))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.TxHashList) > 0 {
+		for iNdEx := len(m.TxHashList) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TxHashList[iNdEx])
+			copy(dAtA[i:], m.TxHashList[iNdEx])
+			i = encodeVarintFalanx(dAtA, i, uint64(len(m.TxHashList[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Sequence != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ClientId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ClientId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BaVote) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BaVote) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BaVote) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Seq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Seq))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.MissingReplicas) > 0 {
+		dAtA5 := make([]byte, len(m.MissingReplicas)*10)
+		var j4 int
+		for _, num := range m.MissingReplicas {
+			for num >= 1<<7 {
+				dAtA5[j4] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j4++
+			}
+			dAtA5[j4] = uint8(num)
+			j4++
+		}
+		i -= j4
+		copy(dAtA[i:], dAtA5[:j4])
+		i = encodeVarintFalanx(dAtA, i, uint64(j4))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.TxHash) > 0 {
+		i -= len(m.TxHash)
+		copy(dAtA[i:], m.TxHash)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.TxHash)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Suspect) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Suspect) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Suspect) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Seq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Seq))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.MaliceId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.MaliceId))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Reply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Reply) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Reply) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Result) > 0 {
+		i -= len(m.Result)
+		copy(dAtA[i:], m.Result)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Result)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.TxHash) > 0 {
+		i -= len(m.TxHash)
+		copy(dAtA[i:], m.TxHash)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.TxHash)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.ClientId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ClientId))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LogFetch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LogFetch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LogFetch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ToSeq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ToSeq))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.FromSeq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.FromSeq))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Origin != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Origin))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LogResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LogResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LogResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Logs) > 0 {
+		for iNdEx := len(m.Logs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Logs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Origin != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Origin))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ReqFetch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReqFetch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReqFetch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ToSeq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ToSeq))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.FromSeq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.FromSeq))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.ClientId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ClientId))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ReqResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReqResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReqResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Absent) > 0 {
+		dAtA7 := make([]byte, len(m.Absent)*10)
+		var j6 int
+		for _, num := range m.Absent {
+			for num >= 1<<7 {
+				dAtA7[j6] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j6++
+			}
+			dAtA7[j6] = uint8(num)
+			j6++
+		}
+		i -= j6
+		copy(dAtA[i:], dAtA7[:j6])
+		i = encodeVarintFalanx(dAtA, i, uint64(j6))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Reqs) > 0 {
+		for iNdEx := len(m.Reqs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Reqs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.ClientId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ClientId))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TxSet) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxSet) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TxSet) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Txs) > 0 {
+		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Txs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TxFetch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxFetch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TxFetch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TxHashes) > 0 {
+		for iNdEx := len(m.TxHashes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TxHashes[iNdEx])
+			copy(dAtA[i:], m.TxHashes[iNdEx])
+			i = encodeVarintFalanx(dAtA, i, uint64(len(m.TxHashes[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PendingPair) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PendingPair) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PendingPair) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Latter) > 0 {
+		i -= len(m.Latter)
+		copy(dAtA[i:], m.Latter)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Latter)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Former) > 0 {
+		i -= len(m.Former)
+		copy(dAtA[i:], m.Former)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Former)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Blacklisted) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Blacklisted) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Blacklisted) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ReadmitSeq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReadmitSeq))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FilterSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FilterSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FilterSnapshot) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.StableSeq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.StableSeq))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.Progress) > 0 {
+		for iNdEx := len(m.Progress) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Progress[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.Pending) > 0 {
+		for iNdEx := len(m.Pending) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Pending[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.Logs) > 0 {
+		for iNdEx := len(m.Logs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Logs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.Blacklist) > 0 {
+		for iNdEx := len(m.Blacklist) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Blacklist[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Verified) > 0 {
+		for iNdEx := len(m.Verified) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Verified[iNdEx])
+			copy(dAtA[i:], m.Verified[iNdEx])
+			i = encodeVarintFalanx(dAtA, i, uint64(len(m.Verified[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Executed) > 0 {
+		for iNdEx := len(m.Executed) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Executed[iNdEx])
+			copy(dAtA[i:], m.Executed[iNdEx])
+			i = encodeVarintFalanx(dAtA, i, uint64(len(m.Executed[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.BatchSeq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.BatchSeq))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Checkpoint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Checkpoint) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Checkpoint) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Digest) > 0 {
+		i -= len(m.Digest)
+		copy(dAtA[i:], m.Digest)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Digest)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Seq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Seq))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StableCheckpoint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StableCheckpoint) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StableCheckpoint) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Proofs) > 0 {
+		for iNdEx := len(m.Proofs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Proofs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Digest) > 0 {
+		i -= len(m.Digest)
+		copy(dAtA[i:], m.Digest)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Digest)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Seq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Seq))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExecutedBatch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExecutedBatch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExecutedBatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TxHashes) > 0 {
+		for iNdEx := len(m.TxHashes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TxHashes[iNdEx])
+			copy(dAtA[i:], m.TxHashes[iNdEx])
+			i = encodeVarintFalanx(dAtA, i, uint64(len(m.TxHashes[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Seq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Seq))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CheckpointSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CheckpointSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CheckpointSnapshot) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Batches) > 0 {
+		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.Stable != nil {
+		{
+			size, err := m.Stable.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFalanx(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Digest) > 0 {
+		i -= len(m.Digest)
+		copy(dAtA[i:], m.Digest)
+		i = encodeVarintFalanx(dAtA, i, uint64(len(m.Digest)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Seq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Seq))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StateFetch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StateFetch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StateFetch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Seq != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.Seq))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StateResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StateResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StateResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Batches) > 0 {
+		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFalanx(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Checkpoint != nil {
+		{
+			size, err := m.Checkpoint.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFalanx(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.ReplicaId != 0 {
+		i = encodeVarintFalanx(dAtA, i, uint64(m.ReplicaId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintFalanx(dAtA []byte, offset int, v uint64) int {
+	offset -= sovFalanx(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *ConsensusMessage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovFalanx(uint64(m.Type))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	return n
+}
+
+func (m *Transaction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if m.Reconfig != nil {
+		l = m.Reconfig.Size()
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	return n
+}
+
+func (m *Reconfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Epoch != 0 {
+		n += 1 + sovFalanx(uint64(m.Epoch))
+	}
+	if len(m.Replicas) > 0 {
+		l = 0
+		for _, e := range m.Replicas {
+			l += sovFalanx(uint64(e))
+		}
+		n += 1 + sovFalanx(uint64(l)) + l
+	}
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	return n
+}
+
+func (m *RequestSet) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Requests) > 0 {
+		for _, e := range m.Requests {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *OrderedLog) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovFalanx(uint64(m.Sequence))
+	}
+	l = len(m.TxHash)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovFalanx(uint64(m.Timestamp))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if m.HashAlgorithm != 0 {
+		n += 1 + sovFalanx(uint64(m.HashAlgorithm))
+	}
+	return n
+}
+
+func (m *OrderedReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ClientId != 0 {
+		n += 1 + sovFalanx(uint64(m.ClientId))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovFalanx(uint64(m.Sequence))
+	}
+	if len(m.TxHashList) > 0 {
+		for _, s := range m.TxHashList {
+			l = len(s)
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovFalanx(uint64(m.Timestamp))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if m.HashAlgorithm != 0 {
+		n += 1 + sovFalanx(uint64(m.HashAlgorithm))
+	}
+	return n
+}
+
+func (m *BaVote) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	l = len(m.TxHash)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if len(m.MissingReplicas) > 0 {
+		l = 0
+		for _, e := range m.MissingReplicas {
+			l += sovFalanx(uint64(e))
+		}
+		n += 1 + sovFalanx(uint64(l)) + l
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if m.Seq != 0 {
+		n += 1 + sovFalanx(uint64(m.Seq))
+	}
+	return n
+}
+
+func (m *Suspect) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.MaliceId != 0 {
+		n += 1 + sovFalanx(uint64(m.MaliceId))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if m.Seq != 0 {
+		n += 1 + sovFalanx(uint64(m.Seq))
+	}
+	return n
+}
+
+func (m *Reply) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.ClientId != 0 {
+		n += 1 + sovFalanx(uint64(m.ClientId))
+	}
+	l = len(m.TxHash)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	l = len(m.Result)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	return n
+}
+
+func (m *LogFetch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.Origin != 0 {
+		n += 1 + sovFalanx(uint64(m.Origin))
+	}
+	if m.FromSeq != 0 {
+		n += 1 + sovFalanx(uint64(m.FromSeq))
+	}
+	if m.ToSeq != 0 {
+		n += 1 + sovFalanx(uint64(m.ToSeq))
+	}
+	return n
+}
+
+func (m *LogResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.Origin != 0 {
+		n += 1 + sovFalanx(uint64(m.Origin))
+	}
+	if len(m.Logs) > 0 {
+		for _, e := range m.Logs {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ReqFetch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.ClientId != 0 {
+		n += 1 + sovFalanx(uint64(m.ClientId))
+	}
+	if m.FromSeq != 0 {
+		n += 1 + sovFalanx(uint64(m.FromSeq))
+	}
+	if m.ToSeq != 0 {
+		n += 1 + sovFalanx(uint64(m.ToSeq))
+	}
+	return n
+}
+
+func (m *ReqResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.ClientId != 0 {
+		n += 1 + sovFalanx(uint64(m.ClientId))
+	}
+	if len(m.Reqs) > 0 {
+		for _, e := range m.Reqs {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	if len(m.Absent) > 0 {
+		l = 0
+		for _, e := range m.Absent {
+			l += sovFalanx(uint64(e))
+		}
+		n += 1 + sovFalanx(uint64(l)) + l
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	return n
+}
+
+func (m *TxSet) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if len(m.Txs) > 0 {
+		for _, e := range m.Txs {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	return n
+}
+
+func (m *TxFetch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if len(m.TxHashes) > 0 {
+		for _, s := range m.TxHashes {
+			l = len(s)
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PendingPair) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Former)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	l = len(m.Latter)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	return n
+}
+
+func (m *Blacklisted) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.ReadmitSeq != 0 {
+		n += 1 + sovFalanx(uint64(m.ReadmitSeq))
+	}
+	return n
+}
+
+func (m *FilterSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BatchSeq != 0 {
+		n += 1 + sovFalanx(uint64(m.BatchSeq))
+	}
+	if len(m.Executed) > 0 {
+		for _, s := range m.Executed {
+			l = len(s)
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	if len(m.Verified) > 0 {
+		for _, s := range m.Verified {
+			l = len(s)
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	if len(m.Blacklist) > 0 {
+		for _, e := range m.Blacklist {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	if len(m.Logs) > 0 {
+		for _, e := range m.Logs {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	if len(m.Pending) > 0 {
+		for _, e := range m.Pending {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	if len(m.Progress) > 0 {
+		for _, e := range m.Progress {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	if m.StableSeq != 0 {
+		n += 1 + sovFalanx(uint64(m.StableSeq))
+	}
+	return n
+}
+
+func (m *Checkpoint) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.Seq != 0 {
+		n += 1 + sovFalanx(uint64(m.Seq))
+	}
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	return n
+}
+
+func (m *StableCheckpoint) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Seq != 0 {
+		n += 1 + sovFalanx(uint64(m.Seq))
+	}
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if len(m.Proofs) > 0 {
+		for _, e := range m.Proofs {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ExecutedBatch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Seq != 0 {
+		n += 1 + sovFalanx(uint64(m.Seq))
+	}
+	if len(m.TxHashes) > 0 {
+		for _, s := range m.TxHashes {
+			l = len(s)
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *CheckpointSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Seq != 0 {
+		n += 1 + sovFalanx(uint64(m.Seq))
+	}
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if m.Stable != nil {
+		l = m.Stable.Size()
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if len(m.Batches) > 0 {
+		for _, e := range m.Batches {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *StateFetch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
 	_ = l
-	if m.ClientId != 0 {
-		n += 1 + sovFalanx(uint64(m.ClientId))
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.Seq != 0 {
+		n += 1 + sovFalanx(uint64(m.Seq))
+	}
+	return n
+}
+
+func (m *StateResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReplicaId != 0 {
+		n += 1 + sovFalanx(uint64(m.ReplicaId))
+	}
+	if m.Checkpoint != nil {
+		l = m.Checkpoint.Size()
+		n += 1 + l + sovFalanx(uint64(l))
+	}
+	if len(m.Batches) > 0 {
+		for _, e := range m.Batches {
+			l = e.Size()
+			n += 1 + l + sovFalanx(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovFalanx(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozFalanx(x uint64) (n int) {
+	return sovFalanx(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: consensus_message: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: consensus_message: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= Type(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Transaction) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Transaction: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Transaction: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reconfig", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Reconfig == nil {
+				m.Reconfig = &Reconfig{}
+			}
+			if err := m.Reconfig.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Reconfig) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: reconfig: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: reconfig: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Epoch", wireType)
+			}
+			m.Epoch = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Epoch |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFalanx
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Replicas = append(m.Replicas, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFalanx
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthFalanx
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthFalanx
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.Replicas) == 0 {
+					m.Replicas = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowFalanx
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Replicas = append(m.Replicas, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Replicas", wireType)
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RequestSet) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: request_set: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: request_set: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Requests", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Requests = append(m.Requests, &Transaction{})
+			if err := m.Requests[len(m.Requests)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OrderedLog) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ordered_log: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ordered_log: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HashAlgorithm", wireType)
+			}
+			m.HashAlgorithm = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HashAlgorithm |= HashAlgorithm(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OrderedReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ordered_req: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ordered_req: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+			}
+			m.ClientId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ClientId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxHashList", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxHashList = append(m.TxHashList, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HashAlgorithm", wireType)
+			}
+			m.HashAlgorithm = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HashAlgorithm |= HashAlgorithm(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BaVote) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ba_vote: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ba_vote: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFalanx
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.MissingReplicas = append(m.MissingReplicas, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFalanx
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthFalanx
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthFalanx
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.MissingReplicas) == 0 {
+					m.MissingReplicas = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowFalanx
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.MissingReplicas = append(m.MissingReplicas, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field MissingReplicas", wireType)
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
+			}
+			m.Seq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Suspect) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: suspect: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: suspect: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaliceId", wireType)
+			}
+			m.MaliceId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaliceId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
+			}
+			m.Seq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Reply) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: reply: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: reply: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+			}
+			m.ClientId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ClientId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Result = append(m.Result[:0], dAtA[iNdEx:postIndex]...)
+			if m.Result == nil {
+				m.Result = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LogFetch) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: log_fetch: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: log_fetch: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Origin", wireType)
+			}
+			m.Origin = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Origin |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromSeq", wireType)
+			}
+			m.FromSeq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FromSeq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToSeq", wireType)
+			}
+			m.ToSeq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ToSeq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LogResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: log_response: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: log_response: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Origin", wireType)
+			}
+			m.Origin = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Origin |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Logs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Logs = append(m.Logs, &OrderedLog{})
+			if err := m.Logs[len(m.Logs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReqFetch) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: req_fetch: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: req_fetch: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+			}
+			m.ClientId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ClientId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromSeq", wireType)
+			}
+			m.FromSeq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FromSeq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToSeq", wireType)
+			}
+			m.ToSeq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ToSeq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReqResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: req_response: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: req_response: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+			}
+			m.ClientId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ClientId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reqs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reqs = append(m.Reqs, &OrderedReq{})
+			if err := m.Reqs[len(m.Reqs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFalanx
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Absent = append(m.Absent, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFalanx
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthFalanx
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthFalanx
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.Absent) == 0 {
+					m.Absent = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowFalanx
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Absent = append(m.Absent, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Absent", wireType)
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	if m.Sequence != 0 {
-		n += 1 + sovFalanx(uint64(m.Sequence))
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	if len(m.TxHashList) > 0 {
-		for _, s := range m.TxHashList {
-			l = len(s)
-			n += 1 + l + sovFalanx(uint64(l))
+	return nil
+}
+func (m *TxSet) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: tx_set: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: tx_set: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Txs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Txs = append(m.Txs, &Transaction{})
+			if err := m.Txs[len(m.Txs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TxFetch) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: tx_fetch: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: tx_fetch: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxHashes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxHashes = append(m.TxHashes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	if m.Timestamp != 0 {
-		n += 1 + sovFalanx(uint64(m.Timestamp))
-	}
-	return n
-}
 
-func (m *Suspect) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.ReplicaId != 0 {
-		n += 1 + sovFalanx(uint64(m.ReplicaId))
-	}
-	if m.MaliceId != 0 {
-		n += 1 + sovFalanx(uint64(m.MaliceId))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
+	return nil
 }
-
-func (m *Reply) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.ReplicaId != 0 {
-		n += 1 + sovFalanx(uint64(m.ReplicaId))
-	}
-	if m.ClientId != 0 {
-		n += 1 + sovFalanx(uint64(m.ClientId))
-	}
-	l = len(m.TxHash)
-	if l > 0 {
-		n += 1 + l + sovFalanx(uint64(l))
+func (m *PendingPair) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: pending_pair: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: pending_pair: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Former", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Former = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Latter", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Latter = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	l = len(m.Result)
-	if l > 0 {
-		n += 1 + l + sovFalanx(uint64(l))
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
+	return nil
 }
-
-func sovFalanx(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+func (m *Blacklisted) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFalanx
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: blacklisted: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: blacklisted: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadmitSeq", wireType)
+			}
+			m.ReadmitSeq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadmitSeq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFalanx(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	return n
-}
-func sozFalanx(x uint64) (n int) {
-	return sovFalanx(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
+func (m *FilterSnapshot) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -901,17 +6080,17 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: consensus_message: wiretype end group for non-group")
+			return fmt.Errorf("proto: filter_snapshot: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: consensus_message: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: filter_snapshot: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field BatchSeq", wireType)
 			}
-			m.Type = 0
+			m.BatchSeq = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFalanx
@@ -921,16 +6100,16 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Type |= Type(b&0x7F) << shift
+				m.BatchSeq |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Executed", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFalanx
@@ -940,36 +6119,218 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthFalanx
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
+			m.Executed = append(m.Executed, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Verified", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Verified = append(m.Verified, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blacklist", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Blacklist = append(m.Blacklist, &Blacklisted{})
+			if err := m.Blacklist[len(m.Blacklist)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Logs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Logs = append(m.Logs, &OrderedLog{})
+			if err := m.Logs[len(m.Logs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pending", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Pending = append(m.Pending, &PendingPair{})
+			if err := m.Pending[len(m.Pending)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Progress", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Progress = append(m.Progress, &OrderedLog{})
+			if err := m.Progress[len(m.Progress)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StableSeq", wireType)
+			}
+			m.StableSeq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StableSeq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFalanx(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthFalanx
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if (iNdEx + skippy) > l {
@@ -984,7 +6345,7 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Transaction) Unmarshal(dAtA []byte) error {
+func (m *Checkpoint) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1007,15 +6368,85 @@ func (m *Transaction) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Transaction: wiretype end group for non-group")
+			return fmt.Errorf("proto: checkpoint: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Transaction: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: checkpoint: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+			}
+			m.ReplicaId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReplicaId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
+			}
+			m.Seq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Digest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1042,9 +6473,9 @@ func (m *Transaction) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -1053,10 +6484,7 @@ func (m *Transaction) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthFalanx
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if (iNdEx + skippy) > l {
@@ -1071,7 +6499,7 @@ func (m *Transaction) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RequestSet) Unmarshal(dAtA []byte) error {
+func (m *StableCheckpoint) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1094,15 +6522,66 @@ func (m *RequestSet) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: request_set: wiretype end group for non-group")
+			return fmt.Errorf("proto: stable_checkpoint: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: request_set: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: stable_checkpoint: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
+			}
+			m.Seq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seq |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Requests", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFalanx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Digest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Proofs", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1129,8 +6608,8 @@ func (m *RequestSet) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Requests = append(m.Requests, &Transaction{})
-			if err := m.Requests[len(m.Requests)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Proofs = append(m.Proofs, &Checkpoint{})
+			if err := m.Proofs[len(m.Proofs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -1140,10 +6619,7 @@ func (m *RequestSet) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthFalanx
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if (iNdEx + skippy) > l {
@@ -1158,7 +6634,7 @@ func (m *RequestSet) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *OrderedLog) Unmarshal(dAtA []byte) error {
+func (m *ExecutedBatch) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1181,17 +6657,17 @@ func (m *OrderedLog) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ordered_log: wiretype end group for non-group")
+			return fmt.Errorf("proto: executed_batch: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ordered_log: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: executed_batch: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ReplicaId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
 			}
-			m.ReplicaId = 0
+			m.Seq = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFalanx
@@ -1201,33 +6677,14 @@ func (m *OrderedLog) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ReplicaId |= uint64(b&0x7F) << shift
+				m.Seq |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
-			}
-			m.Sequence = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowFalanx
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Sequence |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TxHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TxHashes", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1255,37 +6712,15 @@ func (m *OrderedLog) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TxHash = string(dAtA[iNdEx:postIndex])
+			m.TxHashes = append(m.TxHashes, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
-			}
-			m.Timestamp = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowFalanx
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Timestamp |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFalanx(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthFalanx
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if (iNdEx + skippy) > l {
@@ -1300,7 +6735,7 @@ func (m *OrderedLog) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *OrderedReq) Unmarshal(dAtA []byte) error {
+func (m *CheckpointSnapshot) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1323,17 +6758,17 @@ func (m *OrderedReq) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ordered_req: wiretype end group for non-group")
+			return fmt.Errorf("proto: checkpoint_snapshot: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ordered_req: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: checkpoint_snapshot: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
 			}
-			m.ClientId = 0
+			m.Seq = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFalanx
@@ -1343,16 +6778,16 @@ func (m *OrderedReq) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ClientId |= uint64(b&0x7F) << shift
+				m.Seq |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
 			}
-			m.Sequence = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFalanx
@@ -1362,16 +6797,29 @@ func (m *OrderedReq) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Sequence |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Digest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TxHashList", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Stable", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFalanx
@@ -1381,29 +6829,33 @@ func (m *OrderedReq) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthFalanx
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TxHashList = append(m.TxHashList, string(dAtA[iNdEx:postIndex]))
+			if m.Stable == nil {
+				m.Stable = &StableCheckpoint{}
+			}
+			if err := m.Stable.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
 			}
-			m.Timestamp = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFalanx
@@ -1413,21 +6865,33 @@ func (m *OrderedReq) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Timestamp |= int64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFalanx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Batches = append(m.Batches, &ExecutedBatch{})
+			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFalanx(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthFalanx
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if (iNdEx + skippy) > l {
@@ -1442,7 +6906,7 @@ func (m *OrderedReq) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Suspect) Unmarshal(dAtA []byte) error {
+func (m *StateFetch) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1465,10 +6929,10 @@ func (m *Suspect) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: suspect: wiretype end group for non-group")
+			return fmt.Errorf("proto: state_fetch: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: suspect: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: state_fetch: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1492,9 +6956,9 @@ func (m *Suspect) Unmarshal(dAtA []byte) error {
 			}
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MaliceId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
 			}
-			m.MaliceId = 0
+			m.Seq = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFalanx
@@ -1504,7 +6968,7 @@ func (m *Suspect) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.MaliceId |= uint64(b&0x7F) << shift
+				m.Seq |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1515,10 +6979,7 @@ func (m *Suspect) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthFalanx
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if (iNdEx + skippy) > l {
@@ -1533,7 +6994,7 @@ func (m *Suspect) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Reply) Unmarshal(dAtA []byte) error {
+func (m *StateResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1556,10 +7017,10 @@ func (m *Reply) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: reply: wiretype end group for non-group")
+			return fmt.Errorf("proto: state_response: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: reply: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: state_response: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1582,29 +7043,10 @@ func (m *Reply) Unmarshal(dAtA []byte) error {
 				}
 			}
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
-			}
-			m.ClientId = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowFalanx
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.ClientId |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TxHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Checkpoint", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFalanx
@@ -1614,29 +7056,33 @@ func (m *Reply) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthFalanx
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TxHash = string(dAtA[iNdEx:postIndex])
+			if m.Checkpoint == nil {
+				m.Checkpoint = &StableCheckpoint{}
+			}
+			if err := m.Checkpoint.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 4:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFalanx
@@ -1646,24 +7092,24 @@ func (m *Reply) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthFalanx
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Result = append(m.Result[:0], dAtA[iNdEx:postIndex]...)
-			if m.Result == nil {
-				m.Result = []byte{}
+			m.Batches = append(m.Batches, &ExecutedBatch{})
+			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		default:
@@ -1672,10 +7118,7 @@ func (m *Reply) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthFalanx
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFalanx
 			}
 			if (iNdEx + skippy) > l {
@@ -1693,6 +7136,7 @@ func (m *Reply) Unmarshal(dAtA []byte) error {
 func skipFalanx(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -1724,10 +7168,8 @@ func skipFalanx(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -1748,55 +7190,30 @@ func skipFalanx(dAtA []byte) (n int, err error) {
 				return 0, ErrInvalidLengthFalanx
 			}
 			iNdEx += length
-			if iNdEx < 0 {
-				return 0, ErrInvalidLengthFalanx
-			}
-			return iNdEx, nil
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowFalanx
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipFalanx(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-				if iNdEx < 0 {
-					return 0, ErrInvalidLengthFalanx
-				}
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupFalanx
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthFalanx
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthFalanx = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowFalanx   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthFalanx        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowFalanx          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupFalanx = fmt.Errorf("proto: unexpected end of group")
 )
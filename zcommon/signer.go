@@ -0,0 +1,254 @@
+package zcommon
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// Signer is used to sign the messages sent by current node and verify the ones from others
+type Signer interface {
+	Sign(msg []byte) ([]byte, error)
+	Verify(id uint64, msg []byte, sig []byte) error
+}
+
+type ed25519Signer struct {
+	private ed25519.PrivateKey
+	publics map[uint64]ed25519.PublicKey
+}
+
+// NewEd25519Signer is used to initiate the default signer with the private key of current node and the
+// public keys of all the nodes, including the replicas and clients.
+func NewEd25519Signer(private ed25519.PrivateKey, publics map[uint64]ed25519.PublicKey) *ed25519Signer {
+	return &ed25519Signer{private: private, publics: publics}
+}
+
+func (s *ed25519Signer) Sign(msg []byte) ([]byte, error) {
+	if len(s.private) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid private key")
+	}
+	return ed25519.Sign(s.private, msg), nil
+}
+
+func (s *ed25519Signer) Verify(id uint64, msg []byte, sig []byte) error {
+	public, ok := s.publics[id]
+	if !ok {
+		return errors.New("unknown signer")
+	}
+	if !ed25519.Verify(public, msg, sig) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// SignOrderedLog is used to sign the ordered log generated by current replica, the signature is calculated
+// with the payload of the log whose signature field is empty.
+func SignOrderedLog(signer Signer, log *pb.OrderedLog) error {
+	log.Signature = nil
+	digest, err := log.Marshal()
+	if err != nil {
+		return err
+	}
+	log.Signature, err = signer.Sign(digest)
+	return err
+}
+
+// VerifyOrderedLog is used to check whether the ordered log has been signed by the replica it claims.
+func VerifyOrderedLog(signer Signer, log *pb.OrderedLog) error {
+	signature := log.Signature
+	log.Signature = nil
+	digest, err := log.Marshal()
+	log.Signature = signature
+	if err != nil {
+		return err
+	}
+	return signer.Verify(log.ReplicaId, digest, signature)
+}
+
+// SignOrderedReq is used to sign the ordered request generated by current client.
+func SignOrderedReq(signer Signer, req *pb.OrderedReq) error {
+	req.Signature = nil
+	digest, err := req.Marshal()
+	if err != nil {
+		return err
+	}
+	req.Signature, err = signer.Sign(digest)
+	return err
+}
+
+// VerifyOrderedReq is used to check whether the ordered request has been signed by the client it claims.
+func VerifyOrderedReq(signer Signer, req *pb.OrderedReq) error {
+	signature := req.Signature
+	req.Signature = nil
+	digest, err := req.Marshal()
+	req.Signature = signature
+	if err != nil {
+		return err
+	}
+	return signer.Verify(req.ClientId, digest, signature)
+}
+
+// SignTxSet is used to sign the payloads disseminated by current client.
+func SignTxSet(signer Signer, set *pb.TxSet) error {
+	set.Signature = nil
+	digest, err := set.Marshal()
+	if err != nil {
+		return err
+	}
+	set.Signature, err = signer.Sign(digest)
+	return err
+}
+
+// VerifyTxSet is used to check whether the payloads have been disseminated by the client it claims, so that
+// they could be charged to the quota of that client.
+func VerifyTxSet(signer Signer, set *pb.TxSet) error {
+	signature := set.Signature
+	set.Signature = nil
+	digest, err := set.Marshal()
+	set.Signature = signature
+	if err != nil {
+		return err
+	}
+	return signer.Verify(set.ReplicaId, digest, signature)
+}
+
+// SignReply is used to sign the reply generated by current replica.
+func SignReply(signer Signer, reply *pb.Reply) error {
+	reply.Signature = nil
+	digest, err := reply.Marshal()
+	if err != nil {
+		return err
+	}
+	reply.Signature, err = signer.Sign(digest)
+	return err
+}
+
+// VerifyReply is used to check whether the reply has been signed by the replica it claims.
+func VerifyReply(signer Signer, reply *pb.Reply) error {
+	signature := reply.Signature
+	reply.Signature = nil
+	digest, err := reply.Marshal()
+	reply.Signature = signature
+	if err != nil {
+		return err
+	}
+	return signer.Verify(reply.ReplicaId, digest, signature)
+}
+
+// SignCheckpoint is used to sign the checkpoint generated by current replica.
+func SignCheckpoint(signer Signer, checkpoint *pb.Checkpoint) error {
+	checkpoint.Signature = nil
+	digest, err := checkpoint.Marshal()
+	if err != nil {
+		return err
+	}
+	checkpoint.Signature, err = signer.Sign(digest)
+	return err
+}
+
+// VerifyCheckpoint is used to check whether the checkpoint has been signed by the replica it claims.
+func VerifyCheckpoint(signer Signer, checkpoint *pb.Checkpoint) error {
+	signature := checkpoint.Signature
+	checkpoint.Signature = nil
+	digest, err := checkpoint.Marshal()
+	checkpoint.Signature = signature
+	if err != nil {
+		return err
+	}
+	return signer.Verify(checkpoint.ReplicaId, digest, signature)
+}
+
+// SignReconfig is used to sign the reconfiguration proposed by current replica.
+func SignReconfig(signer Signer, reconfig *pb.Reconfig) error {
+	reconfig.Signature = nil
+	digest, err := reconfig.Marshal()
+	if err != nil {
+		return err
+	}
+	reconfig.Signature, err = signer.Sign(digest)
+	return err
+}
+
+// VerifyReconfig is used to check whether the reconfiguration has been signed by the replica it claims, the
+// reconfiguration is carried by a transaction which might be shared by other modules, so that it is copied
+// instead of being modified in place.
+func VerifyReconfig(signer Signer, reconfig *pb.Reconfig) error {
+	unsigned := *reconfig
+	unsigned.Signature = nil
+	digest, err := unsigned.Marshal()
+	if err != nil {
+		return err
+	}
+	return signer.Verify(reconfig.ReplicaId, digest, reconfig.Signature)
+}
+
+// SignBaVote is used to sign the vote generated by current replica.
+func SignBaVote(signer Signer, vote *pb.BaVote) error {
+	vote.Signature = nil
+	digest, err := vote.Marshal()
+	if err != nil {
+		return err
+	}
+	vote.Signature, err = signer.Sign(digest)
+	return err
+}
+
+// VerifyBaVote is used to check whether the vote has been signed by the replica it claims.
+func VerifyBaVote(signer Signer, vote *pb.BaVote) error {
+	signature := vote.Signature
+	vote.Signature = nil
+	digest, err := vote.Marshal()
+	vote.Signature = signature
+	if err != nil {
+		return err
+	}
+	return signer.Verify(vote.ReplicaId, digest, signature)
+}
+
+// SignSuspect is used to sign the suspect message generated by current replica.
+func SignSuspect(signer Signer, suspect *pb.Suspect) error {
+	suspect.Signature = nil
+	digest, err := suspect.Marshal()
+	if err != nil {
+		return err
+	}
+	suspect.Signature, err = signer.Sign(digest)
+	return err
+}
+
+// VerifySuspect is used to check whether the suspect message has been signed by the replica it claims.
+func VerifySuspect(signer Signer, suspect *pb.Suspect) error {
+	signature := suspect.Signature
+	suspect.Signature = nil
+	digest, err := suspect.Marshal()
+	suspect.Signature = signature
+	if err != nil {
+		return err
+	}
+	return signer.Verify(suspect.ReplicaId, digest, signature)
+}
+
+// SignReqResponse is used to sign the response generated by current replica, so that the absent sequence
+// numbers reported in it could be attributed to current replica.
+func SignReqResponse(signer Signer, response *pb.ReqResponse) error {
+	response.Signature = nil
+	digest, err := response.Marshal()
+	if err != nil {
+		return err
+	}
+	response.Signature, err = signer.Sign(digest)
+	return err
+}
+
+// VerifyReqResponse is used to check whether the response has been signed by the replica it claims.
+func VerifyReqResponse(signer Signer, response *pb.ReqResponse) error {
+	signature := response.Signature
+	response.Signature = nil
+	digest, err := response.Marshal()
+	response.Signature = signature
+	if err != nil {
+		return err
+	}
+	return signer.Verify(response.ReplicaId, digest, signature)
+}
@@ -0,0 +1,151 @@
+package zcommon
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// newTestSigners generates the key pairs of the nodes and returns their signers, each of which holds the
+// public keys of all the nodes
+func newTestSigners(t *testing.T, ids ...uint64) map[uint64]Signer {
+	publics := make(map[uint64]ed25519.PublicKey)
+	privates := make(map[uint64]ed25519.PrivateKey)
+	for _, id := range ids {
+		public, private, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publics[id] = public
+		privates[id] = private
+	}
+	signers := make(map[uint64]Signer)
+	for _, id := range ids {
+		signers[id] = NewEd25519Signer(privates[id], publics)
+	}
+	return signers
+}
+
+func TestSignOrderedLog(t *testing.T) {
+	signers := newTestSigners(t, 1, 2)
+
+	log := &pb.OrderedLog{ReplicaId: 1, Sequence: 3, TxHash: "tx", Timestamp: 7, HashAlgorithm: pb.HashAlgorithm_SHA256}
+	if err := SignOrderedLog(signers[1], log); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyOrderedLog(signers[2], log); err != nil {
+		t.Fatalf("valid log rejected: %s", err)
+	}
+	// the verification should leave the signature in place
+	if len(log.Signature) != ed25519.SignatureSize {
+		t.Fatalf("signature modified by verification: %d bytes", len(log.Signature))
+	}
+
+	tampered := *log
+	tampered.TxHash = "other"
+	if err := VerifyOrderedLog(signers[2], &tampered); err == nil {
+		t.Fatal("tampered log accepted")
+	}
+
+	forged := *log
+	forged.ReplicaId = 2
+	if err := VerifyOrderedLog(signers[2], &forged); err == nil {
+		t.Fatal("log claiming another replica accepted")
+	}
+
+	unknown := *log
+	unknown.ReplicaId = 3
+	if err := VerifyOrderedLog(signers[2], &unknown); err == nil {
+		t.Fatal("log of unknown replica accepted")
+	}
+
+	corrupted := *log
+	corrupted.Signature = append([]byte(nil), log.Signature...)
+	corrupted.Signature[0] ^= 0xff
+	if err := VerifyOrderedLog(signers[2], &corrupted); err == nil {
+		t.Fatal("log with corrupted signature accepted")
+	}
+
+	unsigned := *log
+	unsigned.Signature = nil
+	if err := VerifyOrderedLog(signers[2], &unsigned); err == nil {
+		t.Fatal("unsigned log accepted")
+	}
+}
+
+func TestSignOrderedReq(t *testing.T) {
+	signers := newTestSigners(t, 1, 100)
+
+	req := &pb.OrderedReq{ClientId: 100, Sequence: 1, TxHashList: []string{"a", "b"}, Timestamp: 9, HashAlgorithm: pb.HashAlgorithm_SHA256}
+	if err := SignOrderedReq(signers[100], req); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyOrderedReq(signers[1], req); err != nil {
+		t.Fatalf("valid request rejected: %s", err)
+	}
+
+	tampered := *req
+	tampered.TxHashList = []string{"b", "a"}
+	if err := VerifyOrderedReq(signers[1], &tampered); err == nil {
+		t.Fatal("reordered request accepted")
+	}
+
+	replayed := *req
+	replayed.Sequence = 2
+	if err := VerifyOrderedReq(signers[1], &replayed); err == nil {
+		t.Fatal("request with modified sequence accepted")
+	}
+
+	algorithm := *req
+	algorithm.HashAlgorithm = pb.HashAlgorithm_SHA3
+	if err := VerifyOrderedReq(signers[1], &algorithm); err == nil {
+		t.Fatal("request with modified hash algorithm accepted")
+	}
+
+	// a replica could not sign a request on behalf of the client
+	forged := *req
+	if err := SignOrderedReq(signers[1], &forged); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyOrderedReq(signers[100], &forged); err == nil {
+		t.Fatal("request signed by another node accepted")
+	}
+}
+
+// TestSignTxSet checks that the payloads could only be charged to the client which has signed them
+func TestSignTxSet(t *testing.T) {
+	signers := newTestSigners(t, 1, 2)
+
+	set := &pb.TxSet{ReplicaId: 1, Txs: []*pb.Transaction{{Payload: []byte("a")}, {Payload: []byte("b")}}}
+	if err := SignTxSet(signers[1], set); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyTxSet(signers[2], set); err != nil {
+		t.Fatalf("valid tx set rejected: %s", err)
+	}
+
+	forged := *set
+	forged.ReplicaId = 2
+	if err := VerifyTxSet(signers[2], &forged); err == nil {
+		t.Fatal("tx set claiming another client accepted")
+	}
+
+	appended := *set
+	appended.Txs = append(append([]*pb.Transaction{}, set.Txs...), &pb.Transaction{Payload: []byte("c")})
+	if err := VerifyTxSet(signers[2], &appended); err == nil {
+		t.Fatal("tx set with appended payload accepted")
+	}
+
+	unsigned := &pb.TxSet{ReplicaId: 1, Txs: set.Txs}
+	if err := VerifyTxSet(signers[2], unsigned); err == nil {
+		t.Fatal("unsigned tx set accepted")
+	}
+}
+
+func TestSignWithoutPrivateKey(t *testing.T) {
+	signer := NewEd25519Signer(nil, nil)
+	if err := SignOrderedLog(signer, &pb.OrderedLog{ReplicaId: 1}); err == nil {
+		t.Fatal("signed without private key")
+	}
+}
@@ -1,17 +1,74 @@
 package types
 
 import (
+	"time"
+
+	"github.com/Grivn/libfalanx/api"
 	"github.com/Grivn/libfalanx/logger"
 	"github.com/Grivn/libfalanx/network"
+	netType "github.com/Grivn/libfalanx/network/types"
 	"github.com/Grivn/libfalanx/zcommon"
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
 )
 
+// Config is used to initiate the falanx instance
+// N:         the amount of replicas in the initial epoch, whose identifiers are 1..N. a replica joining in a
+//            later epoch should be initiated with the replicas of that epoch
+// Executor:  the state machine of application, it could be nil if the application only subscribes the commits
+// CommitLen: the capacity of commit stream, DefaultChannelLen will be used if it is not positive
+// Hash:      the hash algorithm used to calculate the digests, all the replicas should select the same one
+//            and the messages with different algorithm will be rejected, DefaultHashAlgorithm will be
+//            used if it hasn't been specified
+// Signer:    the signer of current replica, it should hold the public keys of all the replicas and the clients
+//            whose requests are ordered by falanx, it is required
+// Receiver:  the messages delivered by transport into it will be processed by falanx, and the application
+//            could also deliver the messages with StepMessage if it hasn't been specified
+// DataDir:   the directory to persist the states of current replica, such as the write-ahead log of local
+//            order and the snapshot of filter, the persistence is disabled if it's empty
+// CheckpointInterval: the amount of executed batches between two checkpoints, the default one will be used
+//                     if it is not positive
+// Mempool:   the limits of the container for transaction payloads, the default ones will be used for the
+//            fields which are not positive
+// Clients:   the limits of the client order instances, which are created once the first request of a client
+//            has arrived, the default ones will be used for the fields which are not positive
 type Config struct {
-	ID     uint64
-	N      int
-	Sender network.Network
-	Tools  zcommon.Tools
-	Logger logger.Logger
+	ID                 uint64
+	N                  int
+	Sender             network.Network
+	Receiver           netType.NetworkReceiver
+	Executor           api.Executor
+	CommitLen          int
+	Hash               pb.HashAlgorithm
+	Signer             zcommon.Signer
+	DataDir            string
+	CheckpointInterval uint64
+	Mempool            MempoolConfig
+	Clients            ClientsConfig
+	Logger             logger.Logger
+}
+
+// MempoolConfig is used to bound the transactions kept by current replica
+// Capacity:    the maximum amount of transactions
+// ClientQuota: the maximum amount of transactions from one client
+// TTL:         the transactions which haven't been paved into any batch will be evicted after TTL
+type MempoolConfig struct {
+	Capacity    int
+	ClientQuota int
+	TTL         time.Duration
+}
+
+// ClientsConfig is used to bound the client order instances kept by current replica
+// MaxClients:  the maximum amount of clients served at the same time, the requests from the other clients
+//              will be rejected until some idle ones have been evicted
+// IdleTimeout: the instance of a client will be evicted once it hasn't received anything for IdleTimeout,
+//              and it will be resumed from the latest accepted request on the next request of the client
+// MaxEvicted:  the maximum amount of evicted clients whose latest accepted request is kept, the earliest
+//              evicted one will be forgotten once it has been reached, and its instance will be resumed from
+//              the first request issued after the forgotten ones on its next request
+type ClientsConfig struct {
+	MaxClients  int
+	IdleTimeout time.Duration
+	MaxEvicted  int
 }
 
 type Peer struct {
@@ -19,11 +76,116 @@ type Peer struct {
 	Hash string
 }
 
+// LocalBAEvent is used to report the replicas which haven't sent logs in time
+// Seq:     the batch which cannot be finalized without the logs from the missing replicas, the correct
+//          replicas will be blocked at the same batch, so that it's used as the agreed point to exclude them
+// Suspect: whether the missing replicas should be suspected as malicious ones, which is set when
+//          the replicas elected as candidates for some transaction have not ordered it
 type LocalBAEvent struct {
+	Seq             uint64
 	TxHash          string
 	MissingReplicas []uint64
+	Suspect         bool
+}
+
+// ExcludeEvent is used to post the replica excluded by local BA, the exclusion takes effect from batch Seq on
+// every replica, the ones which haven't reached it yet will hold the event until then.
+// ID:  the excluded replica
+// Seq: the batch which the votes or suspect messages of the agreement have been generated for
+type ExcludeEvent struct {
+	ID  uint64
+	Seq uint64
+}
+
+// GraphEvent is used to deliver a finalized relation graph from filter to graph engine
+// Seq:      the sequence number of the batch
+// Vertices: the transactions in current batch
+// Edges:    the relations between transactions in current batch
+// External: the relations preferring the transactions which haven't been finalized to the ones in current
+//           batch, the ones preferring the latter are implied by the order of finalization
+// Pending:  the pairs of transactions which haven't been finalized and the ones in current batch, whose
+//           relation cannot be determined until more logs arrive
+type GraphEvent struct {
+	Seq      uint64
+	Vertices []string
+	Edges    []Edge
+	External []Edge
+	Pending  []Pair
+}
+
+// Edge indicates the transaction 'From' has a higher priority than 'To'
+// Votes: the amount of replicas which have ordered 'From' before 'To'
+type Edge struct {
+	From  string
+	To    string
+	Votes int
+}
+
+// Pair indicates two transactions whose relation hasn't been determined, 'Former' is the one which hasn't
+// been finalized and 'Latter' is the finalized one
+type Pair struct {
+	Former string
+	Latter string
+}
+
+// ExecuteEvent is used to deliver a batch of executable transactions to executor
+// Seq:      the sequence number of the batch, the batches should be executed one by one. the ones posted by
+//           DAG manager carry the sequence number of finalized batch, and the executed ones are renumbered
+//           contiguously, which only differs from the former once some batches have been executed by state
+//           transfer or skipped
+// TxHashes: the ordered transactions' hash in current batch
+type ExecuteEvent struct {
+	Seq      uint64
+	TxHashes []string
+}
+
+// EpochEvent is used to switch the replica set once a reconfiguration has been agreed by the executed order
+// Epoch:    the sequence number of the new epoch
+// Seq:      the executed batch which completes the reconfiguration, the new epoch takes effect after it
+// Replicas: the replicas of the new epoch in ascending order
+type EpochEvent struct {
+	Epoch    uint64
+	Seq      uint64
+	Replicas []int
+}
+
+// TransferEvent is used to deliver the executed batches fetched from other replicas by state transfer
+// Seq:     the sequence number of the stable checkpoint which covers the batches
+// Batches: the batches following the latest one executed by current replica, in the order of sequence number
+type TransferEvent struct {
+	Seq     uint64
+	Batches []ExecuteEvent
+}
+
+// CommitEvent is used to notify the application of a committed batch
+// Seq:      the sequence number of the batch
+// TxHashes: the ordered transactions' hash in current batch
+// Txs:      the payloads of the transactions, Txs[i] is the payload of TxHashes[i], and it will be nil
+//           if current replica cannot find the payload
+type CommitEvent struct {
+	Seq      uint64
+	TxHashes []string
+	Txs      []*pb.Transaction
+}
+
+// CompleteEvent is used to notify the client of a transaction which has been executed, it is reported
+// once f+1 replicas have replied the same result, so that at least one correct replica has executed it
+type CompleteEvent struct {
+	TxHash string
+	Result []byte
 }
 
 const (
 	DefaultChannelLen = 1000
 	)
+
+// DefaultBlacklistBatches is the amount of batches a blacklisted replica is excluded from relating txs, it
+// will be readmitted after then, and it could be blacklisted once again if it's still suspected.
+const DefaultBlacklistBatches = 64
+
+// the file names of the persisted states in DataDir
+const (
+	LocalOrderWAL      = "localorder.wal"
+	FilterSnapshot     = "filter.snapshot"
+	CheckpointSnapshot = "checkpoint.snapshot"
+)
@@ -1,37 +1,69 @@
 package zcommon
 
 import (
-	"crypto/md5"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
+	"hash"
+
 	pb "github.com/Grivn/libfalanx/zcommon/protos"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
 )
 
+// DefaultHashAlgorithm is used when the hash algorithm hasn't been specified
+const DefaultHashAlgorithm = pb.HashAlgorithm_SHA256
+
 type Tools interface {
+	HashAlgorithm() pb.HashAlgorithm
 	TransactionHash(tx *pb.Transaction) string
-	CalculateMD5Hash(list []string, timestamp int64) string
+	CalculateHash(list []string, timestamp int64) string
+	CalculatePayloadHash(payload []byte, timestamp int64) string
+	Signer
 }
 
-type toolsImpl struct {}
+type toolsImpl struct {
+	algorithm pb.HashAlgorithm
+	Signer
+}
 
-func NewTools() *toolsImpl {return &toolsImpl{}}
+// NewTools is used to initiate the tools with the selected hash algorithm, the default one will be used
+// if it hasn't been specified, and it panics with an unsupported algorithm.
+func NewTools(algorithm pb.HashAlgorithm, signer Signer) *toolsImpl {
+	if algorithm == pb.HashAlgorithm_HASH_UNSPECIFIED {
+		algorithm = DefaultHashAlgorithm
+	}
+	if newHash(algorithm) == nil {
+		panic("unsupported hash algorithm!")
+	}
+	return &toolsImpl{algorithm: algorithm, Signer: signer}
+}
+
+func (t *toolsImpl) HashAlgorithm() pb.HashAlgorithm {
+	return t.algorithm
+}
 
 func (t *toolsImpl) TransactionHash(tx *pb.Transaction) string {
 	return t.transactionHash(tx)
 }
 
-func (t *toolsImpl) CalculateMD5Hash(list []string, timestamp int64) string {
-	return hex.EncodeToString(t.calculateMD5Hash(list, timestamp))
+func (t *toolsImpl) CalculateHash(list []string, timestamp int64) string {
+	return hex.EncodeToString(t.calculateHash(list, timestamp))
+}
+
+func (t *toolsImpl) CalculatePayloadHash(payload []byte, timestamp int64) string {
+	return CalculatePayloadHash(t.algorithm, payload, timestamp)
 }
 
 func (t *toolsImpl) transactionHash(tx *pb.Transaction) string {
 	payload, _ := tx.Marshal()
-	return CalculatePayloadHash(payload, 0)
+	return CalculatePayloadHash(t.algorithm, payload, 0)
 }
 
-// calculateMD5Hash calculate hash by MD5
-func (t *toolsImpl) calculateMD5Hash(list []string, timestamp int64) []byte {
-	h := md5.New()
+// calculateHash calculate hash of the list with the selected algorithm
+func (t *toolsImpl) calculateHash(list []string, timestamp int64) []byte {
+	h := newHash(t.algorithm)
 	for _, hash := range list {
 		_, _ = h.Write([]byte(hash))
 	}
@@ -43,8 +75,8 @@ func (t *toolsImpl) calculateMD5Hash(list []string, timestamp int64) []byte {
 	return h.Sum(nil)
 }
 
-func CalculatePayloadHash(payload []byte, timestamp int64) string {
-	h := md5.New()
+func CalculatePayloadHash(algorithm pb.HashAlgorithm, payload []byte, timestamp int64) string {
+	h := newHash(algorithm)
 	_, _ = h.Write(payload)
 
 	if timestamp > 0 {
@@ -53,4 +85,19 @@ func CalculatePayloadHash(payload []byte, timestamp int64) string {
 		_, _ = h.Write(b)
 	}
 	return hex.EncodeToString(h.Sum(nil))
-}
\ No newline at end of file
+}
+
+// newHash is used to create the hash instance of the algorithm, nil for the unsupported ones
+func newHash(algorithm pb.HashAlgorithm) hash.Hash {
+	switch algorithm {
+	case pb.HashAlgorithm_SHA256:
+		return sha256.New()
+	case pb.HashAlgorithm_BLAKE2B:
+		h, _ := blake2b.New256(nil)
+		return h
+	case pb.HashAlgorithm_SHA3:
+		return sha3.New256()
+	default:
+		return nil
+	}
+}
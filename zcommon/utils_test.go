@@ -0,0 +1,53 @@
+package zcommon
+
+import (
+	"encoding/hex"
+	"testing"
+
+	pb "github.com/Grivn/libfalanx/zcommon/protos"
+)
+
+// TestSelectHashAlgorithm checks that every supported algorithm could be selected, and the digests of the
+// same transaction calculated with different algorithms are distinguished
+func TestSelectHashAlgorithm(t *testing.T) {
+	tx := &pb.Transaction{Payload: []byte("payload")}
+	digests := make(map[string]pb.HashAlgorithm)
+	for _, algorithm := range []pb.HashAlgorithm{pb.HashAlgorithm_SHA256, pb.HashAlgorithm_BLAKE2B, pb.HashAlgorithm_SHA3} {
+		tools := NewTools(algorithm, nil)
+		if tools.HashAlgorithm() != algorithm {
+			t.Fatalf("select %s, got %s", algorithm, tools.HashAlgorithm())
+		}
+
+		digest := tools.TransactionHash(tx)
+		if raw, err := hex.DecodeString(digest); err != nil || len(raw) != 32 {
+			t.Fatalf("invalid %s digest %q", algorithm, digest)
+		}
+		if digest != tools.TransactionHash(tx) {
+			t.Fatalf("%s digest isn't stable", algorithm)
+		}
+		if other, ok := digests[digest]; ok {
+			t.Fatalf("%s digest is the same as %s", algorithm, other)
+		}
+		digests[digest] = algorithm
+
+		if tools.CalculatePayloadHash([]byte("payload"), 1) == tools.CalculatePayloadHash([]byte("payload"), 2) {
+			t.Fatalf("%s digest ignores the timestamp", algorithm)
+		}
+	}
+}
+
+func TestDefaultHashAlgorithm(t *testing.T) {
+	tools := NewTools(pb.HashAlgorithm_HASH_UNSPECIFIED, nil)
+	if tools.HashAlgorithm() != DefaultHashAlgorithm {
+		t.Fatalf("unspecified algorithm selects %s, expect %s", tools.HashAlgorithm(), DefaultHashAlgorithm)
+	}
+}
+
+func TestUnsupportedHashAlgorithm(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("unsupported algorithm accepted")
+		}
+	}()
+	NewTools(pb.HashAlgorithm(100), nil)
+}